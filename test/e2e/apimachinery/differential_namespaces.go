@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apimachinery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/test/e2e/framework"
+	e2eskipper "k8s.io/kubernetes/test/e2e/framework/skipper"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// Differential conformance for the namespace API: the probes below run in
+// lockstep against the primary cluster and the one behind
+// --secondary-kubeconfig, and the spec fails on any behavioral divergence.
+// Point the secondary kubeconfig at an upgrade-candidate control plane to get
+// a namespace-behavior diff for upgrade qualification.
+var _ = SIGDescribe("Namespace differential conformance [Feature:DifferentialConformance]", func() {
+	f := framework.NewDefaultFramework("nsdiff")
+	// Every probe provisions and removes its own namespaces on both
+	// clusters; the framework-managed namespace would only exist on the
+	// primary and skew the comparison.
+	f.SkipNamespaceCreation = true
+
+	ginkgo.It("should answer namespace API probes identically on both clusters", func() {
+		if !framework.SecondaryClusterConfigured() {
+			e2eskipper.Skipf("differential conformance requires --secondary-kubeconfig")
+		}
+
+		probes := []framework.DifferentialProbe{
+			{
+				Name: "namespace create/delete round-trip",
+				Run: func(c clientset.Interface) framework.DifferentialObservation {
+					name := "nsdiff-roundtrip-" + framework.RandomSuffix()
+					_, err := c.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{
+						ObjectMeta: metav1.ObjectMeta{Name: name},
+					}, metav1.CreateOptions{})
+					if err == nil {
+						err = framework.WaitForNamespaceInitialized(context.TODO(), c, name)
+					}
+					if err == nil {
+						err = c.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+					}
+					if err == nil {
+						err = framework.WaitForNamespacesDeleted(c, []string{name}, framework.ScaleTimeout(2*time.Minute))
+					}
+					return framework.DifferentialObservation{Outcome: framework.OutcomeForError(err)}
+				},
+			},
+			{
+				Name: "invalid namespace name rejection",
+				Run: func(c clientset.Interface) framework.DifferentialObservation {
+					_, err := c.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{
+						ObjectMeta: metav1.ObjectMeta{Name: "NSDIFF_Invalid_Name"},
+					}, metav1.CreateOptions{})
+					return framework.DifferentialObservation{Outcome: framework.OutcomeForError(err)}
+				},
+			},
+			{
+				Name: "duplicate namespace rejection",
+				Run: func(c clientset.Interface) framework.DifferentialObservation {
+					name := "nsdiff-duplicate-" + framework.RandomSuffix()
+					_, err := c.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{
+						ObjectMeta: metav1.ObjectMeta{Name: name},
+					}, metav1.CreateOptions{})
+					if err != nil {
+						return framework.DifferentialObservation{Outcome: framework.OutcomeForError(err)}
+					}
+					defer c.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+					_, err = c.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{
+						ObjectMeta: metav1.ObjectMeta{Name: name},
+					}, metav1.CreateOptions{})
+					return framework.DifferentialObservation{Outcome: framework.OutcomeForError(err)}
+				},
+			},
+			{
+				Name: "missing namespace GET",
+				Run: func(c clientset.Interface) framework.DifferentialObservation {
+					_, err := c.CoreV1().Namespaces().Get(context.TODO(), "nsdiff-does-not-exist", metav1.GetOptions{})
+					return framework.DifferentialObservation{Outcome: framework.OutcomeForError(err)}
+				},
+			},
+			{
+				Name: "missing namespace DELETE",
+				Run: func(c clientset.Interface) framework.DifferentialObservation {
+					err := c.CoreV1().Namespaces().Delete(context.TODO(), "nsdiff-does-not-exist", metav1.DeleteOptions{})
+					return framework.DifferentialObservation{Outcome: framework.OutcomeForError(err)}
+				},
+			},
+			{
+				Name: "default namespace phase and finalizers",
+				Run: func(c clientset.Interface) framework.DifferentialObservation {
+					ns, err := c.CoreV1().Namespaces().Get(context.TODO(), metav1.NamespaceDefault, metav1.GetOptions{})
+					if err != nil {
+						return framework.DifferentialObservation{Outcome: framework.OutcomeForError(err)}
+					}
+					finalizers := make([]string, 0, len(ns.Spec.Finalizers))
+					for _, finalizer := range ns.Spec.Finalizers {
+						finalizers = append(finalizers, string(finalizer))
+					}
+					return framework.DifferentialObservation{
+						Outcome: "success",
+						Detail:  fmt.Sprintf("phase=%s finalizers=%s", ns.Status.Phase, strings.Join(finalizers, ",")),
+					}
+				},
+			},
+		}
+
+		ginkgo.By("Running the namespace probes against both clusters in lockstep")
+		report := framework.RunDifferentialProbes(f.ClientSet, f.SecondaryClientSet, probes)
+		report.WriteArtifact("namespaces")
+
+		divergences := report.Divergences()
+		if len(divergences) > 0 {
+			framework.Failf("the clusters diverged on %d namespace probe(s):\n%s",
+				len(divergences), strings.Join(divergences, "\n"))
+		}
+	})
+})
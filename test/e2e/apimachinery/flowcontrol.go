@@ -358,6 +358,58 @@ func createFlowSchema(f *framework.Framework, flowSchemaName string, matchingPre
 	}
 }
 
+// createNamespaceResourceFlowSchema creates a flow schema referring to a
+// particular priority level and matching cluster-scoped resource requests
+// against the namespaces resource issued by the usernames provided. It is the
+// fixture used by tests verifying that APF protects the namespace lifecycle
+// path.
+func createNamespaceResourceFlowSchema(f *framework.Framework, flowSchemaName string, matchingPrecedence int32, priorityLevelName string, matchingUsernames []string) (*flowcontrol.FlowSchema, func()) {
+	var subjects []flowcontrol.Subject
+	for _, matchingUsername := range matchingUsernames {
+		subjects = append(subjects, flowcontrol.Subject{
+			Kind: flowcontrol.SubjectKindUser,
+			User: &flowcontrol.UserSubject{
+				Name: matchingUsername,
+			},
+		})
+	}
+
+	createdFlowSchema, err := f.ClientSet.FlowcontrolV1beta2().FlowSchemas().Create(
+		context.TODO(),
+		&flowcontrol.FlowSchema{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: flowSchemaName,
+			},
+			Spec: flowcontrol.FlowSchemaSpec{
+				MatchingPrecedence: matchingPrecedence,
+				PriorityLevelConfiguration: flowcontrol.PriorityLevelConfigurationReference{
+					Name: priorityLevelName,
+				},
+				DistinguisherMethod: &flowcontrol.FlowDistinguisherMethod{
+					Type: flowcontrol.FlowDistinguisherMethodByUserType,
+				},
+				Rules: []flowcontrol.PolicyRulesWithSubjects{
+					{
+						Subjects: subjects,
+						ResourceRules: []flowcontrol.ResourcePolicyRule{
+							{
+								Verbs:        []string{flowcontrol.VerbAll},
+								APIGroups:    []string{""},
+								Resources:    []string{"namespaces"},
+								ClusterScope: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		metav1.CreateOptions{})
+	framework.ExpectNoError(err)
+	return createdFlowSchema, func() {
+		framework.ExpectNoError(f.ClientSet.FlowcontrolV1beta2().FlowSchemas().Delete(context.TODO(), flowSchemaName, metav1.DeleteOptions{}))
+	}
+}
+
 // waitForSteadyState repeatedly polls the API server to check if the newly
 // created flow schema and priority level have been seen by the APF controller
 // by checking: (1) the dangling priority level reference condition in the flow
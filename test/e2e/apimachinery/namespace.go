@@ -24,122 +24,837 @@ import (
 	"sync"
 	"time"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/kubernetes/test/e2e/framework"
+	e2eauth "k8s.io/kubernetes/test/e2e/framework/auth"
+	e2edisruption "k8s.io/kubernetes/test/e2e/framework/disruption"
+	"k8s.io/kubernetes/test/e2e/framework/dynamicresource"
+	e2eevents "k8s.io/kubernetes/test/e2e/framework/events"
+	e2egc "k8s.io/kubernetes/test/e2e/framework/gc"
+	e2ematchers "k8s.io/kubernetes/test/e2e/framework/matchers"
+	e2emetrics "k8s.io/kubernetes/test/e2e/framework/metrics"
 	e2epod "k8s.io/kubernetes/test/e2e/framework/pod"
+	e2eservice "k8s.io/kubernetes/test/e2e/framework/service"
+	e2eskipper "k8s.io/kubernetes/test/e2e/framework/skipper"
+	"k8s.io/kubernetes/test/e2e/framework/webhookfixture"
+	"k8s.io/kubernetes/test/e2e/perftype"
 	imageutils "k8s.io/kubernetes/test/utils/image"
 	admissionapi "k8s.io/pod-security-admission/api"
+	"k8s.io/utils/pointer"
 
 	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// namespaceSoakBaseline captures the control-plane load signals a soak must
+// restore before handing the shared cluster to the next spec. Clusters where
+// the component metrics cannot be grabbed yield an invalid baseline, which
+// downgrades the cool-down assertion to a log line.
+type namespaceSoakBaseline struct {
+	queueDepth float64
+	inflight   float64
+	valid      bool
+}
+
+// Slack added on top of the captured baselines during cool-down: both
+// signals jitter under normal background traffic, so an exact restoration
+// check would be flaky even on an idle cluster.
+const (
+	soakQueueDepthSlack = 5
+	soakInflightSlack   = 20
+)
+
+// namespaceControllerQueueDepth reads the namespace controller's workqueue
+// depth from the controller-manager metrics.
+func namespaceControllerQueueDepth(f *framework.Framework) (float64, error) {
+	metrics, err := e2emetrics.GrabNamespaceControllerMetrics(f.ClientSet, f.ClientConfig())
+	if err != nil {
+		return 0, err
+	}
+	return metrics.QueueDepth, nil
+}
+
+// apiserverInflightRequests sums the apiserver's current inflight request
+// gauge over all request kinds.
+func apiserverInflightRequests(f *framework.Framework) (float64, error) {
+	grabber, err := e2emetrics.NewMetricsGrabber(f.ClientSet, nil, f.ClientConfig(), false, false, false, true, false, false)
+	if err != nil {
+		return 0, err
+	}
+	metrics, err := grabber.GrabFromAPIServer()
+	if err != nil {
+		return 0, err
+	}
+	samples := metrics["apiserver_current_inflight_requests"]
+	if len(samples) == 0 {
+		return 0, fmt.Errorf("no apiserver_current_inflight_requests samples found")
+	}
+	total := 0.0
+	for _, sample := range samples {
+		total += float64(sample.Value)
+	}
+	return total, nil
+}
+
+// captureNamespaceSoakBaseline snapshots the signals waitForNamespaceSoakBaseline
+// later compares against.
+func captureNamespaceSoakBaseline(f *framework.Framework) namespaceSoakBaseline {
+	queueDepth, err := namespaceControllerQueueDepth(f)
+	if err != nil {
+		framework.Logf("Cannot capture namespace controller queue depth for the soak baseline: %v", err)
+		return namespaceSoakBaseline{}
+	}
+	inflight, err := apiserverInflightRequests(f)
+	if err != nil {
+		framework.Logf("Cannot capture apiserver inflight requests for the soak baseline: %v", err)
+		return namespaceSoakBaseline{}
+	}
+	framework.Logf("Soak baseline: namespace controller queue depth %.0f, apiserver inflight requests %.0f", queueDepth, inflight)
+	return namespaceSoakBaseline{queueDepth: queueDepth, inflight: inflight, valid: true}
+}
+
+// waitForNamespaceSoakBaseline fails the spec if the control-plane load does
+// not return to the captured baseline (plus slack) within the timeout.
+func waitForNamespaceSoakBaseline(f *framework.Framework, baseline namespaceSoakBaseline, timeout time.Duration) {
+	if !baseline.valid {
+		framework.Logf("Skipping cool-down baseline check; no baseline was captured")
+		return
+	}
+	var lastQueueDepth, lastInflight float64
+	err := wait.Poll(10*time.Second, timeout, func() (bool, error) {
+		queueDepth, err := namespaceControllerQueueDepth(f)
+		if err != nil {
+			return false, err
+		}
+		inflight, err := apiserverInflightRequests(f)
+		if err != nil {
+			return false, err
+		}
+		lastQueueDepth, lastInflight = queueDepth, inflight
+		return queueDepth <= baseline.queueDepth+soakQueueDepthSlack &&
+			inflight <= baseline.inflight+soakInflightSlack, nil
+	})
+	if err != nil {
+		framework.Failf("control plane did not cool down to the pre-soak baseline within %v: queue depth %.0f (baseline %.0f), inflight requests %.0f (baseline %.0f): %v",
+			timeout, lastQueueDepth, baseline.queueDepth, lastInflight, baseline.inflight, err)
+	}
+	framework.Logf("Cool-down complete: queue depth %.0f, inflight requests %.0f", lastQueueDepth, lastInflight)
+}
+
 func extinguish(f *framework.Framework, totalNS int, maxAllowedAfterDel int, maxSeconds int) {
+	// The whole soak is namespace create/delete; a leased namespace pool
+	// cannot provide that.
+	e2eskipper.SkipUnlessNamespaceLifecycle()
+	// CI jobs may override the hard-coded soak parameters through the
+	// --namespace-stress-* flags to run larger or smaller soaks.
+	stress := framework.TestContext.NamespaceStress
+	if stress.TotalNamespaces > 0 {
+		totalNS = stress.TotalNamespaces
+	}
+	if stress.AllowedRemainder >= 0 {
+		maxAllowedAfterDel = stress.AllowedRemainder
+	}
+	if stress.Timeout > 0 {
+		maxSeconds = int(stress.Timeout.Seconds())
+	}
+
+	// Bail out up front on clusters that cannot absorb the soak, with a
+	// message naming the shortfall, rather than failing at the deadline.
+	e2eskipper.SkipUnlessClusterCanSupport(f.ClientSet, e2eskipper.ResourceBudget{
+		Namespaces: totalNS,
+		QPS:        2 * float64(totalNS),
+	})
+
+	// A small warm-up batch primes the apiserver caches and the namespace
+	// controller's informers so the measured soak doesn't pay first-touch
+	// costs, then the baseline is captured from the primed steady state.
+	ginkgo.By("Warming up with a small namespace batch")
+	warmupSelector := fmt.Sprintf("nslifetest-warmup=%s", framework.RunID)
+	_, err := f.CreateNamespaces(context.TODO(), 5, "nslifewarmup", framework.NamespaceBulkOptions{
+		Labels: map[string]string{"nslifetest-warmup": string(framework.RunID)},
+	})
+	framework.ExpectNoError(err, "failed to create warm-up namespaces")
+	warmedUp, err := framework.DeleteNamespacesByLabel(f.ClientSet, warmupSelector)
+	framework.ExpectNoError(err, "failed to delete warm-up namespaces")
+	framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, warmedUp, 2*time.Minute),
+		"warm-up namespaces did not finish terminating")
+	baseline := captureNamespaceSoakBaseline(f)
+
+	// Storage counts are snapshotted after the warm-up batch has drained, so
+	// the leak verdict below compares steady state against steady state.
+	storageCheck, err := e2emetrics.StartStorageLeakCheck(f.ClientSet, f.ClientConfig())
+	if err != nil {
+		framework.Logf("Cannot snapshot apiserver storage object counts, skipping the storage leak check: %v", err)
+		storageCheck = nil
+	}
+
+	// A run-unique label lets verification below use exact label-selector
+	// counts instead of substring matching on names.
+	nsLabels := map[string]string{"nslifetest": string(framework.RunID)}
+	labelSelector := fmt.Sprintf("nslifetest=%s", framework.RunID)
+
+	framework.WriteHeapProfile("nslifetest", "before")
+
 	ginkgo.By("Creating testing namespaces")
-	wg := &sync.WaitGroup{}
-	wg.Add(totalNS)
-	for n := 0; n < totalNS; n++ {
-		go func(n int) {
-			defer wg.Done()
+	createStart := time.Now()
+	nsNames, err := f.CreateNamespaces(context.TODO(), totalNS, "nslifetest", framework.NamespaceBulkOptions{
+		Parallelism: stress.CreationParallelism,
+		Labels:      nsLabels,
+		// Server-side naming keeps parallel soak runs against a shared
+		// cluster from racing on namespace names.
+		UseGenerateName: true,
+	})
+	framework.ExpectNoError(err, "failed to create testing namespaces")
+
+	// Wait until every namespace is Active, then SEND delete requests for all of them.
+	ginkgo.By("Waiting for all namespaces to be Active")
+	framework.ExpectNoError(framework.WaitAllNamespacesActive(f.ClientSet, nsNames, time.Minute),
+		"not all testing namespaces became Active")
+	// Creation throughput covers everything up to all namespaces being
+	// usable, since a namespace that exists but is not yet Active cannot
+	// absorb test traffic.
+	createElapsed := time.Since(createStart)
+
+	ginkgo.By("Verifying the exact number of labelled namespaces")
+	// The streaming list path is preferred here because this is the largest
+	// read of the soak; on servers without the capability the helper falls
+	// back to a chunked LIST on its own.
+	nsItems, streamed, err := framework.ListNamespacesViaWatchList(f.ClientSet, labelSelector, 30*time.Second)
+	framework.ExpectNoError(err, "failed to list namespaces matching %s", labelSelector)
+	framework.Logf("Listed %d namespaces matching %s (streamed: %t)", len(nsItems), labelSelector, streamed)
+	framework.ExpectEqual(len(nsItems), totalNS, "unexpected number of namespaces matching %s", labelSelector)
+
+	// Record resourceVersion checkpoints during the churn so that a failure
+	// below can be debugged by replaying the watch from just before it.
+	recorder := framework.StartRVCheckpointRecorder(f.ClientSet, 10*time.Second)
+	defer recorder.Stop()
+
+	deleteStart := time.Now()
+	results, err := framework.DeleteNamespacesWithMetrics(f.ClientSet, labelSelector, time.Duration(maxSeconds)*time.Second)
+	framework.ExpectNoError(err, "failed to delete namespaces matching %s", labelSelector)
+	framework.ExpectEqual(len(results), totalNS)
+
+	remaining := 0
+	latencies := make([]time.Duration, 0, len(results))
+	for _, result := range results {
+		if result.Removed() {
+			latencies = append(latencies, result.Duration())
+		} else {
+			remaining++
+		}
+	}
+	if remaining > maxAllowedAfterDel {
+		checkpoints := recorder.Stop()
+		recorder.WriteArtifact("nslifetest")
+		framework.ReplayNamespaceWatchFromCheckpoints(f.ClientSet, checkpoints, 30*time.Second)
+		var stuck []string
+		for _, result := range results {
+			if !result.Removed() {
+				stuck = append(stuck, result.Name)
+			}
+		}
+		framework.LogStuckNamespaceAnalysis(f.ClientSet, f.DynamicClient, stuck...)
+	}
+	// The verdict mode decides whether a missed deletion deadline fails the
+	// spec, is only reported, or just becomes a score in the artifacts.
+	framework.EnforceSLO(framework.SLOResult{
+		Name:      "namespace-deletion-remainder",
+		Measured:  float64(remaining),
+		Objective: float64(maxAllowedAfterDel),
+		Unit:      "namespaces",
+		Description: fmt.Sprintf("%d namespaces survived the %ds deletion deadline (max allowed: %d)",
+			remaining, maxSeconds, maxAllowedAfterDel),
+	})
+	if len(latencies) > 0 {
+		framework.Logf("Namespace deletion latency over %d namespaces: P50 %v, P90 %v",
+			len(latencies), framework.LatencyPercentile(latencies, 50), framework.LatencyPercentile(latencies, 90))
+	}
+	logNamespaceSoakPerfData(totalNS, createElapsed, totalNS-remaining, time.Since(deleteStart), latencies)
+
+	// Attach the controller's own view of the soak so slow deletions can be
+	// correlated with namespace controller backpressure, not just wall time.
+	if controllerMetrics, err := e2emetrics.GrabNamespaceControllerMetrics(f.ClientSet, f.ClientConfig()); err != nil {
+		framework.Logf("Cannot grab namespace controller metrics after the soak: %v", err)
+	} else {
+		framework.Logf("Namespace controller after the soak: %v", controllerMetrics)
+		ginkgo.AddReportEntry("namespace-controller-metrics", controllerMetrics.String())
+	}
+
+	framework.WriteHeapProfile("nslifetest", "after")
+
+	// Cool-down: the soak must leave the shared cluster the way it found it,
+	// or the queue it leaves behind skews whatever runs next.
+	ginkgo.By("Waiting for the control plane to cool down to the pre-soak baseline")
+	waitForNamespaceSoakBaseline(f, baseline, 5*time.Minute)
+
+	// With the API view empty and the control plane quiet, the storage
+	// gauges must be back where they started: LIST showing nothing proves
+	// the apiserver serves no objects, this proves etcd holds none. Each
+	// namespace the deletion deadline legitimately left behind still holds
+	// its auto-provisioned service account, token secret and root-CA
+	// configmap, so those resources get a matching allowance.
+	if storageCheck != nil {
+		ginkgo.By("Verifying apiserver storage object counts returned to the pre-soak baseline")
+		allowance := float64(remaining)
+		if err := storageCheck.Verify(map[string]float64{
+			"namespaces":      allowance,
+			"serviceaccounts": allowance,
+			"configmaps":      allowance,
+			"secrets":         allowance,
+		}); err != nil {
+			framework.Failf("the namespace soak leaked storage keys: %v", err)
+		}
+	}
+}
+
+// logNamespaceSoakPerfData prints the measured namespace creation and
+// deletion throughput plus latency percentiles in json format with
+// PerfResultTag prefix so perf dashboards can plot namespace controller
+// performance across releases.
+func logNamespaceSoakPerfData(createdNS int, createElapsed time.Duration, deletedNS int, elapsed time.Duration, latencies []time.Duration) {
+	if deletedNS <= 0 || elapsed <= 0 {
+		return
+	}
+	p := &perftype.PerfData{
+		Version: "v1",
+		DataItems: []perftype.DataItem{
+			{
+				Data:   map[string]float64{"rate": float64(deletedNS) / elapsed.Seconds()},
+				Unit:   "namespaces/s",
+				Labels: map[string]string{"datatype": "namespace-deletion"},
+			},
+			{
+				Data:   map[string]float64{"duration": elapsed.Seconds()},
+				Unit:   "s",
+				Labels: map[string]string{"datatype": "namespace-deletion-duration"},
+			},
+		},
+	}
+	if createdNS > 0 && createElapsed > 0 {
+		p.DataItems = append(p.DataItems, perftype.DataItem{
+			Data:   map[string]float64{"rate": float64(createdNS) / createElapsed.Seconds()},
+			Unit:   "namespaces/s",
+			Labels: map[string]string{"datatype": "namespace-creation"},
+		})
+	}
+	if len(latencies) > 0 {
+		p.DataItems = append(p.DataItems, perftype.DataItem{
+			Data: map[string]float64{
+				"Perc50": framework.LatencyPercentile(latencies, 50).Seconds(),
+				"Perc90": framework.LatencyPercentile(latencies, 90).Seconds(),
+				"Perc99": framework.LatencyPercentile(latencies, 99).Seconds(),
+			},
+			Unit:   "s",
+			Labels: map[string]string{"datatype": "namespace-deletion-latency"},
+		})
+	}
+	if str := framework.PrettyPrintJSON(p); str != "" {
+		framework.Logf("%s %s\n%s", perftype.PerfResultTag, str, perftype.PerfResultEnd)
+	}
+}
+
+// namespaceReadLoad describes an optional background read load to run against
+// the namespaces resource while extinguish churns namespaces, modelling fleets
+// where many controllers list and watch namespaces continuously.
+type namespaceReadLoad struct {
+	// watchers is the number of concurrent watches kept open on the namespaces resource.
+	watchers int
+	// listers is the number of goroutines issuing namespace LIST calls in a tight loop.
+	listers int
+	// maxListLatency is the read SLO; the slowest observed LIST must stay below it.
+	maxListLatency time.Duration
+}
+
+// namespaceReadLoadResult aggregates what the background readers observed.
+type namespaceReadLoadResult struct {
+	lists         int64
+	listErrors    int64
+	slowestList   time.Duration
+	watchRestarts int64
+
+	mu       sync.Mutex
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// startNamespaceReadLoad launches the configured watchers and listers. The
+// returned result must be stopped via stop() before its fields are inspected.
+func startNamespaceReadLoad(c clientset.Interface, load *namespaceReadLoad) *namespaceReadLoadResult {
+	result := &namespaceReadLoadResult{stopCh: make(chan struct{})}
+	for i := 0; i < load.watchers; i++ {
+		result.wg.Add(1)
+		go func() {
+			defer result.wg.Done()
 			defer ginkgo.GinkgoRecover()
-			ns := fmt.Sprintf("nslifetest-%v", n)
-			_, err := f.CreateNamespace(ns, nil)
-			framework.ExpectNoError(err, "failed to create namespace: %s", ns)
-		}(n)
-	}
-	wg.Wait()
-
-	//Wait 10 seconds, then SEND delete requests for all the namespaces.
-	ginkgo.By("Waiting 10 seconds")
-	time.Sleep(10 * time.Second)
-	deleteFilter := []string{"nslifetest"}
-	deleted, err := framework.DeleteNamespaces(f.ClientSet, deleteFilter, nil /* skipFilter */)
-	framework.ExpectNoError(err, "failed to delete namespace(s) containing: %s", deleteFilter)
-	framework.ExpectEqual(len(deleted), totalNS)
-
-	ginkgo.By("Waiting for namespaces to vanish")
-	//Now POLL until all namespaces have been eradicated.
-	framework.ExpectNoError(wait.Poll(2*time.Second, time.Duration(maxSeconds)*time.Second,
-		func() (bool, error) {
-			var cnt = 0
-			nsList, err := f.ClientSet.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
-			if err != nil {
-				return false, err
+			for {
+				w, err := c.CoreV1().Namespaces().Watch(context.TODO(), metav1.ListOptions{})
+				if err != nil {
+					select {
+					case <-result.stopCh:
+						return
+					case <-time.After(time.Second):
+						continue
+					}
+				}
+				func() {
+					defer w.Stop()
+					for {
+						select {
+						case <-result.stopCh:
+							return
+						case _, ok := <-w.ResultChan():
+							if !ok {
+								result.mu.Lock()
+								result.watchRestarts++
+								result.mu.Unlock()
+								return
+							}
+						}
+					}
+				}()
+				select {
+				case <-result.stopCh:
+					return
+				default:
+				}
 			}
-			for _, item := range nsList.Items {
-				if strings.Contains(item.Name, "nslifetest") {
-					cnt++
+		}()
+	}
+	for i := 0; i < load.listers; i++ {
+		result.wg.Add(1)
+		go func() {
+			defer result.wg.Done()
+			defer ginkgo.GinkgoRecover()
+			for {
+				select {
+				case <-result.stopCh:
+					return
+				default:
+				}
+				start := time.Now()
+				_, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+				elapsed := time.Since(start)
+				result.mu.Lock()
+				result.lists++
+				if err != nil {
+					result.listErrors++
 				}
+				if elapsed > result.slowestList {
+					result.slowestList = elapsed
+				}
+				result.mu.Unlock()
 			}
-			if cnt > maxAllowedAfterDel {
-				framework.Logf("Remaining namespaces : %v", cnt)
+		}()
+	}
+	return result
+}
+
+// stop terminates the background readers and waits for them to exit. It is
+// safe to call more than once.
+func (r *namespaceReadLoadResult) stop() {
+	r.stopOnce.Do(func() { close(r.stopCh) })
+	r.wg.Wait()
+}
+
+// extinguishWithReadLoad runs the namespace churn soak while the given read
+// load is applied, then asserts both the deletion SLO (via extinguish) and the
+// read SLO from the load configuration.
+func extinguishWithReadLoad(f *framework.Framework, totalNS int, maxAllowedAfterDel int, maxSeconds int, load *namespaceReadLoad) {
+	ginkgo.By(fmt.Sprintf("Starting background namespace read load (%d watchers, %d listers)", load.watchers, load.listers))
+	result := startNamespaceReadLoad(f.ClientSet, load)
+	defer result.stop()
+
+	extinguish(f, totalNS, maxAllowedAfterDel, maxSeconds)
+
+	result.stop()
+	framework.Logf("Read load summary: %d lists (%d errors), slowest list %v, %d watch restarts",
+		result.lists, result.listErrors, result.slowestList, result.watchRestarts)
+	framework.ExpectEqual(result.listErrors, int64(0), "background namespace LIST calls failed during churn")
+	framework.EnforceSLO(framework.SLOResult{
+		Name:      "namespace-list-latency-under-churn",
+		Measured:  result.slowestList.Seconds(),
+		Objective: load.maxListLatency.Seconds(),
+		Unit:      "s",
+		Description: fmt.Sprintf("slowest namespace LIST during churn took %v, violating the %v read SLO",
+			result.slowestList, load.maxListLatency),
+	})
+}
+
+// createNamespaceRetryOnTerminating creates the given namespace with a fixed
+// name, retrying while a previous instance of the same name is still
+// terminating. Any error other than AlreadyExists aborts the retry loop, so
+// the API must answer recreation attempts against a Terminating namespace
+// with a clean conflict for this helper to succeed.
+func createNamespaceRetryOnTerminating(c clientset.Interface, namespace *v1.Namespace, timeout time.Duration) (*v1.Namespace, error) {
+	var created *v1.Namespace
+	err := wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		got, err := c.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{})
+		if err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// The previous instance has not finished terminating yet.
 				return false, nil
 			}
-			return true, nil
-		}))
+			return false, err
+		}
+		created = got
+		return true, nil
+	})
+	return created, err
+}
+
+// ensureNamespaceRecreationStorm repeatedly deletes and recreates the same
+// namespace name with alternating content (pods vs services) without waiting
+// for a previous deletion to finish, then verifies the final namespace is
+// consistent and empty of test content.
+func ensureNamespaceRecreationStorm(f *framework.Framework, iterations int) {
+	namespaceName := "nsdeletetest"
+	framework.ClaimFixedNamespaceName(namespaceName)
+	for k := 0; k < iterations; k++ {
+		ginkgo.By(fmt.Sprintf("Recreating namespace %q (iteration %d)", namespaceName, k))
+		namespace, err := createNamespaceRetryOnTerminating(f.ClientSet, &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespaceName},
+		}, 5*time.Minute)
+		framework.ExpectNoError(err, "failed to (re)create namespace: %s", namespaceName)
+		f.AddNamespacesToDelete(namespace)
+
+		if k%2 == 0 {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "storm-pod"},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:  "pause",
+							Image: imageutils.GetPauseImageName(),
+						},
+					},
+				},
+			}
+			_, err = f.ClientSet.CoreV1().Pods(namespace.Name).Create(context.TODO(), pod, metav1.CreateOptions{})
+			framework.ExpectNoError(err, "failed to create pod in namespace: %s", namespace.Name)
+		} else {
+			service := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "storm-service"},
+				Spec: v1.ServiceSpec{
+					Selector: map[string]string{"storm": "true"},
+					Ports: []v1.ServicePort{{
+						Port:       80,
+						TargetPort: intstr.FromInt(80),
+					}},
+				},
+			}
+			_, err = f.ClientSet.CoreV1().Services(namespace.Name).Create(context.TODO(), service, metav1.CreateOptions{})
+			framework.ExpectNoError(err, "failed to create service in namespace: %s", namespace.Name)
+		}
+
+		// Send the delete without waiting for removal; the next iteration's
+		// create will race with this deletion on purpose.
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace: %s", namespace.Name)
+	}
+
+	ginkgo.By("Waiting for the final deletion to finish and verifying a clean recreation")
+	framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespaceName}, 5*time.Minute),
+		"namespace %s did not finish terminating after the recreation storm", namespaceName)
+	namespace, err := f.CreateNamespace(namespaceName, nil)
+	framework.ExpectNoError(err, "failed to create namespace: %s", namespaceName)
+	pods, err := f.ClientSet.CoreV1().Pods(namespace.Name).List(context.TODO(), metav1.ListOptions{})
+	framework.ExpectNoError(err, "failed to list pods in namespace: %s", namespace.Name)
+	framework.ExpectEqual(len(pods.Items), 0, "pods from a previous incarnation leaked into namespace %s", namespace.Name)
+	services, err := f.ClientSet.CoreV1().Services(namespace.Name).List(context.TODO(), metav1.ListOptions{})
+	framework.ExpectNoError(err, "failed to list services in namespace: %s", namespace.Name)
+	framework.ExpectEqual(len(services.Items), 0, "services from a previous incarnation leaked into namespace %s", namespace.Name)
+}
+
+// configMapRecreator is a deliberately naive controller fixture: it watches
+// configmaps in one namespace and blindly recreates any it sees deleted,
+// without checking whether the namespace is terminating. Real controllers
+// with this bug rely on the apiserver rejecting writes into a Terminating
+// namespace; the fixture counts how those rejections look from the client.
+type configMapRecreator struct {
+	attempts              int64
+	successfulRecreates   int64
+	terminatingRejections int64
+	unexpectedErrors      []error
+
+	mu       sync.Mutex
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// startConfigMapRecreator launches the fixture against the given namespace.
+// The returned recreator must be stopped via stop() before its counters are
+// inspected; it also exits on its own once the namespace is gone.
+func startConfigMapRecreator(c clientset.Interface, namespace string) *configMapRecreator {
+	r := &configMapRecreator{stopCh: make(chan struct{})}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer ginkgo.GinkgoRecover()
+		for {
+			w, err := c.CoreV1().ConfigMaps(namespace).Watch(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					// The namespace is gone; the fixture has nothing left to fight for.
+					return
+				}
+				select {
+				case <-r.stopCh:
+					return
+				case <-time.After(time.Second):
+					continue
+				}
+			}
+			if !r.processEvents(c, namespace, w) {
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// processEvents consumes one watch until it closes, recreating deleted
+// configmaps. It reports whether the watch should be re-established.
+func (r *configMapRecreator) processEvents(c clientset.Interface, namespace string, w watch.Interface) bool {
+	defer w.Stop()
+	for {
+		select {
+		case <-r.stopCh:
+			return false
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return true
+			}
+			if event.Type != watch.Deleted {
+				continue
+			}
+			deleted, ok := event.Object.(*v1.ConfigMap)
+			if !ok {
+				continue
+			}
+			recreate := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: deleted.Name},
+				Data:       deleted.Data,
+			}
+			_, err := c.CoreV1().ConfigMaps(namespace).Create(context.TODO(), recreate, metav1.CreateOptions{})
+			r.mu.Lock()
+			r.attempts++
+			switch {
+			case err == nil:
+				// The namespace controller will delete this one too; the next
+				// Deleted event closes the loop until rejections start.
+				r.successfulRecreates++
+			case apierrors.HasStatusCause(err, v1.NamespaceTerminatingCause):
+				r.terminatingRejections++
+			case apierrors.IsNotFound(err) || apierrors.IsAlreadyExists(err):
+				// Races with the namespace vanishing or with our own earlier
+				// recreate; neither is a protocol violation.
+			default:
+				r.unexpectedErrors = append(r.unexpectedErrors, err)
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// stop terminates the fixture and waits for it to exit. Safe to call more
+// than once.
+func (r *configMapRecreator) stop() {
+	r.stopOnce.Do(func() { close(r.stopCh) })
+	r.wg.Wait()
+}
+
+// apiserverForbiddenRequestCount sums the apiserver's request counter over
+// all 403 responses, used to cross-check client-observed terminating
+// rejections against server-side accounting.
+func apiserverForbiddenRequestCount(f *framework.Framework) (float64, error) {
+	grabber, err := e2emetrics.NewMetricsGrabber(f.ClientSet, nil, f.ClientConfig(), false, false, false, true, false, false)
+	if err != nil {
+		return 0, err
+	}
+	metrics, err := grabber.GrabFromAPIServer()
+	if err != nil {
+		return 0, err
+	}
+	total := 0.0
+	for _, sample := range metrics["apiserver_request_total"] {
+		if string(sample.Metric["code"]) == "403" {
+			total += float64(sample.Value)
+		}
+	}
+	return total, nil
+}
+
+// etcdStorageInUseBytes reports the logical etcd database size via the
+// apiserver's metrics endpoint. It tries the in-use metric first since that
+// one shrinks after compaction, falling back to the total size metrics.
+func etcdStorageInUseBytes(f *framework.Framework) (float64, error) {
+	grabber, err := e2emetrics.NewMetricsGrabber(f.ClientSet, nil, f.ClientConfig(), false, false, false, true, false, false)
+	if err != nil {
+		return 0, err
+	}
+	metrics, err := grabber.GrabFromAPIServer()
+	if err != nil {
+		return 0, err
+	}
+	for _, metricName := range []string{"etcd_db_total_size_in_use_in_bytes", "apiserver_storage_db_total_size_in_bytes", "etcd_db_total_size_in_bytes"} {
+		for _, sample := range metrics[metricName] {
+			return float64(sample.Value), nil
+		}
+	}
+	return 0, fmt.Errorf("no etcd database size metric found on the apiserver")
+}
+
+// applyEtcdWritePressure grows etcd storage by filling the namespace with
+// large configmaps.
+func applyEtcdWritePressure(f *framework.Framework, namespace string, count, valueBytes int) error {
+	payload := strings.Repeat("x", valueBytes)
+	return framework.RunParallel(count, 10, func(i int) error {
+		ballast := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("etcd-ballast-%d", i)},
+			Data:       map[string]string{"ballast": payload},
+		}
+		if _, err := f.ClientSet.CoreV1().ConfigMaps(namespace).Create(context.TODO(), ballast, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create ballast configmap %s: %v", ballast.Name, err)
+		}
+		return nil
+	})
+}
+
+// newTestPod returns the minimal pod the namespace tests fill their
+// namespaces with. The shared builder keeps it admissible on clusters that
+// enforce LimitRanges or restricted pod security by default.
+func newTestPod(name string) *v1.Pod {
+	return e2epod.NewMinimalPod(name)
+}
+
+var podGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// createPodWithSchedulingGates creates the pod with spec.schedulingGates set
+// through the dynamic client, since the vendored typed API predates the
+// field. It reports whether the server retained the gates; servers without
+// pod scheduling readiness silently drop the field.
+func createPodWithSchedulingGates(f *framework.Framework, namespace string, pod *v1.Pod, gates []string) (bool, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert pod %s to unstructured: %v", pod.Name, err)
+	}
+	gateObjs := make([]interface{}, 0, len(gates))
+	for _, gate := range gates {
+		gateObjs = append(gateObjs, map[string]interface{}{"name": gate})
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetAPIVersion("v1")
+	u.SetKind("Pod")
+	if err := unstructured.SetNestedSlice(u.Object, gateObjs, "spec", "schedulingGates"); err != nil {
+		return false, err
+	}
+	created, err := f.DynamicClient.Resource(podGVR).Namespace(namespace).Create(context.TODO(), u, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to create gated pod %s: %v", pod.Name, err)
+	}
+	retained, _, err := unstructured.NestedSlice(created.Object, "spec", "schedulingGates")
+	if err != nil {
+		return false, err
+	}
+	return len(retained) > 0, nil
+}
+
+// liftSchedulingGates removes all scheduling gates from the pod, releasing it
+// to the scheduler.
+func liftSchedulingGates(f *framework.Framework, namespace, podName string) error {
+	_, err := f.ClientSet.CoreV1().Pods(namespace).Patch(context.TODO(), podName, types.StrategicMergePatchType,
+		[]byte(`{"spec":{"schedulingGates":null}}`), metav1.PatchOptions{})
+	return err
 }
 
 func ensurePodsAreRemovedWhenNamespaceIsDeleted(f *framework.Framework) {
+	e2eskipper.SkipUnlessNamespaceLifecycle()
+	e2eskipper.SkipUnlessProviderHasNodes()
 	ginkgo.By("Creating a test namespace")
 	namespaceName := "nsdeletetest"
 	namespace, err := f.CreateNamespace(namespaceName, nil)
 	framework.ExpectNoError(err, "failed to create namespace: %s", namespaceName)
 
 	ginkgo.By("Waiting for a default service account to be provisioned in namespace")
-	err = framework.WaitForDefaultServiceAccountInNamespace(f.ClientSet, namespace.Name)
+	err = framework.WaitForDefaultServiceAccountInNamespaceWithContext(f.SpecContext(), f.ClientSet, namespace.Name)
 	framework.ExpectNoError(err, "failure while waiting for a default service account to be provisioned in namespace: %s", namespace.Name)
 
-	ginkgo.By("Creating a pod in the namespace")
-	podName := "test-pod"
-	pod := &v1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: podName,
-		},
-		Spec: v1.PodSpec{
-			Containers: []v1.Container{
-				{
-					Name:  "nginx",
-					Image: imageutils.GetPauseImageName(),
-				},
-			},
-		},
+	// Teardown must behave the same for every QoS class, so the namespace
+	// holds one pod per class when it is deleted.
+	ginkgo.By("Creating one pod per QoS class in the namespace")
+	qosVariants := []v1.PodQOSClass{v1.PodQOSGuaranteed, v1.PodQOSBurstable, v1.PodQOSBestEffort}
+	var pods []*v1.Pod
+	for _, qos := range qosVariants {
+		podName := "test-pod-" + strings.ToLower(string(qos))
+		pod := e2epod.NewPodWithQOS(podName, qos)
+		pod.Labels = map[string]string{"nsdeletetest": string(framework.RunID)}
+		pod, err = f.ClientSet.CoreV1().Pods(namespace.Name).Create(context.TODO(), pod, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create %s pod %s in namespace: %s", qos, podName, namespace.Name)
+		pods = append(pods, pod)
 	}
-	pod, err = f.ClientSet.CoreV1().Pods(namespace.Name).Create(context.TODO(), pod, metav1.CreateOptions{})
-	framework.ExpectNoError(err, "failed to create pod %s in namespace: %s", podName, namespace.Name)
 
-	ginkgo.By("Waiting for the pod to have running status")
-	framework.ExpectNoError(e2epod.WaitForPodRunningInNamespace(f.ClientSet, pod))
+	ginkgo.By("Waiting for the pods to have running status")
+	var maxGracePeriod int64
+	for i, pod := range pods {
+		framework.ExpectNoError(e2epod.WaitForPodRunningInNamespaceWithContext(f.SpecContext(), f.ClientSet, pod))
+		running, err := f.ClientSet.CoreV1().Pods(namespace.Name).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to get pod %s in namespace: %s", pod.Name, namespace.Name)
+		framework.ExpectEqual(running.Status.QOSClass, qosVariants[i],
+			"pod %s landed in an unexpected QoS class", pod.Name)
+		if grace := pod.Spec.TerminationGracePeriodSeconds; grace != nil && *grace > maxGracePeriod {
+			maxGracePeriod = *grace
+		}
+	}
 
 	ginkgo.By("Deleting the namespace")
 	err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
 	framework.ExpectNoError(err, "failed to delete namespace: %s", namespace.Name)
 
 	ginkgo.By("Waiting for the namespace to be removed.")
-	maxWaitSeconds := int64(60) + *pod.Spec.TerminationGracePeriodSeconds
-	framework.ExpectNoError(wait.Poll(1*time.Second, time.Duration(maxWaitSeconds)*time.Second,
-		func() (bool, error) {
-			_, err = f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
-			if err != nil && apierrors.IsNotFound(err) {
-				return true, nil
-			}
-			return false, nil
-		}))
+	maxWaitSeconds := int64(60) + maxGracePeriod
+	lastNamespace, err := framework.WaitForNamespaceTerminated(f.SpecContext(), f.ClientSet, namespace.Name, framework.ScaleTimeout(time.Duration(maxWaitSeconds)*time.Second))
+	framework.ExpectNoErrorWithObject(err, lastNamespace, "failed waiting for namespace %s to be removed", namespace.Name)
 
 	ginkgo.By("Recreating the namespace")
 	namespace, err = f.CreateNamespace(namespaceName, nil)
 	framework.ExpectNoError(err, "failed to create namespace: %s", namespaceName)
 
 	ginkgo.By("Verifying there are no pods in the namespace")
-	_, err = f.ClientSet.CoreV1().Pods(namespace.Name).Get(context.TODO(), pod.Name, metav1.GetOptions{})
-	framework.ExpectError(err, "failed to get pod %s in namespace: %s", pod.Name, namespace.Name)
+	for _, pod := range pods {
+		_, err = f.ClientSet.CoreV1().Pods(namespace.Name).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+		framework.ExpectError(err, "%s pod %s survived namespace deletion", pod.Status.QOSClass, pod.Name)
+	}
+
+	ginkgo.By("Verifying no other content survived into the recreated namespace")
+	framework.ExpectNoError(framework.VerifyNamespaceEmpty(f.ClientSet, f.DynamicClient, namespace.Name),
+		"content of the deleted namespace leaked into its recreation")
+
+	ginkgo.By("Verifying the pods were not recreated in any other namespace")
+	framework.ExpectNoObjectsClusterWide(f.DynamicClient,
+		[]schema.GroupVersionResource{{Version: "v1", Resource: "pods"}},
+		fmt.Sprintf("nsdeletetest=%s", framework.RunID))
 }
 
 func ensureServicesAreRemovedWhenNamespaceIsDeleted(f *framework.Framework) {
+	e2eskipper.SkipUnlessNamespaceLifecycle()
+	e2eskipper.SkipUnlessProviderHasNodes()
 	var err error
 
 	ginkgo.By("Creating a test namespace")
@@ -148,29 +863,17 @@ func ensureServicesAreRemovedWhenNamespaceIsDeleted(f *framework.Framework) {
 	framework.ExpectNoError(err, "failed to create namespace: %s", namespaceName)
 
 	ginkgo.By("Waiting for a default service account to be provisioned in namespace")
-	err = framework.WaitForDefaultServiceAccountInNamespace(f.ClientSet, namespace.Name)
+	err = framework.WaitForDefaultServiceAccountInNamespaceWithContext(f.SpecContext(), f.ClientSet, namespace.Name)
 	framework.ExpectNoError(err, "failure while waiting for a default service account to be provisioned in namespace: %s", namespace.Name)
 
-	ginkgo.By("Creating a service in the namespace")
+	ginkgo.By("Creating a service with a running backend in the namespace")
 	serviceName := "test-service"
-	labels := map[string]string{
-		"foo": "bar",
-		"baz": "blah",
-	}
-	service := &v1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: serviceName,
-		},
-		Spec: v1.ServiceSpec{
-			Selector: labels,
-			Ports: []v1.ServicePort{{
-				Port:       80,
-				TargetPort: intstr.FromInt(80),
-			}},
-		},
-	}
-	service, err = f.ClientSet.CoreV1().Services(namespace.Name).Create(context.TODO(), service, metav1.CreateOptions{})
-	framework.ExpectNoError(err, "failed to create service %s in namespace %s", serviceName, namespace.Name)
+	served, err := e2eservice.CreateServedService(f.ClientSet, namespace.Name, serviceName)
+	framework.ExpectNoError(err, "failed to create served service %s in namespace %s", serviceName, namespace.Name)
+
+	ginkgo.By("Verifying the service answers traffic before the namespace is deleted")
+	framework.ExpectNoError(served.WaitUntilServing(2*time.Minute),
+		"service %s in namespace %s never served traffic, so its removal would prove nothing", serviceName, namespace.Name)
 
 	ginkgo.By("Deleting the namespace")
 	err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
@@ -178,22 +881,20 @@ func ensureServicesAreRemovedWhenNamespaceIsDeleted(f *framework.Framework) {
 
 	ginkgo.By("Waiting for the namespace to be removed.")
 	maxWaitSeconds := int64(60)
-	framework.ExpectNoError(wait.Poll(1*time.Second, time.Duration(maxWaitSeconds)*time.Second,
-		func() (bool, error) {
-			_, err = f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
-			if err != nil && apierrors.IsNotFound(err) {
-				return true, nil
-			}
-			return false, nil
-		}))
+	lastNamespace, err := framework.WaitForNamespaceTerminated(f.SpecContext(), f.ClientSet, namespace.Name, framework.ScaleTimeout(time.Duration(maxWaitSeconds)*time.Second))
+	framework.ExpectNoErrorWithObject(err, lastNamespace, "failed waiting for namespace %s to be removed", namespace.Name)
 
 	ginkgo.By("Recreating the namespace")
 	namespace, err = f.CreateNamespace(namespaceName, nil)
 	framework.ExpectNoError(err, "failed to create namespace: %s", namespaceName)
 
 	ginkgo.By("Verifying there is no service in the namespace")
-	_, err = f.ClientSet.CoreV1().Services(namespace.Name).Get(context.TODO(), service.Name, metav1.GetOptions{})
-	framework.ExpectError(err, "failed to get service %s in namespace: %s", service.Name, namespace.Name)
+	_, err = f.ClientSet.CoreV1().Services(namespace.Name).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	framework.ExpectError(err, "failed to get service %s in namespace: %s", serviceName, namespace.Name)
+
+	ginkgo.By("Verifying no other content survived into the recreated namespace")
+	framework.ExpectNoError(framework.VerifyNamespaceEmpty(f.ClientSet, f.DynamicClient, namespace.Name),
+		"content of the deleted namespace leaked into its recreation")
 }
 
 // This test must run [Serial] due to the impact of running other parallel
@@ -222,13 +923,45 @@ func ensureServicesAreRemovedWhenNamespaceIsDeleted(f *framework.Framework) {
 // Namespace creation, and soak Namespace deletion, its not appropriate to
 // further soak the cluster with other parallel Namespace deletion activities
 // that each have a variable amount of content in the associated Namespace.
-// When run in [Serial] this test appears to delete Namespace objects at a
-// rate of approximately 1 per second.
+// The measured creation and deletion throughput and latency percentiles are
+// emitted as perftype.PerfData JSON (see logNamespaceSoakPerfData), so the
+// actual rates can be read from the test output instead of estimated.
 var _ = SIGDescribe("Namespaces [Serial]", func() {
 
 	f := framework.NewDefaultFramework("namespaces")
 	f.NamespacePodSecurityEnforceLevel = admissionapi.LevelBaseline
 
+	ginkgo.BeforeEach(func() {
+		// Whatever the spec did, no pods may survive the deletion of the
+		// namespaces it created.
+		f.AddNamespaceTeardownCheck(func(f *framework.Framework, namespace string) error {
+			pods, err := f.ClientSet.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to list pods in deleted namespace %s: %v", namespace, err)
+			}
+			if len(pods.Items) > 0 {
+				return fmt.Errorf("%d pods still present in deleted namespace %s", len(pods.Items), namespace)
+			}
+			return nil
+		})
+
+		// Namespace teardown must only ever issue namespaced delete calls; a
+		// deleteCollection escaping to cluster scope has destroyed foreign
+		// objects before. Guarded via the apiserver's request counters, so
+		// the check works regardless of which client misbehaves.
+		guard, err := e2emetrics.StartClusterScopedDeleteCollectionGuard(f.ClientSet, f.ClientConfig())
+		if err != nil {
+			framework.Logf("Skipping the cluster-scoped deleteCollection guard: %v", err)
+			return
+		}
+		f.AddNamespaceTeardownCheck(func(f *framework.Framework, namespace string) error {
+			if err := guard.Verify(); err != nil {
+				return fmt.Errorf("while namespace %s was deleted: %v", namespace, err)
+			}
+			return nil
+		})
+	})
+
 	/*
 		Release: v1.11
 		Testname: namespace-deletion-removes-pods
@@ -245,6 +978,25 @@ var _ = SIGDescribe("Namespaces [Serial]", func() {
 	framework.ConformanceIt("should ensure that all services are removed when a namespace is deleted",
 		func() { ensureServicesAreRemovedWhenNamespaceIsDeleted(f) })
 
+	ginkgo.It("should provision the default service account with low P99 latency over 50 namespace creations [Feature:ServiceAccountProvisioningLatency]", func() {
+		const total = 50
+		latencies := make([]time.Duration, 0, total)
+		for i := 0; i < total; i++ {
+			namespace, err := f.CreateNamespace("nssalatency", nil)
+			framework.ExpectNoError(err, "failed to create namespace %d of %d", i+1, total)
+			start := time.Now()
+			err = framework.WaitForDefaultServiceAccountInNamespaceWithContext(f.SpecContext(), f.ClientSet, namespace.Name)
+			framework.ExpectNoError(err, "default service account was never provisioned in namespace %s", namespace.Name)
+			latencies = append(latencies, time.Since(start))
+		}
+		p50 := framework.LatencyPercentile(latencies, 50)
+		p99 := framework.LatencyPercentile(latencies, 99)
+		framework.Logf("Default service account provisioning latency over %d creations: P50 %v, P99 %v", total, p50, p99)
+		if threshold := framework.TestContext.ServiceAccountProvisioningThreshold; threshold > 0 && p99 > threshold {
+			framework.Failf("P99 service account provisioning latency %v exceeded the threshold of %v", p99, threshold)
+		}
+	})
+
 	ginkgo.It("should delete fast enough (90 percent of 100 namespaces in 150 seconds)",
 		func() { extinguish(f, 100, 10, 150) })
 
@@ -252,34 +1004,2322 @@ var _ = SIGDescribe("Namespaces [Serial]", func() {
 	ginkgo.It("should always delete fast (ALL of 100 namespaces in 150 seconds) [Feature:ComprehensiveNamespaceDraining]",
 		func() { extinguish(f, 100, 0, 150) })
 
-	/*
-	   Release: v1.18
-	   Testname: Namespace patching
-	   Description: A Namespace is created.
-	   The Namespace is patched.
-	   The Namespace and MUST now include the new Label.
-	*/
-	framework.ConformanceIt("should patch a Namespace", func() {
-		ginkgo.By("creating a Namespace")
-		namespaceName := "nspatchtest-" + string(uuid.NewUUID())
-		ns, err := f.CreateNamespace(namespaceName, nil)
-		framework.ExpectNoError(err, "failed creating Namespace")
-		namespaceName = ns.ObjectMeta.Name
+	ginkgo.It("should still delete all of 100 namespaces while the controller-manager restarts mid-drain [Feature:NamespaceDrainDisruption] [Serial] [Disruptive]", func() {
+		if !e2edisruption.Supported() {
+			e2eskipper.Skipf("control plane disruption is not supported on provider %q", framework.TestContext.Provider)
+		}
+		restartDone := make(chan struct{})
+		go func() {
+			defer ginkgo.GinkgoRecover()
+			defer close(restartDone)
+			// Strike only once the drain has actually begun: the soak labels
+			// its namespaces with the run ID, so the first Terminating one
+			// marks the start of the drain.
+			selector := fmt.Sprintf("nslifetest=%s", framework.RunID)
+			err := wait.PollImmediate(2*time.Second, 10*time.Minute, func() (bool, error) {
+				list, err := f.ClientSet.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+				if err != nil {
+					return false, nil
+				}
+				for i := range list.Items {
+					if list.Items[i].DeletionTimestamp != nil {
+						return true, nil
+					}
+				}
+				return false, nil
+			})
+			if err != nil {
+				framework.Logf("Never saw a terminating soak namespace, skipping the mid-drain restart: %v", err)
+				return
+			}
+			before := time.Now()
+			framework.Logf("Restarting the controller-manager mid-drain")
+			framework.ExpectNoError(e2edisruption.RestartControllerManager(),
+				"failed to restart the controller-manager mid-drain")
+			framework.ExpectNoError(e2edisruption.WaitForControllerManagerHealthy(f.ClientSet, before, 3*time.Minute),
+				"controller-manager never renewed its lease after the mid-drain restart")
+		}()
+		// The deadline is looser than the undisrupted soak to absorb the
+		// controller-manager outage, but every namespace must still vanish.
+		extinguish(f, 100, 0, 300)
+		<-restartDone
+	})
 
-		ginkgo.By("patching the Namespace")
-		nspatch, err := json.Marshal(map[string]interface{}{
-			"metadata": map[string]interface{}{
-				"labels": map[string]string{"testLabel": "testValue"},
-			},
+	ginkgo.It("should survive repeated delete and recreate of the same namespace name with alternating content [Feature:NamespaceRecreationStorm]",
+		func() { ensureNamespaceRecreationStorm(f, 4) })
+
+	ginkgo.It("should handle concurrent delete requests for the same namespace idempotently", func() {
+		const concurrentDeletes = 10
+
+		ginkgo.By("Creating a test namespace with content")
+		namespace, err := f.CreateNamespace("nsconcurrentdelete", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(),
+			&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "concurrent-delete"}}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create configmap in namespace %s", namespace.Name)
+
+		ginkgo.By(fmt.Sprintf("Firing %d concurrent delete requests", concurrentDeletes))
+		errs := make([]error, concurrentDeletes)
+		var wg sync.WaitGroup
+		for i := 0; i < concurrentDeletes; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer ginkgo.GinkgoRecover()
+				errs[i] = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+			}(i)
+		}
+		wg.Wait()
+		succeeded := 0
+		for i, err := range errs {
+			switch {
+			case err == nil:
+				succeeded++
+			case apierrors.IsNotFound(err) || apierrors.IsConflict(err):
+				// Acceptable outcomes for a racing delete.
+			default:
+				framework.Failf("concurrent delete %d failed with an unexpected error: %v", i, err)
+			}
+		}
+		if succeeded == 0 {
+			framework.Failf("none of the %d concurrent delete requests succeeded", concurrentDeletes)
+		}
+
+		ginkgo.By("Verifying only one deletion orchestration was started")
+		// The deletionTimestamp is set exactly once; repeated deletes against
+		// the terminating namespace must neither move it nor recreate the
+		// object under a new UID.
+		ns, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+		if err == nil {
+			framework.ExpectEqual(ns.UID, namespace.UID, "concurrent deletes produced a different incarnation of namespace %s", namespace.Name)
+			gomega.Expect(ns.DeletionTimestamp).NotTo(gomega.BeNil(), "namespace %s is not terminating after successful deletes", namespace.Name)
+			observedDeletionTimestamp := *ns.DeletionTimestamp
+			err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				framework.Failf("delete of the already-terminating namespace %s failed: %v", namespace.Name, err)
+			}
+			ns, err = f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+			if err == nil {
+				framework.ExpectEqual(ns.DeletionTimestamp.Equal(&observedDeletionTimestamp), true,
+					"repeated delete moved the deletionTimestamp of namespace %s", namespace.Name)
+			} else if !apierrors.IsNotFound(err) {
+				framework.ExpectNoError(err, "failed to re-read terminating namespace %s", namespace.Name)
+			}
+		} else if !apierrors.IsNotFound(err) {
+			framework.ExpectNoError(err, "failed to read namespace %s after concurrent deletes", namespace.Name)
+		}
+
+		ginkgo.By("Waiting for the namespace to be removed")
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s did not finish terminating after concurrent deletes", namespace.Name)
+	})
+
+	ginkgo.It("should block foreground namespace deletion until owner-referenced dependents are finalized", func() {
+		ginkgo.By("Creating a test namespace with a dependent owner-referenced by it")
+		namespace, err := f.CreateNamespace("nsforeground", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		const dependentFinalizer = "e2e.k8s.io/foreground-block"
+		blockOwnerDeletion := true
+		configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:       "foreground-dependent",
+			Finalizers: []string{dependentFinalizer},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "v1",
+				Kind:               "Namespace",
+				Name:               namespace.Name,
+				UID:                namespace.UID,
+				BlockOwnerDeletion: &blockOwnerDeletion,
+			}},
+		}}
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(), configMap, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create dependent configmap in namespace %s", namespace.Name)
+		defer func() {
+			// The finalizer must never outlive the spec.
+			_, err := f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Patch(context.TODO(), configMap.Name,
+				types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				framework.Logf("Failed to strip finalizer from configmap %s: %v", configMap.Name, err)
+			}
+		}()
+
+		ginkgo.By("Verifying a delete with a mismatched UID precondition is rejected")
+		wrongUID := types.UID("e2e-mismatched-uid")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &wrongUID},
 		})
-		framework.ExpectNoError(err, "failed to marshal JSON patch data")
-		_, err = f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), namespaceName, types.StrategicMergePatchType, nspatch, metav1.PatchOptions{})
-		framework.ExpectNoError(err, "failed to patch Namespace")
+		framework.ExpectEqual(apierrors.IsConflict(err), true,
+			"delete of namespace %s with a mismatched UID precondition returned %v instead of a conflict", namespace.Name, err)
 
-		ginkgo.By("get the Namespace and ensuring it has the label")
-		namespace, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespaceName, metav1.GetOptions{})
-		framework.ExpectNoError(err, "failed to get Namespace")
-		framework.ExpectEqual(namespace.ObjectMeta.Labels["testLabel"], "testValue", "namespace not patched")
+		ginkgo.By("Deleting the namespace in the foreground with the correct UID through the bulk helper")
+		propagation := metav1.DeletePropagationForeground
+		deleted, err := framework.DeleteNamespacesWithOptions(context.TODO(), f.ClientSet, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+			Preconditions:     &metav1.Preconditions{UID: &namespace.UID},
+		}, []string{namespace.Name}, nil)
+		framework.ExpectNoError(err, "failed to delete namespace %s in the foreground", namespace.Name)
+		framework.ExpectEqual(len(deleted), 1, "the delete filter matched more than the test namespace")
+
+		ginkgo.By("Verifying the namespace object is retained while the dependent is unfinalized")
+		sawForegroundFinalizer := false
+		deadline := time.Now().Add(20 * time.Second)
+		for time.Now().Before(deadline) {
+			ns, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				framework.Failf("namespace %s was removed in the foreground while its dependent still carried finalizer %s", namespace.Name, dependentFinalizer)
+			}
+			framework.ExpectNoError(err, "failed to get foreground-deleted namespace %s", namespace.Name)
+			for _, finalizer := range ns.Finalizers {
+				if finalizer == metav1.FinalizerDeleteDependents {
+					sawForegroundFinalizer = true
+				}
+			}
+			time.Sleep(2 * time.Second)
+		}
+		if !sawForegroundFinalizer {
+			framework.Failf("namespace %s never carried the %s finalizer despite foreground propagation", namespace.Name, metav1.FinalizerDeleteDependents)
+		}
+
+		ginkgo.By("Finalizing the dependent and waiting for the namespace to go away")
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Patch(context.TODO(), configMap.Name,
+			types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to clear finalizer from dependent configmap %s", configMap.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s did not finish foreground deletion after its dependent was finalized", namespace.Name)
+	})
+
+	ginkgo.It("should finalize dependents with dangling or cross-namespace owner references during namespace deletion", func() {
+		e2eskipper.SkipUnlessNamespaceLifecycle()
+
+		ginkgo.By("Creating a namespace for the dependents and one holding a foreign owner")
+		namespace, err := f.CreateNamespace("nsgcdangling", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		otherNamespace, err := f.CreateNamespace("nsgcowner", nil)
+		framework.ExpectNoError(err, "failed to create namespace for the foreign owner")
+
+		ginkgo.By("Verifying the garbage collector cascades a healthy chain in the namespace")
+		chain, err := e2egc.CreateChain(f.ClientSet, namespace.Name, "healthy", 2)
+		framework.ExpectNoError(err, "failed to create owner chain in namespace %s", namespace.Name)
+		framework.ExpectNoError(chain.DeleteOwner(e2egc.BackgroundDeleteOptions()),
+			"failed to delete the owner of the healthy chain")
+		framework.ExpectNoError(chain.WaitForDependentsDeleted(2*time.Minute),
+			"garbage collector never removed the dependents of the healthy chain; GC is not working, so the dangling-reference check would be meaningless")
+
+		ginkgo.By("Creating dependents whose owner references cannot resolve")
+		foreignOwner, err := f.ClientSet.CoreV1().ConfigMaps(otherNamespace.Name).Create(context.TODO(),
+			&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foreign-owner"}}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create the foreign owner in namespace %s", otherNamespace.Name)
+		_, err = e2egc.CreateDependentConfigMap(f.ClientSet, namespace.Name, "dangling-dependent",
+			e2egc.DanglingOwnerReference("ConfigMap", "no-such-owner"))
+		framework.ExpectNoError(err, "failed to create the dependent with a dangling owner reference")
+		// Owner references are resolved in the dependent's own namespace, so a
+		// reference to an object living elsewhere is just as unresolvable.
+		_, err = e2egc.CreateDependentSecret(f.ClientSet, namespace.Name, "cross-namespace-dependent",
+			e2egc.OwnerReferenceTo(foreignOwner))
+		framework.ExpectNoError(err, "failed to create the dependent referencing an owner from another namespace")
+
+		ginkgo.By("Deleting the namespace and waiting for it to vanish")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s holding dependents with unresolvable owner references did not finish deleting", namespace.Name)
+
+		ginkgo.By("Verifying the foreign owner survived the deletion of the other namespace")
+		_, err = f.ClientSet.CoreV1().ConfigMaps(otherNamespace.Name).Get(context.TODO(), foreignOwner.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "owner %s in namespace %s was collected along with namespace %s",
+			foreignOwner.Name, otherNamespace.Name, namespace.Name)
+	})
+
+	ginkgo.It("should converge deletion against a controller recreating deleted content [Feature:NamespaceTerminatingRejection]", func() {
+		ginkgo.By("Creating a test namespace with a configmap a naive controller will defend")
+		namespace, err := f.CreateNamespace("nsrecreatectl", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		configMap := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "defended-configmap"},
+			Data:       map[string]string{"defended": "true"},
+		}
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(), configMap, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create configmap in namespace %s", namespace.Name)
+
+		forbiddenBaseline, baselineErr := apiserverForbiddenRequestCount(f)
+		if baselineErr != nil {
+			framework.Logf("Cannot read apiserver 403 metrics (will rely on client-observed causes only): %v", baselineErr)
+		}
+
+		ginkgo.By("Starting the recreate-on-delete controller fixture")
+		recreator := startConfigMapRecreator(f.ClientSet, namespace.Name)
+		defer recreator.stop()
+
+		ginkgo.By("Deleting the namespace while the fixture fights the deletion")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 2*time.Minute),
+			"namespace %s did not converge to deletion while its content was being recreated", namespace.Name)
+
+		recreator.stop()
+		framework.Logf("Recreator summary: %d attempts, %d successful recreates, %d terminating rejections, %d unexpected errors",
+			recreator.attempts, recreator.successfulRecreates, recreator.terminatingRejections, len(recreator.unexpectedErrors))
+		if recreator.attempts == 0 {
+			framework.Failf("the fixture never observed the configmap deletion; the test did not exercise recreation against a terminating namespace")
+		}
+		if recreator.terminatingRejections == 0 {
+			framework.Failf("no recreate attempt was rejected with the %s cause; the apiserver did not fence the terminating namespace", v1.NamespaceTerminatingCause)
+		}
+		for _, err := range recreator.unexpectedErrors {
+			framework.Logf("unexpected recreate error: %v", err)
+		}
+		framework.ExpectEqual(len(recreator.unexpectedErrors), 0,
+			"recreate attempts failed with errors other than the terminating rejection")
+
+		if baselineErr == nil {
+			ginkgo.By("Cross-checking the rejections against the apiserver 403 counter")
+			forbiddenAfter, err := apiserverForbiddenRequestCount(f)
+			framework.ExpectNoError(err, "failed to re-read apiserver 403 metrics")
+			if delta := forbiddenAfter - forbiddenBaseline; delta < float64(recreator.terminatingRejections) {
+				framework.Failf("apiserver 403 counter grew by %.0f, less than the %d terminating rejections the fixture observed",
+					delta, recreator.terminatingRejections)
+			}
+		}
+	})
+
+	ginkgo.It("should remove never-scheduled pods held by scheduling gates when the namespace is deleted [Feature:PodSchedulingReadiness]", func() {
+		e2eskipper.SkipUnlessProviderHasNodes()
+		ginkgo.By("Creating a test namespace with a gated and an ungated pod")
+		namespace, err := f.CreateNamespace("nsgatedpods", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		const gate = "e2e.k8s.io/namespace-deletion"
+		gatedPod := newTestPod("gated-pod")
+		retained, err := createPodWithSchedulingGates(f, namespace.Name, gatedPod, []string{gate})
+		framework.ExpectNoError(err, "failed to create gated pod in namespace %s", namespace.Name)
+		if !retained {
+			e2eskipper.Skipf("server dropped spec.schedulingGates; pod scheduling readiness is not available")
+		}
+		liftedPod := newTestPod("lifted-pod")
+		retained, err = createPodWithSchedulingGates(f, namespace.Name, liftedPod, []string{gate})
+		framework.ExpectNoError(err, "failed to create second gated pod in namespace %s", namespace.Name)
+		framework.ExpectEqual(retained, true, "server dropped scheduling gates on the second pod")
+
+		// One shared watch covers both pods instead of polling each one.
+		watcher, err := e2epod.NewPodWatcher(context.TODO(), f.ClientSet, namespace.Name)
+		framework.ExpectNoError(err, "failed to start a pod watch over namespace %s", namespace.Name)
+		defer watcher.Stop()
+
+		ginkgo.By("Lifting the gates on one pod and verifying it schedules")
+		framework.ExpectNoError(liftSchedulingGates(f, namespace.Name, liftedPod.Name),
+			"failed to lift scheduling gates on pod %s", liftedPod.Name)
+		framework.ExpectNoError(watcher.WaitForRunning(liftedPod.Name, f.Timeouts.PodStart),
+			"pod %s never ran after its scheduling gates were lifted", liftedPod.Name)
+
+		ginkgo.By("Verifying the gated pod was never scheduled")
+		pod, err := f.ClientSet.CoreV1().Pods(namespace.Name).Get(context.TODO(), gatedPod.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to get gated pod %s", gatedPod.Name)
+		framework.ExpectEqual(pod.Spec.NodeName, "", "gated pod %s was scheduled despite its gates", gatedPod.Name)
+
+		ginkgo.By("Deleting the namespace and waiting for both pods to be removed")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(watcher.WaitForDeletion(gatedPod.Name, 5*time.Minute),
+			"never-scheduled pod %s was not removed during namespace deletion", gatedPod.Name)
+		framework.ExpectNoError(watcher.WaitForDeletion(liftedPod.Name, 5*time.Minute),
+			"running pod %s was not removed during namespace deletion", liftedPod.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s holding a never-scheduled pod did not finish terminating", namespace.Name)
 	})
 
+	ginkgo.It("should delete content before removing the spec finalizer and retain the object for user finalizers", func() {
+		ginkgo.By("Creating a test namespace with a user finalizer")
+		namespace, err := f.CreateNamespace("nsfinalizerorder", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		const userFinalizer = "e2e.k8s.io/finalizer-ordering"
+		namespace.Finalizers = append(namespace.Finalizers, userFinalizer)
+		namespace, err = f.ClientSet.CoreV1().Namespaces().Update(context.TODO(), namespace, metav1.UpdateOptions{})
+		framework.ExpectNoError(err, "failed to add user finalizer to namespace %s", namespace.Name)
+
+		ginkgo.By("Creating content in the namespace")
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(),
+			&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "finalizer-ordering"}}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create configmap in namespace %s", namespace.Name)
+
+		ginkgo.By("Watching the namespace while it is deleted")
+		w, err := f.ClientSet.CoreV1().Namespaces().Watch(context.TODO(), metav1.ListOptions{
+			FieldSelector:   "metadata.name=" + namespace.Name,
+			ResourceVersion: namespace.ResourceVersion,
+		})
+		framework.ExpectNoError(err, "failed to watch namespace %s", namespace.Name)
+		defer w.Stop()
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+
+		hasKubernetesFinalizer := func(ns *v1.Namespace) bool {
+			for _, finalizer := range ns.Spec.Finalizers {
+				if finalizer == v1.FinalizerKubernetes {
+					return true
+				}
+			}
+			return false
+		}
+
+		ginkgo.By("Waiting for the kubernetes spec finalizer to be removed")
+		sawTerminating := false
+		deadline := time.After(5 * time.Minute)
+	observeSpecFinalizer:
+		for {
+			select {
+			case event, ok := <-w.ResultChan():
+				if !ok {
+					framework.Failf("watch on namespace %s closed before the kubernetes spec finalizer was removed", namespace.Name)
+				}
+				if event.Type == watch.Deleted {
+					framework.Failf("namespace %s was removed while the user finalizer %s was still present", namespace.Name, userFinalizer)
+				}
+				ns, ok := event.Object.(*v1.Namespace)
+				if !ok {
+					continue
+				}
+				if ns.DeletionTimestamp == nil {
+					continue
+				}
+				if hasKubernetesFinalizer(ns) {
+					sawTerminating = true
+					continue
+				}
+				break observeSpecFinalizer
+			case <-deadline:
+				framework.Failf("timed out waiting for the kubernetes spec finalizer to be removed from namespace %s", namespace.Name)
+			}
+		}
+		if !sawTerminating {
+			framework.Failf("never observed namespace %s terminating with the kubernetes spec finalizer still present", namespace.Name)
+		}
+
+		ginkgo.By("Verifying the namespace content was deleted before the spec finalizer was removed")
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Get(context.TODO(), "finalizer-ordering", metav1.GetOptions{})
+		gomega.Expect(err).To(e2ematchers.BeDeleted(),
+			"configmap in namespace %s was not deleted before the kubernetes spec finalizer was removed", namespace.Name)
+
+		ginkgo.By("Verifying the namespace object is retained by the user finalizer")
+		ns, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "namespace %s disappeared while the user finalizer was still present", namespace.Name)
+
+		ginkgo.By("Removing the user finalizer and waiting for the namespace to go away")
+		remaining := ns.Finalizers[:0]
+		for _, finalizer := range ns.Finalizers {
+			if finalizer != userFinalizer {
+				remaining = append(remaining, finalizer)
+			}
+		}
+		ns.Finalizers = remaining
+		_, err = f.ClientSet.CoreV1().Namespaces().Update(context.TODO(), ns, metav1.UpdateOptions{})
+		framework.ExpectNoError(err, "failed to remove user finalizer from namespace %s", namespace.Name)
+		deadline = time.After(time.Minute)
+		for {
+			select {
+			case event, ok := <-w.ResultChan():
+				if !ok {
+					framework.Failf("watch on namespace %s closed before the object was removed", namespace.Name)
+				}
+				if event.Type == watch.Deleted {
+					return
+				}
+			case <-deadline:
+				framework.Failf("timed out waiting for namespace %s to be removed after dropping the user finalizer", namespace.Name)
+			}
+		}
+	})
+
+	ginkgo.It("should deny namespace patch and delete to an unprivileged impersonated user", func() {
+		const user = "e2e-unprivileged-user"
+		impersonated, err := f.ClientSetForUser(user, nil)
+		framework.ExpectNoError(err, "failed to build impersonating clientset for user %s", user)
+
+		ginkgo.By("Verifying the user cannot patch or delete the test namespace")
+		_, err = impersonated.CoreV1().Namespaces().Patch(context.TODO(), f.Namespace.Name, types.StrategicMergePatchType,
+			[]byte(`{"metadata":{"labels":{"e2e-impersonated":"true"}}}`), metav1.PatchOptions{})
+		framework.ExpectEqual(apierrors.IsForbidden(err), true,
+			"patch of namespace %s as user %s returned %v instead of Forbidden", f.Namespace.Name, user, err)
+		err = impersonated.CoreV1().Namespaces().Delete(context.TODO(), f.Namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectEqual(apierrors.IsForbidden(err), true,
+			"delete of namespace %s as user %s returned %v instead of Forbidden", f.Namespace.Name, user, err)
+
+		ginkgo.By("Granting the view cluster role and verifying the user can read but still not delete")
+		framework.ExpectNoError(f.GrantClusterRoleToUser("view", user),
+			"failed to grant view cluster role to user %s", user)
+		framework.ExpectNoError(e2eauth.WaitForAuthorizationUpdate(f.ClientSet.AuthorizationV1(),
+			user, f.Namespace.Name, "get", schema.GroupResource{Resource: "namespaces"}, true),
+			"view grant for user %s never became effective", user)
+		_, err = impersonated.CoreV1().Namespaces().Get(context.TODO(), f.Namespace.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "user %s cannot read namespace %s despite the view grant", user, f.Namespace.Name)
+		err = impersonated.CoreV1().Namespaces().Delete(context.TODO(), f.Namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectEqual(apierrors.IsForbidden(err), true,
+			"delete of namespace %s as view-only user %s returned %v instead of Forbidden", f.Namespace.Name, user, err)
+	})
+
+	ginkgo.It("should enforce the namespace verb matrix across the admin, edit and view identities", func() {
+		ginkgo.By("Creating a target namespace for the write attempts")
+		target, err := f.CreateNamespace("nsrbacmatrix", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+
+		labelPatch := []byte(`{"metadata":{"labels":{"e2e-rbac-matrix":"true"}}}`)
+		for _, identity := range []framework.Identity{framework.IdentityEdit, framework.IdentityView} {
+			client, err := f.ClientFor(identity)
+			framework.ExpectNoError(err, "failed to build %s client", identity)
+			framework.ExpectNoError(e2eauth.WaitForAuthorizationUpdate(f.ClientSet.AuthorizationV1(),
+				f.UserFor(identity), target.Name, "get", schema.GroupResource{Resource: "namespaces"}, true),
+				"%s grant never became effective", identity)
+
+			ginkgo.By(fmt.Sprintf("Verifying the %s identity can read but not write namespaces", identity))
+			_, err = client.CoreV1().Namespaces().Get(context.TODO(), target.Name, metav1.GetOptions{})
+			framework.ExpectNoError(err, "%s identity cannot read namespace %s", identity, target.Name)
+			_, err = client.CoreV1().Namespaces().Create(context.TODO(),
+				&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsrbacmatrix-" + framework.RandomSuffix()}}, metav1.CreateOptions{})
+			framework.ExpectEqual(apierrors.IsForbidden(err), true,
+				"namespace create as %s returned %v instead of Forbidden", identity, err)
+			_, err = client.CoreV1().Namespaces().Patch(context.TODO(), target.Name, types.StrategicMergePatchType, labelPatch, metav1.PatchOptions{})
+			framework.ExpectEqual(apierrors.IsForbidden(err), true,
+				"namespace patch as %s returned %v instead of Forbidden", identity, err)
+			err = client.CoreV1().Namespaces().Delete(context.TODO(), target.Name, metav1.DeleteOptions{})
+			framework.ExpectEqual(apierrors.IsForbidden(err), true,
+				"namespace delete as %s returned %v instead of Forbidden", identity, err)
+			_, err = client.CoreV1().Namespaces().Finalize(context.TODO(), target, metav1.UpdateOptions{})
+			framework.ExpectEqual(apierrors.IsForbidden(err), true,
+				"namespace finalize as %s returned %v instead of Forbidden", identity, err)
+		}
+
+		ginkgo.By("Verifying the admin identity can create, patch, finalize and delete namespaces")
+		adminClient, err := f.ClientFor(framework.IdentityAdmin)
+		framework.ExpectNoError(err, "failed to resolve admin client")
+		scratch, err := f.CreateNamespace("nsrbacadmin", nil)
+		framework.ExpectNoError(err, "failed to create namespace as admin")
+		_, err = adminClient.CoreV1().Namespaces().Patch(context.TODO(), scratch.Name, types.StrategicMergePatchType, labelPatch, metav1.PatchOptions{})
+		framework.ExpectNoError(err, "namespace patch as admin was denied")
+		scratch, err = adminClient.CoreV1().Namespaces().Get(context.TODO(), scratch.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to get namespace %s", scratch.Name)
+		_, err = adminClient.CoreV1().Namespaces().Finalize(context.TODO(), scratch, metav1.UpdateOptions{})
+		framework.ExpectNoError(err, "namespace finalize as admin was denied")
+		err = adminClient.CoreV1().Namespaces().Delete(context.TODO(), scratch.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "namespace delete as admin was denied")
+	})
+
+	ginkgo.It("should ignore spec.finalizers mutations through patches on the main resource", func() {
+		ginkgo.By("Creating a test namespace")
+		namespace, err := f.CreateNamespace("nsfinalizerimmutable", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		namespace, err = f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to get namespace %s", namespace.Name)
+		original := namespace.Spec.Finalizers
+		framework.ExpectEqual(original, []v1.FinalizerName{v1.FinalizerKubernetes},
+			"fresh namespace %s does not carry exactly the kubernetes spec finalizer", namespace.Name)
+
+		// The main-resource update strategy copies spec.finalizers from the
+		// old object, so a patch that tries to change them is accepted but
+		// has no effect; only the /finalize subresource can mutate the list.
+		ginkgo.By("Applying a strategic merge patch replacing spec.finalizers")
+		patched, err := f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), namespace.Name, types.StrategicMergePatchType,
+			[]byte(`{"spec":{"finalizers":["e2e.k8s.io/bogus"]}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "strategic merge patch of spec.finalizers on namespace %s was rejected instead of ignored", namespace.Name)
+		framework.ExpectEqual(patched.Spec.Finalizers, original,
+			"strategic merge patch mutated spec.finalizers of namespace %s through the main resource", namespace.Name)
+
+		// JSON patch differs from strategic merge in that its operations are
+		// evaluated against the live list: a test op naming the actual value
+		// passes (and the replace is still reverted), while a test op naming
+		// a wrong value fails the whole patch with 422.
+		ginkgo.By("Applying a JSON patch whose test op matches the live finalizer")
+		patched, err = f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), namespace.Name, types.JSONPatchType,
+			[]byte(`[{"op":"test","path":"/spec/finalizers/0","value":"kubernetes"},{"op":"replace","path":"/spec/finalizers","value":["e2e.k8s.io/bogus"]}]`),
+			metav1.PatchOptions{})
+		framework.ExpectNoError(err, "JSON patch of spec.finalizers on namespace %s was rejected instead of ignored", namespace.Name)
+		framework.ExpectEqual(patched.Spec.Finalizers, original,
+			"JSON patch mutated spec.finalizers of namespace %s through the main resource", namespace.Name)
+
+		ginkgo.By("Applying a JSON patch whose test op does not match the live finalizer")
+		_, err = f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), namespace.Name, types.JSONPatchType,
+			[]byte(`[{"op":"test","path":"/spec/finalizers/0","value":"e2e.k8s.io/bogus"}]`), metav1.PatchOptions{})
+		if err == nil {
+			framework.Failf("JSON patch with a failing test op against namespace %s unexpectedly succeeded", namespace.Name)
+		}
+		statusErr, ok := err.(*apierrors.StatusError)
+		framework.ExpectEqual(ok, true, "JSON patch with a failing test op returned a non-status error: %v", err)
+		framework.ExpectEqual(statusErr.ErrStatus.Code, int32(422),
+			"JSON patch with a failing test op against namespace %s returned %v instead of 422", namespace.Name, err)
+
+		ginkgo.By("Verifying the persisted spec.finalizers are unchanged")
+		namespace, err = f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to get namespace %s", namespace.Name)
+		framework.ExpectEqual(namespace.Spec.Finalizers, original,
+			"spec.finalizers of namespace %s changed despite every main-resource patch being ignored or rejected", namespace.Name)
+	})
+
+	ginkgo.It("should record the per-resource deletion ordering while deleting a namespace with mixed content", func() {
+		e2eskipper.SkipUnlessNamespaceLifecycle()
+		ginkgo.By("Creating a test namespace with mixed content")
+		namespace, err := f.CreateNamespace("nsdeleteorder", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		pod := newTestPod("deletion-order-pod")
+		_, err = f.ClientSet.CoreV1().Pods(namespace.Name).Create(context.TODO(), pod, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create pod in namespace %s", namespace.Name)
+		framework.ExpectNoError(e2epod.WaitForPodNameRunningInNamespace(f.ClientSet, pod.Name, namespace.Name),
+			"pod %s never ran in namespace %s", pod.Name, namespace.Name)
+		for i := 0; i < 3; i++ {
+			_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(),
+				&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("deletion-order-%d", i)}}, metav1.CreateOptions{})
+			framework.ExpectNoError(err, "failed to create configmap in namespace %s", namespace.Name)
+		}
+		_, err = f.ClientSet.CoreV1().Secrets(namespace.Name).Create(context.TODO(),
+			&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "deletion-order-secret"}}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create secret in namespace %s", namespace.Name)
+
+		ginkgo.By("Deleting the namespace while tracing per-resource deletion")
+		tracer := framework.StartDeletionOrderTracer(f.ClientSet, f.DynamicClient, namespace.Name)
+		defer tracer.Stop()
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s did not finish terminating", namespace.Name)
+		tracer.Stop()
+
+		ginkgo.By("Writing the deletion ordering report")
+		tracer.WriteArtifact(namespace.Name)
+		records := tracer.Records()
+		if len(records) == 0 {
+			framework.Failf("deletion order trace of namespace %s observed no content at all", namespace.Name)
+		}
+		sawPods := false
+		for _, record := range records {
+			if record.GVR == "/v1, Resource=pods" {
+				sawPods = true
+				if record.ClearedAt.IsZero() {
+					framework.Failf("deletion order trace never observed pods being emptied in namespace %s", namespace.Name)
+				}
+			}
+		}
+		framework.ExpectEqual(sawPods, true, "deletion order trace of namespace %s never observed the pod", namespace.Name)
+	})
+
+	ginkgo.It("should finish deleting a namespace while a validating webhook intercepts DELETEs of its content", func() {
+		e2eskipper.SkipUnlessNamespaceLifecycle()
+		e2eskipper.SkipUnlessProviderHasNodes()
+		e2eskipper.RequireCapability(context.TODO(), f.ClientSet, e2eskipper.Capability{
+			GroupVersion: "admissionregistration.k8s.io/v1",
+			Resource:     "validatingwebhookconfigurations",
+		})
+		ginkgo.By("Deploying a webhook backend into the framework namespace")
+		fixture, err := webhookfixture.Deploy(f.ClientSet, f.Namespace.Name, webhookfixture.Config{})
+		framework.ExpectNoError(err, "failed to deploy webhook fixture in namespace %s", f.Namespace.Name)
+
+		ginkgo.By("Creating a test namespace matched by the webhook's namespace selector")
+		interceptLabel := map[string]string{"e2e-webhook-delete-intercept": f.UniqueName}
+		namespace, err := f.CreateNamespace("nswebhookdelete", interceptLabel)
+		framework.ExpectNoError(err, "failed to create namespace")
+
+		ginkgo.By("Registering a validating webhook that delays DELETEs of pods and configmaps in that namespace")
+		sideEffectsNone := admissionregistrationv1.SideEffectClassNone
+		failurePolicy := admissionregistrationv1.Fail
+		configName := "e2e-namespace-delete-intercept-" + framework.RandomSuffix()
+		err = fixture.RegisterValidating(configName, admissionregistrationv1.ValidatingWebhook{
+			Name: "namespace-delete-intercept.e2e.k8s.io",
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Delete},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods", "configmaps"},
+				},
+			}},
+			ClientConfig:            fixture.ClientConfig("/always-allow-delay-5s"),
+			NamespaceSelector:       &metav1.LabelSelector{MatchLabels: interceptLabel},
+			FailurePolicy:           &failurePolicy,
+			TimeoutSeconds:          pointer.Int32Ptr(30),
+			SideEffects:             &sideEffectsNone,
+			AdmissionReviewVersions: []string{"v1", "v1beta1"},
+		})
+		framework.ExpectNoError(err, "failed to register validating webhook %s", configName)
+		defer fixture.Teardown()
+
+		// The configuration takes a moment to become active; the delay
+		// endpoint makes activation observable, because an intercepted DELETE
+		// cannot return in under five seconds.
+		ginkgo.By("Waiting for the webhook to intercept DELETE requests")
+		err = wait.PollImmediate(time.Second, 2*time.Minute, func() (bool, error) {
+			probe := fmt.Sprintf("webhook-activation-probe-%s", framework.RandomSuffix())
+			_, err := f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(),
+				&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: probe}}, metav1.CreateOptions{})
+			if err != nil {
+				return false, err
+			}
+			start := time.Now()
+			if err := f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Delete(context.TODO(), probe, metav1.DeleteOptions{}); err != nil {
+				return false, err
+			}
+			return time.Since(start) > 3*time.Second, nil
+		})
+		framework.ExpectNoError(err, "validating webhook %s never started intercepting DELETEs", configName)
+
+		ginkgo.By("Creating a pod and configmaps in the test namespace")
+		pod := newTestPod("webhook-intercepted-pod")
+		_, err = f.ClientSet.CoreV1().Pods(namespace.Name).Create(context.TODO(), pod, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create pod in namespace %s", namespace.Name)
+		framework.ExpectNoError(e2epod.WaitForPodNameRunningInNamespace(f.ClientSet, pod.Name, namespace.Name),
+			"pod %s never ran in namespace %s", pod.Name, namespace.Name)
+		for i := 0; i < 2; i++ {
+			_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(),
+				&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("webhook-intercepted-%d", i)}}, metav1.CreateOptions{})
+			framework.ExpectNoError(err, "failed to create configmap in namespace %s", namespace.Name)
+		}
+
+		ginkgo.By("Deleting the namespace and waiting for it to terminate despite the interception")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s did not finish terminating while its content DELETEs were intercepted", namespace.Name)
+	})
+
+	ginkgo.It("should reference only the new namespace UID in objects created after a delete and recreate", func() {
+		e2eskipper.SkipUnlessNamespaceLifecycle()
+		name := "e2e-uid-freshness-" + framework.RandomSuffix()
+
+		ginkgo.By("Creating a namespace and recording the UIDs of its content")
+		namespace, err := f.ClientSet.CoreV1().Namespaces().Create(context.TODO(),
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create namespace %q", name)
+		f.AddNamespacesToDelete(namespace)
+		framework.ExpectNoError(framework.WaitForNamespaceInitialized(context.TODO(), f.ClientSet, namespace.Name),
+			"namespace %s was never initialized", namespace.Name)
+		oldChild, err := f.ClientSet.CoreV1().ConfigMaps(name).Create(context.TODO(), &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "uid-freshness-child",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "v1",
+					Kind:       "Namespace",
+					Name:       name,
+					UID:        namespace.UID,
+				}},
+			},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create configmap in namespace %s", name)
+		staleUIDs := []types.UID{namespace.UID, oldChild.UID}
+
+		ginkgo.By("Deleting the namespace and recreating it with the same name")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{name}, 5*time.Minute),
+			"namespace %s never finished deleting", name)
+		namespace, err = f.ClientSet.CoreV1().Namespaces().Create(context.TODO(),
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to recreate namespace %q", name)
+		f.AddNamespacesToDelete(namespace)
+		framework.ExpectNoError(framework.WaitForNamespaceInitialized(context.TODO(), f.ClientSet, name),
+			"recreated namespace %s was never initialized", name)
+		framework.ExpectNotEqual(namespace.UID, staleUIDs[0], "the recreated namespace reused the deleted namespace's UID")
+
+		ginkgo.By("Creating child objects referencing the recreated namespace")
+		_, err = f.ClientSet.CoreV1().ConfigMaps(name).Create(context.TODO(), &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "uid-freshness-child",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "v1",
+					Kind:       "Namespace",
+					Name:       name,
+					UID:        namespace.UID,
+				}},
+			},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to recreate the child configmap in namespace %s", name)
+		_, err = f.ClientSet.CoreV1().Secrets(name).Create(context.TODO(),
+			&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "uid-freshness-secret"}}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create a secret in namespace %s", name)
+
+		ginkgo.By("Verifying nothing in the recreated namespace carries a pre-recreation UID")
+		framework.ExpectNoError(framework.VerifyNamespaceUIDFreshness(f.ClientSet, f.DynamicClient, name, staleUIDs),
+			"the recreated namespace %s still carries UIDs from before its deletion", name)
+	})
+
+	ginkgo.It("should not delete identically-prefixed namespaces owned by another tenant during bulk deletion", func() {
+		e2eskipper.SkipUnlessNamespaceLifecycle()
+		tenantLabels := map[string]string{"nsisolation": string(framework.RunID)}
+		tenantSelector := fmt.Sprintf("nsisolation=%s", framework.RunID)
+
+		// The decoy shares the name prefix of this tenant's namespaces but
+		// carries another tenant's label, so any helper that matches on names
+		// instead of the exact label selector would sweep it up.
+		ginkgo.By("Pre-creating a decoy namespace owned by another tenant")
+		decoyName := "nsisolation-decoy-" + framework.RandomSuffix()
+		decoy, err := f.ClientSet.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   decoyName,
+				Labels: map[string]string{"nsisolation": "another-tenant"},
+			},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create decoy namespace %q", decoyName)
+		f.AddNamespacesToDelete(decoy)
+		framework.ExpectNoError(framework.WaitForNamespaceInitialized(context.TODO(), f.ClientSet, decoyName),
+			"decoy namespace %s was never initialized", decoyName)
+
+		ginkgo.By("Creating this tenant's namespaces")
+		nsNames, err := f.CreateNamespaces(context.TODO(), 3, "nsisolation", framework.NamespaceBulkOptions{
+			Labels: tenantLabels,
+		})
+		framework.ExpectNoError(err, "failed to create tenant namespaces")
+		framework.ExpectNoError(framework.WaitAllNamespacesActive(f.ClientSet, nsNames, time.Minute),
+			"not all tenant namespaces became Active")
+
+		ginkgo.By("Bulk-deleting only the namespaces labelled for this tenant")
+		deleted, err := framework.DeleteNamespacesBySelector(context.TODO(), f.ClientSet, framework.NamespaceSelection{
+			LabelSelector: tenantSelector,
+		})
+		framework.ExpectNoError(err, "failed to delete namespaces matching %s", tenantSelector)
+		framework.ExpectEqual(len(deleted), len(nsNames), "unexpected number of namespaces deleted by %s", tenantSelector)
+		for _, name := range deleted {
+			if name == decoyName {
+				framework.Failf("bulk deletion for selector %s swept up the foreign namespace %s", tenantSelector, decoyName)
+			}
+		}
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, deleted, 2*time.Minute),
+			"tenant namespaces did not finish terminating")
+
+		ginkgo.By("Verifying the decoy namespace is untouched")
+		decoy, err = f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), decoyName, metav1.GetOptions{})
+		framework.ExpectNoError(err, "the decoy namespace %s disappeared during bulk deletion", decoyName)
+		gomega.Expect(decoy).To(e2ematchers.BeInPhase(v1.NamespaceActive),
+			"the decoy namespace %s left the Active phase during bulk deletion", decoyName)
+		if decoy.DeletionTimestamp != nil {
+			framework.Failf("the decoy namespace %s was marked for deletion at %v", decoyName, decoy.DeletionTimestamp)
+		}
+	})
+
+	ginkgo.It("should list namespaces and namespaced content in chunks and bulk-delete via DeleteCollection", func() {
+		nsGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+		cmGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+		chunkLabels := map[string]string{"nschunk": string(framework.RunID)}
+		chunkSelector := fmt.Sprintf("nschunk=%s", framework.RunID)
+
+		ginkgo.By("Creating labelled namespaces to page over")
+		nsNames, err := f.CreateNamespaces(context.TODO(), 10, "nschunk", framework.NamespaceBulkOptions{
+			Labels: chunkLabels,
+		})
+		framework.ExpectNoError(err, "failed to create the namespaces to list")
+
+		ginkgo.By("Verifying a raw chunk respects the limit and hands out a continue token")
+		page, err := f.ClientSet.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: chunkSelector, Limit: 3})
+		framework.ExpectNoError(err, "failed to list the first chunk of namespaces")
+		gomega.Expect(len(page.Items)).To(gomega.BeNumerically("<=", 3),
+			"a LIST with limit=3 returned %d namespaces", len(page.Items))
+		if page.Continue == "" {
+			framework.Failf("a LIST of %d namespaces with limit=3 returned no continue token", len(nsNames))
+		}
+
+		ginkgo.By("Walking all chunks with the paginated list helper")
+		items, err := framework.ListAllWithPagination(context.TODO(), f.DynamicClient, nsGVR, metav1.ListOptions{
+			LabelSelector: chunkSelector,
+			Limit:         3,
+		})
+		framework.ExpectNoError(err, "failed to list namespaces in chunks")
+		listed := map[string]bool{}
+		for _, item := range items {
+			listed[item.GetName()] = true
+		}
+		for _, name := range nsNames {
+			if !listed[name] {
+				framework.Failf("the chunked namespace list is missing %s (got %d of %d)", name, len(items), len(nsNames))
+			}
+		}
+
+		ginkgo.By("Creating labelled configmaps and listing them in chunks")
+		for i := 0; i < 10; i++ {
+			_, err := f.ClientSet.CoreV1().ConfigMaps(f.Namespace.Name).Create(context.TODO(), &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   fmt.Sprintf("nschunk-%02d", i),
+					Labels: chunkLabels,
+				},
+			}, metav1.CreateOptions{})
+			framework.ExpectNoError(err, "failed to create configmap %d", i)
+		}
+		cmItems, err := framework.ListAllWithPaginationInNamespace(context.TODO(), f.DynamicClient, cmGVR, f.Namespace.Name, metav1.ListOptions{
+			LabelSelector: chunkSelector,
+			Limit:         4,
+		})
+		framework.ExpectNoError(err, "failed to list configmaps in chunks")
+		framework.ExpectEqual(len(cmItems), 10, "the chunked configmap list is incomplete")
+
+		ginkgo.By("Bulk-deleting the configmaps with DeleteCollection and the selector")
+		err = f.ClientSet.CoreV1().ConfigMaps(f.Namespace.Name).DeleteCollection(context.TODO(),
+			metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: chunkSelector})
+		framework.ExpectNoError(err, "failed to bulk-delete the configmaps matching %s", chunkSelector)
+		cmItems, err = framework.ListAllWithPaginationInNamespace(context.TODO(), f.DynamicClient, cmGVR, f.Namespace.Name, metav1.ListOptions{
+			LabelSelector: chunkSelector,
+		})
+		framework.ExpectNoError(err, "failed to re-list configmaps after DeleteCollection")
+		framework.ExpectEqual(len(cmItems), 0, "DeleteCollection left configmaps behind: %v", cmItems)
+
+		ginkgo.By("Verifying the namespace resource itself rejects DeleteCollection")
+		// Namespaces deliberately do not expose the deletecollection verb;
+		// bulk namespace deletion must go through per-object DELETEs.
+		err = f.DynamicClient.Resource(nsGVR).DeleteCollection(context.TODO(),
+			metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: chunkSelector})
+		if !apierrors.IsMethodNotSupported(err) {
+			framework.Failf("DeleteCollection on namespaces returned %v instead of a method-not-supported error", err)
+		}
+	})
+
+	ginkgo.It("should serve a streaming namespace list that matches the chunked list [Feature:WatchList]", func() {
+		if framework.ServerAdvertisesCBOR(f.ClientSet) {
+			framework.Logf("The apiserver negotiates CBOR payloads; the framework will use them once the serializer is vendored")
+		}
+
+		streamLabels := map[string]string{"nsstream": string(framework.RunID)}
+		streamSelector := fmt.Sprintf("nsstream=%s", framework.RunID)
+
+		ginkgo.By("Creating labelled namespaces to list")
+		nsNames, err := f.CreateNamespaces(context.TODO(), 5, "nsstream", framework.NamespaceBulkOptions{
+			Labels: streamLabels,
+		})
+		framework.ExpectNoError(err, "failed to create the namespaces to list")
+
+		ginkgo.By("Listing them through the streaming list protocol")
+		streamedItems, streamed, err := framework.ListNamespacesViaWatchList(f.ClientSet, streamSelector, 30*time.Second)
+		framework.ExpectNoError(err, "failed to list namespaces via the streaming path")
+		if !streamed {
+			e2eskipper.Skipf("the apiserver does not support streaming lists")
+		}
+
+		ginkgo.By("Comparing against the chunked list")
+		listed, err := f.ClientSet.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: streamSelector})
+		framework.ExpectNoError(err, "failed to list namespaces matching %s", streamSelector)
+		chunkedNames := map[string]bool{}
+		for _, item := range listed.Items {
+			chunkedNames[item.Name] = true
+		}
+		framework.ExpectEqual(len(streamedItems), len(listed.Items),
+			"the streaming list and the chunked list disagree on how many namespaces match %s", streamSelector)
+		for _, item := range streamedItems {
+			if !chunkedNames[item.Name] {
+				framework.Failf("the streaming list returned namespace %s that the chunked list did not", item.Name)
+			}
+		}
+		for _, name := range nsNames {
+			if !chunkedNames[name] {
+				framework.Failf("namespace %s is missing from both lists", name)
+			}
+		}
+	})
+
+	ginkgo.It("should only garbage collect namespaces owned by cluster-scoped owners", func() {
+		ginkgo.By("Creating an owner namespace and a child namespace referencing it")
+		owner, err := f.CreateNamespace("nsowner", nil)
+		framework.ExpectNoError(err, "failed to create the owner namespace")
+		child, err := f.CreateNamespace("nschild", nil)
+		framework.ExpectNoError(err, "failed to create the child namespace")
+		child.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+			Name:       owner.Name,
+			UID:        owner.UID,
+		}}
+		child, err = f.ClientSet.CoreV1().Namespaces().Update(context.TODO(), child, metav1.UpdateOptions{})
+		framework.ExpectNoError(err, "a cluster-scoped owner on namespace %s should be accepted", child.Name)
+
+		ginkgo.By("Creating a victim namespace referencing a namespaced owner")
+		invalidOwner, err := f.ClientSet.CoreV1().ConfigMaps(f.Namespace.Name).Create(context.TODO(), &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "invalid-namespace-owner"},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create the namespaced owner configmap")
+		victim, err := f.CreateNamespace("nsvictim", nil)
+		framework.ExpectNoError(err, "failed to create the victim namespace")
+		victim.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Name:       invalidOwner.Name,
+			UID:        invalidOwner.UID,
+		}}
+		// The API server does not validate owner scope on write; the garbage
+		// collector is what must refuse to act on the invalid reference.
+		victim, err = f.ClientSet.CoreV1().Namespaces().Update(context.TODO(), victim, metav1.UpdateOptions{})
+		framework.ExpectNoError(err, "failed to set a namespaced owner on namespace %s", victim.Name)
+
+		ginkgo.By("Deleting both owners")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), owner.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete the owner namespace %s", owner.Name)
+		err = f.ClientSet.CoreV1().ConfigMaps(f.Namespace.Name).Delete(context.TODO(), invalidOwner.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete the owner configmap %s", invalidOwner.Name)
+
+		ginkgo.By("Waiting for the garbage collector to delete the validly owned child")
+		err = framework.WaitForNamespacesDeleted(f.ClientSet, []string{child.Name}, framework.ScaleTimeout(2*time.Minute))
+		framework.ExpectNoError(err, "namespace %s owned by deleted namespace %s was never garbage collected", child.Name, owner.Name)
+
+		ginkgo.By("Verifying the invalidly owned namespace is left alone")
+		// The child is only collected after a full GC pass, so by now the
+		// collector has also seen the victim's invalid reference.
+		victim, err = f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), victim.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "namespace %s with a namespaced owner disappeared", victim.Name)
+		gomega.Expect(victim).To(e2ematchers.BeInPhase(v1.NamespaceActive),
+			"namespace %s left the Active phase after its invalid owner was deleted", victim.Name)
+		if victim.DeletionTimestamp != nil {
+			framework.Failf("namespace %s was marked for deletion based on a namespaced owner", victim.Name)
+		}
+	})
+
+	ginkgo.It("should enforce a bootstrap resource quota once its status has synced", func() {
+		ginkgo.By("Creating a namespace whose quota forbids pods from the start")
+		quota := &v1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-quota"},
+			Spec: v1.ResourceQuotaSpec{
+				Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("0")},
+			},
+		}
+		namespace, err := f.CreateNamespaceWithQuota("nsquota", quota)
+		framework.ExpectNoError(err, "failed to create namespace with a bootstrap quota")
+
+		ginkgo.By("Waiting for the quota controller to publish the quota status")
+		synced, err := framework.WaitForQuotaStatusSynced(f.ClientSet, namespace.Name, quota.Name, time.Minute)
+		framework.ExpectNoError(err, "quota %s never synced in namespace %s", quota.Name, namespace.Name)
+		used := synced.Status.Used[v1.ResourcePods]
+		framework.ExpectEqual(used.Value(), int64(0),
+			"freshly synced quota %s reports unexpected pod usage", quota.Name)
+
+		ginkgo.By("Verifying pod creation is rejected by quota admission")
+		_, err = f.ClientSet.CoreV1().Pods(namespace.Name).Create(context.TODO(), newTestPod("quota-denied-pod"), metav1.CreateOptions{})
+		if err == nil {
+			framework.Failf("pod creation in namespace %s was admitted despite a pods=0 quota", namespace.Name)
+		}
+		if !apierrors.IsForbidden(err) {
+			framework.Failf("pod creation in quota-limited namespace %s failed with %v instead of Forbidden", namespace.Name, err)
+		}
+
+		ginkgo.By("Raising the quota and verifying pod creation is admitted after the status re-syncs")
+		synced.Spec.Hard[v1.ResourcePods] = resource.MustParse("1")
+		_, err = f.ClientSet.CoreV1().ResourceQuotas(namespace.Name).Update(context.TODO(), synced, metav1.UpdateOptions{})
+		framework.ExpectNoError(err, "failed to raise quota %s in namespace %s", quota.Name, namespace.Name)
+		// Admission evaluates against the synced status, so wait for the
+		// raised limit to land there before creating the pod.
+		err = wait.PollImmediate(framework.Poll, time.Minute, func() (bool, error) {
+			current, err := f.ClientSet.CoreV1().ResourceQuotas(namespace.Name).Get(context.TODO(), quota.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			hard, ok := current.Status.Hard[v1.ResourcePods]
+			return ok && hard.Value() == 1, nil
+		})
+		framework.ExpectNoError(err, "raised quota %s never reached the status of namespace %s", quota.Name, namespace.Name)
+		_, err = f.ClientSet.CoreV1().Pods(namespace.Name).Create(context.TODO(), newTestPod("quota-admitted-pod"), metav1.CreateOptions{})
+		framework.ExpectNoError(err, "pod creation in namespace %s was rejected despite quota headroom", namespace.Name)
+	})
+
+	ginkgo.It("should accept, serve and delete a namespace whose name is at the 63 character limit", func() {
+		e2eskipper.SkipUnlessNamespaceLifecycle()
+		// Namespace names are DNS-1123 labels, capped at 63 characters. Pad a
+		// unique prefix out to exactly the limit.
+		name := fmt.Sprintf("e2e-max-length-ns-%s-", framework.RandomSuffix())
+		name = name + strings.Repeat("x", validation.DNS1123LabelMaxLength-len(name))
+		framework.ExpectEqual(len(name), validation.DNS1123LabelMaxLength)
+
+		ginkgo.By("Creating a namespace with a 63 character name")
+		namespace, err := f.ClientSet.CoreV1().Namespaces().Create(context.TODO(),
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create namespace with max-length name %q", name)
+		f.AddNamespacesToDelete(namespace)
+		framework.ExpectNoError(framework.WaitForNamespaceInitialized(context.TODO(), f.ClientSet, namespace.Name),
+			"namespace %s was never initialized", namespace.Name)
+
+		ginkgo.By("Rejecting a namespace whose name exceeds the limit")
+		_, err = f.ClientSet.CoreV1().Namespaces().Create(context.TODO(),
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name + "x"}}, metav1.CreateOptions{})
+		if err == nil {
+			framework.Failf("creating a namespace with the 64 character name %q unexpectedly succeeded", name+"x")
+		}
+		if !apierrors.IsInvalid(err) {
+			framework.Failf("creating a namespace with an over-limit name returned %v instead of an Invalid error", err)
+		}
+
+		// Max out the service name too: the generated in-cluster DNS name
+		// ends up with two labels at the 63 character cap, which is still
+		// legal and must round-trip through the API.
+		ginkgo.By("Creating a pod and a max-length-named service inside the namespace")
+		serviceName := "e2e-max-length-svc-" + framework.RandomSuffix() + "-"
+		serviceName = serviceName + strings.Repeat("x", validation.DNS1123LabelMaxLength-len(serviceName))
+		_, err = f.ClientSet.CoreV1().Services(namespace.Name).Create(context.TODO(), &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: serviceName},
+			Spec: v1.ServiceSpec{
+				Selector: map[string]string{"name": "max-length-pod"},
+				Ports:    []v1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(80)}},
+			},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create service %q in namespace %s", serviceName, namespace.Name)
+
+		pod := newTestPod("max-length-pod")
+		_, err = f.ClientSet.CoreV1().Pods(namespace.Name).Create(context.TODO(), pod, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create pod in namespace %s", namespace.Name)
+		framework.ExpectNoError(e2epod.WaitForPodNameRunningInNamespace(f.ClientSet, pod.Name, namespace.Name),
+			"pod %s never ran in namespace %s", pod.Name, namespace.Name)
+
+		ginkgo.By("Deleting the max-length-named namespace and its content end-to-end")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"max-length-named namespace %s did not finish terminating", namespace.Name)
+		_, err = f.ClientSet.CoreV1().Pods(namespace.Name).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+		if !apierrors.IsNotFound(err) {
+			framework.Failf("pod %s in deleted namespace %s still gettable: %v", pod.Name, namespace.Name, err)
+		}
+	})
+
+	ginkgo.It("should delete a namespace and recover storage while etcd is near quota [Feature:EtcdPressure] [Disruptive]", func() {
+		const (
+			ballastCount = 64
+			ballastBytes = 256 * 1024
+		)
+
+		baseline, err := etcdStorageInUseBytes(f)
+		if err != nil {
+			e2eskipper.Skipf("cannot introspect etcd storage size: %v", err)
+		}
+		framework.Logf("etcd in-use size before pressure: %.0f bytes", baseline)
+
+		ginkgo.By("Creating a test namespace and filling it with ballast configmaps")
+		namespace, err := f.CreateNamespace("nsetcdpressure", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		framework.ExpectNoError(applyEtcdWritePressure(f, namespace.Name, ballastCount, ballastBytes),
+			"failed to apply etcd write pressure in namespace %s", namespace.Name)
+		grown, err := etcdStorageInUseBytes(f)
+		framework.ExpectNoError(err, "failed to read etcd storage size under pressure")
+		framework.Logf("etcd in-use size under pressure: %.0f bytes (+%.0f)", grown, grown-baseline)
+
+		ginkgo.By("Deleting the namespace while storage is under pressure")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s under etcd pressure", namespace.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 10*time.Minute),
+			"namespace %s did not finish terminating under etcd pressure", namespace.Name)
+
+		ginkgo.By("Waiting for compaction to recover the ballast space")
+		budget := baseline + float64(ballastCount*ballastBytes)/2
+		framework.ExpectNoError(wait.Poll(15*time.Second, 10*time.Minute, func() (bool, error) {
+			current, err := etcdStorageInUseBytes(f)
+			if err != nil {
+				return false, err
+			}
+			framework.Logf("etcd in-use size after deletion: %.0f bytes (budget %.0f)", current, budget)
+			return current <= budget, nil
+		}), "etcd in-use size never recovered after deleting namespace %s", namespace.Name)
+	})
+
+	ginkgo.It("should report a content deletion failure condition and self-heal once a denying webhook is removed [Feature:NamespaceDeletionContentFailure] [Disruptive]", func() {
+		ginkgo.By("Creating a test namespace with content")
+		namespace, err := f.CreateNamespace("nsdenytest", map[string]string{f.UniqueName: "true"})
+		framework.ExpectNoError(err, "failed to create namespace")
+		configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "deny-deletion"}}
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(), configMap, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create configmap in namespace %s", namespace.Name)
+
+		ginkgo.By("Registering a backendless fail-closed webhook that denies configmap deletion in the namespace")
+		policyFail := admissionregistrationv1.Fail
+		sideEffectsNone := admissionregistrationv1.SideEffectClassNone
+		configName := "e2e-deny-content-deletion-" + framework.RandomSuffix()
+		_, err = f.ClientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(context.TODO(), &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: configName},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+				Name: "deny-configmap-deletion.e2e.k8s.io",
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Delete},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{""},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"configmaps"},
+					},
+				}},
+				// Nothing is listening behind this service reference on purpose;
+				// combined with failurePolicy Fail every matching deletion is
+				// denied without touching system RBAC.
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: namespace.Name,
+						Name:      "no-such-webhook-service",
+						Path:      strPtr("/deny"),
+						Port:      pointer.Int32Ptr(443),
+					},
+				},
+				FailurePolicy:           &policyFail,
+				SideEffects:             &sideEffectsNone,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{f.UniqueName: "true"},
+				},
+			}},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to register webhook configuration %s", configName)
+		defer func() {
+			err := f.ClientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(context.TODO(), configName, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				framework.Logf("Failed to clean up webhook configuration %s: %v", configName, err)
+			}
+		}()
+
+		ginkgo.By("Waiting for the webhook denial to take effect")
+		framework.ExpectNoError(wait.Poll(time.Second, time.Minute, func() (bool, error) {
+			err := f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Delete(context.TODO(), configMap.Name, metav1.DeleteOptions{})
+			if err == nil {
+				// The deletion slipped through before the webhook became
+				// active; recreate the configmap and probe again.
+				if _, err := f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(), configMap, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+					return false, err
+				}
+				return false, nil
+			}
+			return true, nil
+		}), "webhook denial of configmap deletion never became active")
+
+		ginkgo.By("Deleting the namespace and waiting for the content failure condition")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(wait.Poll(5*time.Second, 2*time.Minute, func() (bool, error) {
+			ns, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			for _, condition := range ns.Status.Conditions {
+				if condition.Type == v1.NamespaceDeletionContentFailure && condition.Status == v1.ConditionTrue {
+					framework.Logf("namespace %s reports content deletion failure: %s", namespace.Name, condition.Message)
+					return true, nil
+				}
+			}
+			return false, nil
+		}), "namespace %s never reported the NamespaceDeletionContentFailure condition", namespace.Name)
+
+		ginkgo.By("Lifting the denial and verifying the namespace self-heals")
+		err = f.ClientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(context.TODO(), configName, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to remove webhook configuration %s", configName)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s did not finish terminating after the denying webhook was removed", namespace.Name)
+	})
+
+	ginkgo.It("should emit catalogued event reasons while a webhook blocks pod deletion [Feature:NamespaceDeletionContentFailure] [Disruptive]", func() {
+		ginkgo.By("Creating a test namespace with a ReplicaSet")
+		namespace, err := f.CreateNamespace("nseventreason", map[string]string{f.UniqueName: "true"})
+		framework.ExpectNoError(err, "failed to create namespace")
+		replicas := int32(2)
+		labels := map[string]string{"event-reason-test": "true"}
+		rs := &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "event-reason-rs"},
+			Spec: appsv1.ReplicaSetSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{
+							{
+								Name:  "pause",
+								Image: imageutils.GetPauseImageName(),
+							},
+						},
+					},
+				},
+			},
+		}
+		rs, err = f.ClientSet.AppsV1().ReplicaSets(namespace.Name).Create(context.TODO(), rs, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create ReplicaSet in namespace %s", namespace.Name)
+		selector := metav1.FormatLabelSelector(rs.Spec.Selector)
+
+		ginkgo.By("Waiting for the catalogued pod creation reason")
+		framework.ExpectNoError(e2eevents.ExpectEventWithReason(f.ClientSet, namespace.Name, e2eevents.ReasonSuccessfulCreate, 2*time.Minute),
+			"ReplicaSet %s never reported creating its pods", rs.Name)
+		framework.ExpectNoError(e2eevents.ExpectNoEventWithReason(f.ClientSet, namespace.Name, e2eevents.ReasonFailedCreate, 10*time.Second),
+			"pod creation failed in a fresh namespace")
+
+		ginkgo.By("Registering a backendless fail-closed webhook that denies pod deletion in the namespace")
+		policyFail := admissionregistrationv1.Fail
+		sideEffectsNone := admissionregistrationv1.SideEffectClassNone
+		configName := "e2e-deny-pod-deletion-" + framework.RandomSuffix()
+		_, err = f.ClientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(context.TODO(), &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: configName},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+				Name: "deny-pod-deletion.e2e.k8s.io",
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Delete},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{""},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"pods"},
+					},
+				}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: namespace.Name,
+						Name:      "no-such-webhook-service",
+						Path:      strPtr("/deny"),
+						Port:      pointer.Int32Ptr(443),
+					},
+				},
+				FailurePolicy:           &policyFail,
+				SideEffects:             &sideEffectsNone,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{f.UniqueName: "true"},
+				},
+			}},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to register webhook configuration %s", configName)
+		defer func() {
+			err := f.ClientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(context.TODO(), configName, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				framework.Logf("Failed to clean up webhook configuration %s: %v", configName, err)
+			}
+		}()
+
+		ginkgo.By("Waiting for the webhook denial to take effect")
+		framework.ExpectNoError(wait.Poll(time.Second, time.Minute, func() (bool, error) {
+			pods, err := f.ClientSet.CoreV1().Pods(namespace.Name).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				return false, err
+			}
+			if len(pods.Items) == 0 {
+				return false, nil
+			}
+			// Deletions slipping through are recreated by the ReplicaSet, so
+			// probing is harmless until the denial is active.
+			err = f.ClientSet.CoreV1().Pods(namespace.Name).Delete(context.TODO(), pods.Items[0].Name, metav1.DeleteOptions{})
+			return err != nil, nil
+		}), "webhook denial of pod deletion never became active")
+
+		ginkgo.By("Scaling the ReplicaSet down and waiting for the catalogued deletion-failure reason")
+		_, err = f.ClientSet.AppsV1().ReplicaSets(namespace.Name).Patch(context.TODO(), rs.Name,
+			types.MergePatchType, []byte(`{"spec":{"replicas":0}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to scale down ReplicaSet %s", rs.Name)
+		framework.ExpectNoError(e2eevents.ExpectEventWithReason(f.ClientSet, namespace.Name, e2eevents.ReasonFailedDelete, 2*time.Minute),
+			"ReplicaSet %s never reported the denied pod deletion", rs.Name)
+
+		ginkgo.By("Lifting the denial and waiting for the catalogued deletion success reason")
+		err = f.ClientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(context.TODO(), configName, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to remove webhook configuration %s", configName)
+		framework.ExpectNoError(e2eevents.ExpectEventWithReason(f.ClientSet, namespace.Name, e2eevents.ReasonSuccessfulDelete, 5*time.Minute),
+			"ReplicaSet %s never reported deleting its pods after the denial was lifted", rs.Name)
+
+		ginkgo.By("Deleting the namespace")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s did not finish terminating after the denying webhook was removed", namespace.Name)
+	})
+
+	ginkgo.It("should block namespace deletion while a ValidatingAdmissionPolicy denies it [Feature:ValidatingAdmissionPolicy]", func() {
+		if !framework.ServerSupportsAdmissionPolicy(f.ClientSet) {
+			e2eskipper.Skipf("cluster does not serve the ValidatingAdmissionPolicy API")
+		}
+
+		ginkgo.By("Creating a labeled test namespace")
+		namespace, err := f.CreateNamespace("nsvaptest", map[string]string{f.UniqueName: "true"})
+		framework.ExpectNoError(err, "failed to create namespace")
+
+		ginkgo.By("Installing a policy denying deletion of namespaces carrying the label")
+		policyName := "e2e-deny-namespace-deletion-" + framework.RandomSuffix()
+		denyMessage := fmt.Sprintf("namespace deletion denied by policy %s", policyName)
+		cleanup, err := framework.InstallNamespaceDeleteDenyPolicy(f.DynamicClient, policyName, f.UniqueName, "true", denyMessage)
+		defer cleanup()
+		framework.ExpectNoError(err, "failed to install ValidatingAdmissionPolicy %s", policyName)
+
+		ginkgo.By("Waiting for the policy denial to take effect")
+		framework.ExpectNoError(wait.Poll(time.Second, time.Minute, func() (bool, error) {
+			err := f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+			if err == nil {
+				// The deletion slipped through before the policy became
+				// active; the namespace is already terminating, so the spec
+				// cannot observe the denial anymore.
+				return false, fmt.Errorf("namespace %s was deleted before the policy became active", namespace.Name)
+			}
+			if !strings.Contains(err.Error(), denyMessage) {
+				framework.Logf("deletion of namespace %s denied, but not yet by the policy: %v", namespace.Name, err)
+				return false, nil
+			}
+			return true, nil
+		}), "policy denial of namespace deletion never became active")
+
+		ginkgo.By("Removing the policy and verifying deletion proceeds")
+		cleanup()
+		framework.ExpectNoError(wait.Poll(time.Second, time.Minute, func() (bool, error) {
+			err := f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+			if err != nil && strings.Contains(err.Error(), denyMessage) {
+				return false, nil
+			}
+			if err != nil && !apierrors.IsNotFound(err) {
+				return false, err
+			}
+			return true, nil
+		}), "namespace deletion stayed denied after the policy was removed")
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 2*time.Minute),
+			"namespace %s did not finish terminating after the policy was removed", namespace.Name)
+	})
+
+	ginkgo.It("should report remaining finalizers while a blocking finalizer stalls deletion and finish once it is cleared", func() {
+		ginkgo.By("Creating a test namespace and round-tripping a spec finalizer through /finalize")
+		namespace, err := f.CreateNamespace("nsstuckfinalizer", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		const specFinalizer = v1.FinalizerName("e2e.k8s.io/spec-finalizer")
+		namespace, err = framework.AddNamespaceSpecFinalizer(f.ClientSet, namespace.Name, specFinalizer)
+		framework.ExpectNoError(err, "failed to add spec finalizer to namespace %s", namespace.Name)
+		gomega.Expect(namespace.Spec.Finalizers).To(gomega.ContainElement(specFinalizer),
+			"spec finalizer missing after /finalize update")
+		namespace, err = framework.RemoveNamespaceSpecFinalizer(f.ClientSet, namespace.Name, specFinalizer)
+		framework.ExpectNoError(err, "failed to remove spec finalizer from namespace %s", namespace.Name)
+		gomega.Expect(namespace.Spec.Finalizers).NotTo(gomega.ContainElement(specFinalizer),
+			"spec finalizer still present after /finalize removal")
+
+		ginkgo.By("Creating content carrying a blocking finalizer")
+		const blockingFinalizer = "e2e.k8s.io/blocking"
+		configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:       "stuck-finalizer",
+			Finalizers: []string{blockingFinalizer},
+		}}
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(), configMap, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create configmap in namespace %s", namespace.Name)
+		defer func() {
+			// Ensure the finalizer never outlives the spec even on failure.
+			_, err := f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Patch(context.TODO(), configMap.Name,
+				types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				framework.Logf("Failed to strip blocking finalizer from configmap %s: %v", configMap.Name, err)
+			}
+		}()
+
+		ginkgo.By("Deleting the namespace and waiting for the finalizers-remaining condition")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(wait.Poll(2*time.Second, 2*time.Minute, func() (bool, error) {
+			ns, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if ns.Status.Phase != v1.NamespaceTerminating {
+				return false, nil
+			}
+			for _, condition := range ns.Status.Conditions {
+				if condition.Type == v1.NamespaceFinalizersRemaining && condition.Status == v1.ConditionTrue {
+					if !strings.Contains(condition.Message, blockingFinalizer) {
+						framework.Logf("finalizers-remaining condition does not name %s yet: %s", blockingFinalizer, condition.Message)
+						return false, nil
+					}
+					return true, nil
+				}
+			}
+			return false, nil
+		}), "namespace %s never reported the %s condition naming %s", namespace.Name, v1.NamespaceFinalizersRemaining, blockingFinalizer)
+
+		ginkgo.By("Clearing the blocking finalizer and waiting for deletion to finish")
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Patch(context.TODO(), configMap.Name,
+			types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to clear blocking finalizer from configmap %s", configMap.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 2*time.Minute),
+			"namespace %s did not finish terminating after the blocking finalizer was cleared", namespace.Name)
+	})
+
+	ginkgo.It("should report the content-remaining condition while deletion is blocked and clear it on completion", func() {
+		e2eskipper.SkipUnlessNamespaceLifecycle()
+		if !f.ServerSupports("NamespaceConditions") {
+			e2eskipper.Skipf("server does not publish namespace deletion conditions")
+		}
+		ginkgo.By("Creating a namespace whose content carries a blocking finalizer")
+		namespace, err := f.CreateNamespace("nscontentcond", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		const blockingFinalizer = "e2e.k8s.io/content-remaining"
+		configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:       "content-remaining",
+			Finalizers: []string{blockingFinalizer},
+		}}
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(), configMap, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create configmap in namespace %s", namespace.Name)
+		defer func() {
+			// Never let the finalizer outlive the spec.
+			_, err := f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Patch(context.TODO(), configMap.Name,
+				types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				framework.Logf("Failed to strip blocking finalizer from configmap %s: %v", configMap.Name, err)
+			}
+		}()
+
+		ginkgo.By("Deleting the namespace and waiting for the content-remaining condition")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		ns, err := framework.WaitForNamespaceCondition(context.TODO(), f.ClientSet, namespace.Name,
+			v1.NamespaceContentRemaining, v1.ConditionTrue, 2*time.Minute)
+		framework.ExpectNoErrorWithObject(err, ns, "namespace %s never reported remaining content", namespace.Name)
+		gomega.Expect(ns).To(e2ematchers.BeInPhase(v1.NamespaceTerminating),
+			"namespace %s reports remaining content outside the Terminating phase", namespace.Name)
+		ns, err = framework.WaitForNamespaceCondition(context.TODO(), f.ClientSet, namespace.Name,
+			v1.NamespaceFinalizersRemaining, v1.ConditionTrue, 2*time.Minute)
+		framework.ExpectNoErrorWithObject(err, ns, "namespace %s never reported remaining finalizers", namespace.Name)
+
+		ginkgo.By("Clearing the finalizer and verifying deletion finishes")
+		_, err = f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Patch(context.TODO(), configMap.Name,
+			types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to clear blocking finalizer from configmap %s", configMap.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 2*time.Minute),
+			"namespace %s did not finish terminating after its content was unblocked", namespace.Name)
+	})
+
+	ginkgo.It("should allow updating namespace status conditions through the status subresource", func() {
+		ginkgo.By("Creating a test namespace and reading its status")
+		namespace, err := f.CreateNamespace("nsstatussub", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		ns, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to get namespace %s", namespace.Name)
+		gomega.Expect(ns).To(e2ematchers.BeInPhase(v1.NamespaceActive),
+			"freshly created namespace %s is not Active", namespace.Name)
+
+		ginkgo.By("Patching the status subresource with a custom condition")
+		patch := []byte(`{"status":{"conditions":[{"type":"StatusUpdate","status":"True","reason":"E2E","message":"patched by the namespace status spec","lastTransitionTime":null}]}}`)
+		ns, err = f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), namespace.Name,
+			types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+		framework.ExpectNoError(err, "failed to patch the status of namespace %s", namespace.Name)
+		gomega.Expect(ns).To(e2ematchers.HaveCondition("StatusUpdate", v1.ConditionTrue),
+			"patched condition missing from namespace %s status", namespace.Name)
+
+		ginkgo.By("Updating the condition through the status subresource")
+		for i := range ns.Status.Conditions {
+			if ns.Status.Conditions[i].Type == "StatusUpdate" {
+				ns.Status.Conditions[i].Message = "updated by the namespace status spec"
+			}
+		}
+		ns, err = f.ClientSet.CoreV1().Namespaces().UpdateStatus(context.TODO(), ns, metav1.UpdateOptions{})
+		framework.ExpectNoError(err, "failed to update the status of namespace %s", namespace.Name)
+		var updatedMessage string
+		for _, condition := range ns.Status.Conditions {
+			if condition.Type == "StatusUpdate" {
+				updatedMessage = condition.Message
+			}
+		}
+		framework.ExpectEqual(updatedMessage, "updated by the namespace status spec",
+			"status update did not round-trip on namespace %s", namespace.Name)
+
+		ginkgo.By("Verifying the phase is immutable through the main resource")
+		ns, err = f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to re-get namespace %s", namespace.Name)
+		gomega.Expect(ns).To(e2ematchers.BeInPhase(v1.NamespaceActive),
+			"writing status conditions changed the phase of namespace %s", namespace.Name)
+	})
+
+	ginkgo.It("should return the full namespace with Terminating status in the DELETE response body", func() {
+		e2eskipper.SkipUnlessNamespaceLifecycle()
+		ginkgo.By("Creating a namespace to delete")
+		namespace, err := f.CreateNamespace("nsdeletebody", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+
+		ginkgo.By("Deleting the namespace through the REST client and capturing the raw response body")
+		raw, err := f.ClientSet.CoreV1().RESTClient().Delete().
+			Resource("namespaces").Name(namespace.Name).
+			Do(context.TODO()).Raw()
+		framework.ExpectNoError(err, "failed to delete namespace %s through the REST client", namespace.Name)
+
+		// Per the API conventions an asynchronous DELETE returns the object as
+		// it looks after the delete was accepted, not a metav1.Status.
+		var typeMeta metav1.TypeMeta
+		framework.ExpectNoError(json.Unmarshal(raw, &typeMeta),
+			"failed to decode the DELETE response body for namespace %s", namespace.Name)
+		framework.ExpectEqual(typeMeta.Kind, "Namespace",
+			"the DELETE response body for namespace %s is a %q, not the deleted namespace", namespace.Name, typeMeta.Kind)
+
+		deleted := &v1.Namespace{}
+		framework.ExpectNoError(json.Unmarshal(raw, deleted),
+			"failed to decode the DELETE response body for namespace %s as a namespace", namespace.Name)
+		if deleted.DeletionTimestamp == nil {
+			framework.Failf("the DELETE response for namespace %s carries no deletionTimestamp", namespace.Name)
+		}
+		gomega.Expect(deleted).To(e2ematchers.BeInPhase(v1.NamespaceTerminating),
+			"the DELETE response for namespace %s does not reflect the Terminating phase", namespace.Name)
+
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 2*time.Minute),
+			"namespace %s never finished terminating", namespace.Name)
+	})
+
+	ginkgo.It("should serve namespaces and the /api list identically through the apiserver service proxy [Feature:APIServerServiceProxy]", func() {
+		proxyClient, err := framework.ProxiedClientSet(f.ClientConfig())
+		framework.ExpectNoError(err, "failed to construct client using the apiserver service proxy path")
+
+		ginkgo.By("Comparing the /api list between direct and proxied access")
+		directRaw, err := f.ClientSet.Discovery().RESTClient().Get().AbsPath("/api").Do(context.TODO()).Raw()
+		framework.ExpectNoError(err, "failed to list /api directly")
+		proxiedRaw, err := proxyClient.Discovery().RESTClient().Get().AbsPath("/api").Do(context.TODO()).Raw()
+		if err != nil {
+			// The proxy path does not forward client credentials, so clusters
+			// without proxy authentication wiring reject it.
+			e2eskipper.Skipf("apiserver service proxy rejected the request: %v", err)
+		}
+		framework.ExpectEqual(string(proxiedRaw), string(directRaw), "/api list differs between direct and proxied access")
+
+		ginkgo.By("Creating a namespace through the proxy and reading it back directly")
+		created, err := proxyClient.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "nsproxytest-" + framework.RandomSuffix()},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create namespace through the proxy")
+		f.AddNamespacesToDelete(created)
+		direct, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), created.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "namespace created through the proxy is not visible directly")
+		framework.ExpectEqual(direct.UID, created.UID, "direct and proxied reads returned different namespaces")
+
+		ginkgo.By("Patching the namespace directly and observing the change through the proxy")
+		_, err = f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), created.Name, types.StrategicMergePatchType,
+			[]byte(`{"metadata":{"labels":{"proxy-visible":"true"}}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to patch namespace %s", created.Name)
+		viaProxy, err := proxyClient.CoreV1().Namespaces().Get(context.TODO(), created.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to get namespace %s through the proxy", created.Name)
+		framework.ExpectEqual(viaProxy.Labels["proxy-visible"], "true", "patch is not visible through the proxy")
+
+		ginkgo.By("Deleting the namespace through the proxy")
+		framework.ExpectNoError(proxyClient.CoreV1().Namespaces().Delete(context.TODO(), created.Name, metav1.DeleteOptions{}),
+			"failed to delete namespace %s through the proxy", created.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{created.Name}, 2*time.Minute),
+			"namespace %s deleted through the proxy did not terminate", created.Name)
+	})
+
+	ginkgo.It("should honor read-your-writes on quorum reads while resourceVersion=0 reads may be stale", func() {
+		ginkgo.By("Creating a namespace and immediately reading it back with a quorum read")
+		namespace, err := f.CreateNamespace("nsconsistency", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		got, err := framework.GetNamespaceWithResourceVersion(f.ClientSet, namespace.Name, "")
+		framework.ExpectNoError(err, "quorum read after create did not observe namespace %s", namespace.Name)
+		framework.ExpectEqual(got.UID, namespace.UID, "quorum read returned a different incarnation of namespace %s", namespace.Name)
+
+		ginkgo.By("Reading with resourceVersion=0, which may be served from a stale watch cache")
+		cached, err := framework.GetNamespaceWithResourceVersion(f.ClientSet, namespace.Name, "0")
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				framework.Failf("resourceVersion=0 read after create failed with an error other than NotFound: %v", err)
+			}
+			framework.Logf("resourceVersion=0 read did not observe namespace %s yet; staleness is allowed here", namespace.Name)
+		} else if cached.UID != namespace.UID {
+			framework.Failf("resourceVersion=0 read returned a different incarnation of namespace %s (UID %s, expected %s)", namespace.Name, cached.UID, namespace.UID)
+		}
+
+		ginkgo.By("Deleting the namespace and waiting for its removal")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s did not finish terminating", namespace.Name)
+
+		ginkgo.By("Verifying a quorum read observes the deletion")
+		_, err = framework.GetNamespaceWithResourceVersion(f.ClientSet, namespace.Name, "")
+		if !apierrors.IsNotFound(err) {
+			framework.Failf("quorum read after deletion of namespace %s returned %v instead of NotFound", namespace.Name, err)
+		}
+
+		ginkgo.By("Verifying a resourceVersion=0 read returns NotFound or, at worst, the old incarnation")
+		cached, err = framework.GetNamespaceWithResourceVersion(f.ClientSet, namespace.Name, "0")
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				framework.Failf("resourceVersion=0 read after deletion failed with an error other than NotFound: %v", err)
+			}
+		} else if cached.UID != namespace.UID {
+			framework.Failf("resourceVersion=0 read after deletion returned an unexpected incarnation of namespace %s (UID %s, expected %s)", namespace.Name, cached.UID, namespace.UID)
+		}
+	})
+
+	ginkgo.It("should finish deleting a namespace whose pod is stuck on an unreachable node once force deleted", func() {
+		ginkgo.By("Creating a test namespace")
+		namespace, err := f.CreateNamespace("nsunknownpods", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+
+		ginkgo.By("Creating a pod bound to an unreachable node")
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "stuck-pod"},
+			Spec: v1.PodSpec{
+				NodeName: "e2e-unreachable-node-" + framework.RandomSuffix(),
+				Containers: []v1.Container{
+					{
+						Name:  "pause",
+						Image: imageutils.GetPauseImageName(),
+					},
+				},
+			},
+		}
+		pod, err = f.ClientSet.CoreV1().Pods(namespace.Name).Create(context.TODO(), pod, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create pod in namespace %s", namespace.Name)
+
+		ginkgo.By("Marking the pod as running but unknown, as a lost kubelet would leave it")
+		pod.Status.Phase = v1.PodRunning
+		pod.Status.Reason = "NodeLost"
+		pod.Status.Conditions = []v1.PodCondition{{
+			Type:   v1.PodReady,
+			Status: v1.ConditionUnknown,
+			Reason: "NodeLost",
+		}}
+		pod, err = f.ClientSet.CoreV1().Pods(namespace.Name).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{})
+		framework.ExpectNoError(err, "failed to update status of pod %s", pod.Name)
+
+		ginkgo.By("Deleting the namespace and waiting for the pod to become stuck terminating")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(wait.Poll(2*time.Second, 2*time.Minute, func() (bool, error) {
+			stuckPod, err := f.ClientSet.CoreV1().Pods(namespace.Name).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return stuckPod.DeletionTimestamp != nil, nil
+		}), "pod %s never entered graceful deletion", pod.Name)
+
+		// With no kubelet to acknowledge the graceful deletion, the namespace
+		// must still be terminating behind the stuck pod.
+		ns, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to get terminating namespace %s", namespace.Name)
+		gomega.Expect(ns).To(e2ematchers.BeInPhase(v1.NamespaceTerminating),
+			"namespace %s finished terminating despite a pod stuck on an unreachable node", namespace.Name)
+
+		ginkgo.By("Force deleting the stuck pod with a zero grace period")
+		zero := int64(0)
+		err = f.ClientSet.CoreV1().Pods(namespace.Name).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &zero})
+		framework.ExpectNoError(err, "failed to force delete pod %s", pod.Name)
+
+		ginkgo.By("Verifying the namespace deletion no longer hangs")
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s did not finish terminating after the stuck pod was force deleted", namespace.Name)
+	})
+
+	ginkgo.It("should remove pods orphaned by a ReplicaSet deleted with orphan propagation", func() {
+		ginkgo.By("Creating a test namespace")
+		namespace, err := f.CreateNamespace("nsorphantest", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+
+		ginkgo.By("Creating a ReplicaSet in the namespace")
+		replicas := int32(2)
+		labels := map[string]string{"orphan-test": "true"}
+		rs := &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphan-rs"},
+			Spec: appsv1.ReplicaSetSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{
+							{
+								Name:  "pause",
+								Image: imageutils.GetPauseImageName(),
+							},
+						},
+					},
+				},
+			},
+		}
+		_, err = f.ClientSet.AppsV1().ReplicaSets(namespace.Name).Create(context.TODO(), rs, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create ReplicaSet in namespace %s", namespace.Name)
+
+		ginkgo.By("Waiting for the ReplicaSet pods to be created")
+		selector := metav1.FormatLabelSelector(rs.Spec.Selector)
+		framework.ExpectNoError(wait.Poll(2*time.Second, 2*time.Minute, func() (bool, error) {
+			pods, err := f.ClientSet.CoreV1().Pods(namespace.Name).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				return false, err
+			}
+			return len(pods.Items) == int(replicas), nil
+		}), "ReplicaSet pods were never created in namespace %s", namespace.Name)
+
+		ginkgo.By("Deleting the ReplicaSet with orphan propagation")
+		orphan := metav1.DeletePropagationOrphan
+		err = f.ClientSet.AppsV1().ReplicaSets(namespace.Name).Delete(context.TODO(), rs.Name, metav1.DeleteOptions{PropagationPolicy: &orphan})
+		framework.ExpectNoError(err, "failed to delete ReplicaSet %s with orphan propagation", rs.Name)
+
+		ginkgo.By("Verifying the pods were orphaned, not garbage collected")
+		framework.ExpectNoError(wait.Poll(2*time.Second, time.Minute, func() (bool, error) {
+			_, err := f.ClientSet.AppsV1().ReplicaSets(namespace.Name).Get(context.TODO(), rs.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}), "ReplicaSet %s was never removed", rs.Name)
+		pods, err := f.ClientSet.CoreV1().Pods(namespace.Name).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+		framework.ExpectNoError(err, "failed to list orphaned pods in namespace %s", namespace.Name)
+		framework.ExpectEqual(len(pods.Items), int(replicas), "orphaned pods disappeared before namespace deletion")
+
+		ginkgo.By("Deleting the namespace and waiting for the orphaned pods to go with it")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 5*time.Minute),
+			"namespace %s with orphaned pods did not finish terminating", namespace.Name)
+
+		// Best effort: surface garbage collector health so slow teardown can be
+		// correlated with GC controller backpressure.
+		grabber, err := e2emetrics.NewMetricsGrabber(f.ClientSet, nil, f.ClientConfig(), false, false, true, false, false, false)
+		if err == nil {
+			if received, err := grabber.Grab(); err == nil {
+				framework.Logf("Gathered controller-manager metrics after orphaned pod cleanup: %d metric families", len(received.ControllerManagerMetrics))
+			} else {
+				framework.Logf("Failed to grab controller-manager metrics (continuing): %v", err)
+			}
+		} else {
+			framework.Logf("Failed to create metrics grabber (continuing): %v", err)
+		}
+	})
+
+	ginkgo.It("should clean up external load balancer resources when the namespace is deleted [Feature:LoadBalancer]", func() {
+		e2eskipper.SkipUnlessProviderHasLoadBalancers()
+		ginkgo.By("Creating a test namespace with a LoadBalancer service")
+		namespace, err := f.CreateNamespace("nslbtest", nil)
+		framework.ExpectNoError(err, "failed to create namespace")
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "external-lb"},
+			Spec: v1.ServiceSpec{
+				Type:     v1.ServiceTypeLoadBalancer,
+				Selector: map[string]string{"app": "external-lb"},
+				Ports: []v1.ServicePort{{
+					Port:       80,
+					TargetPort: intstr.FromInt(80),
+				}},
+			},
+		}
+		_, err = f.ClientSet.CoreV1().Services(namespace.Name).Create(context.TODO(), service, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create LoadBalancer service in namespace %s", namespace.Name)
+
+		ginkgo.By("Waiting for the load balancer to be provisioned")
+		framework.ExpectNoError(wait.Poll(10*time.Second, 15*time.Minute, func() (bool, error) {
+			svc, err := f.ClientSet.CoreV1().Services(namespace.Name).Get(context.TODO(), service.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return len(svc.Status.LoadBalancer.Ingress) > 0, nil
+		}), "load balancer for service %s/%s was never provisioned", namespace.Name, service.Name)
+
+		// Snapshot the services before deletion so the provider hook knows
+		// which external resources to look for.
+		services, err := f.ClientSet.CoreV1().Services(namespace.Name).List(context.TODO(), metav1.ListOptions{})
+		framework.ExpectNoError(err, "failed to list services in namespace %s", namespace.Name)
+
+		ginkgo.By("Deleting the namespace and waiting for it to vanish")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, 10*time.Minute))
+
+		ginkgo.By("Verifying the provider deprovisioned external resources")
+		err = framework.TestContext.CloudConfig.Provider.VerifyNamespaceExternalCleanup(namespace.Name, services.Items)
+		framework.ExpectNoError(err, "external resources leaked after deleting namespace %s", namespace.Name)
+	})
+
+	ginkgo.It("should keep pod admission latency low in healthy namespaces while many quota-laden namespaces terminate [Feature:NamespaceAdmissionIsolation]", func() {
+		e2eskipper.SkipUnlessNamespaceLifecycle()
+		const terminatingCount = 50
+		const admissionSamples = 20
+		e2eskipper.SkipUnlessClusterCanSupport(f.ClientSet, e2eskipper.ResourceBudget{
+			Namespaces: terminatingCount,
+			QPS:        2 * terminatingCount,
+		})
+
+		ginkgo.By("Giving the healthy namespace a quota and limit range so its admission path exercises both plugins")
+		_, err := f.ClientSet.CoreV1().ResourceQuotas(f.Namespace.Name).Create(context.TODO(), &v1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "admission-probe-quota"},
+			Spec: v1.ResourceQuotaSpec{
+				Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("100")},
+			},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create the quota in the healthy namespace")
+		_, err = f.ClientSet.CoreV1().LimitRanges(f.Namespace.Name).Create(context.TODO(), &v1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "admission-probe-limits"},
+			Spec: v1.LimitRangeSpec{
+				Limits: []v1.LimitRangeItem{{
+					Type:           v1.LimitTypeContainer,
+					Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")},
+					DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("10m"), v1.ResourceMemory: resource.MustParse("32Mi")},
+				}},
+			},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create the limit range in the healthy namespace")
+
+		// Dry-run creates travel the full admission chain (including the
+		// quota and limit range plugins) without leaving pods to clean up,
+		// which keeps the probe itself from perturbing the measurement.
+		measureAdmission := func(phase string) time.Duration {
+			latencies := make([]time.Duration, 0, admissionSamples)
+			for i := 0; i < admissionSamples; i++ {
+				pod := e2epod.NewMinimalPod(fmt.Sprintf("admission-probe-%s-%d", phase, i))
+				start := time.Now()
+				_, err := f.ClientSet.CoreV1().Pods(f.Namespace.Name).Create(context.TODO(), pod,
+					metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+				latencies = append(latencies, time.Since(start))
+				framework.ExpectNoError(err, "dry-run pod create failed during the %s measurement", phase)
+			}
+			p90 := framework.LatencyPercentile(latencies, 90)
+			framework.Logf("Pod admission latency (%s): P50 %v, P90 %v",
+				phase, framework.LatencyPercentile(latencies, 50), p90)
+			return p90
+		}
+
+		ginkgo.By("Measuring the baseline pod admission latency")
+		baselineP90 := measureAdmission("baseline")
+
+		ginkgo.By(fmt.Sprintf("Creating %d namespaces loaded with quotas and limit ranges", terminatingCount))
+		stormSelector := fmt.Sprintf("nsadmstorm=%s", framework.RunID)
+		stormNames, err := f.CreateNamespaces(context.TODO(), terminatingCount, "nsadmstorm", framework.NamespaceBulkOptions{
+			Labels: map[string]string{"nsadmstorm": string(framework.RunID)},
+		})
+		framework.ExpectNoError(err, "failed to create the terminating-storm namespaces")
+		const holdFinalizer = "e2e.k8s.io/admission-storm-hold"
+		for _, name := range stormNames {
+			_, err := f.ClientSet.CoreV1().ResourceQuotas(name).Create(context.TODO(), &v1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "storm-quota"},
+				Spec: v1.ResourceQuotaSpec{
+					Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("10")},
+				},
+			}, metav1.CreateOptions{})
+			framework.ExpectNoError(err, "failed to create the quota in namespace %s", name)
+			_, err = f.ClientSet.CoreV1().LimitRanges(name).Create(context.TODO(), &v1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{Name: "storm-limits"},
+				Spec: v1.LimitRangeSpec{
+					Limits: []v1.LimitRangeItem{{
+						Type:           v1.LimitTypeContainer,
+						DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("10m")},
+						Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+					}},
+				},
+			}, metav1.CreateOptions{})
+			framework.ExpectNoError(err, "failed to create the limit range in namespace %s", name)
+			// The finalizer pins each namespace in Terminating for the whole
+			// measurement window instead of racing the namespace controller.
+			_, err = f.ClientSet.CoreV1().ConfigMaps(name).Create(context.TODO(), &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "storm-hold", Finalizers: []string{holdFinalizer}},
+			}, metav1.CreateOptions{})
+			framework.ExpectNoError(err, "failed to create the holding configmap in namespace %s", name)
+		}
+		releaseHolds := func() {
+			for _, name := range stormNames {
+				_, err := f.ClientSet.CoreV1().ConfigMaps(name).Patch(context.TODO(), "storm-hold",
+					types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`), metav1.PatchOptions{})
+				if err != nil && !apierrors.IsNotFound(err) {
+					framework.Logf("Failed to release the holding finalizer in namespace %s: %v", name, err)
+				}
+			}
+		}
+		defer releaseHolds()
+
+		ginkgo.By("Deleting the loaded namespaces and waiting until all of them are Terminating")
+		_, err = framework.DeleteNamespacesBySelector(context.TODO(), f.ClientSet, framework.NamespaceSelection{
+			LabelSelector: stormSelector,
+		})
+		framework.ExpectNoError(err, "failed to delete the namespaces matching %s", stormSelector)
+		framework.ExpectNoError(wait.Poll(2*time.Second, 2*time.Minute, func() (bool, error) {
+			for _, name := range stormNames {
+				ns, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				if ns.Status.Phase != v1.NamespaceTerminating {
+					return false, nil
+				}
+			}
+			return true, nil
+		}), "not all storm namespaces entered the Terminating phase")
+
+		ginkgo.By("Measuring pod admission latency while the storm namespaces terminate")
+		duringP90 := measureAdmission("terminating-storm")
+
+		// A healthy namespace's admission path must not pay for quota and
+		// limit range recalculation in Terminating namespaces. The floor
+		// keeps sub-millisecond baselines from turning jitter into failures.
+		objective := 3 * baselineP90
+		if floor := 500 * time.Millisecond; objective < floor {
+			objective = floor
+		}
+		framework.EnforceSLO(framework.SLOResult{
+			Name:      "pod-admission-latency-under-namespace-termination",
+			Measured:  duringP90.Seconds(),
+			Objective: objective.Seconds(),
+			Unit:      "s",
+			Description: fmt.Sprintf("pod admission P90 was %v while %d quota-laden namespaces terminated (baseline %v, objective %v)",
+				duringP90, terminatingCount, baselineP90, objective),
+		})
+
+		ginkgo.By("Releasing the holding finalizers and waiting for the storm namespaces to finish deleting")
+		releaseHolds()
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, stormNames, framework.ScaleTimeout(5*time.Minute)),
+			"the storm namespaces did not finish terminating after their finalizers were released")
+	})
+
+	ginkgo.It("should keep namespace lifecycle latency low for a higher priority client while low priority traffic floods the apiserver [Feature:APFNamespaceLifecycle]", func() {
+		const (
+			elevatedUser = "e2e-ns-apf-elevated"
+			flooderUser  = "e2e-ns-apf-flooder"
+			maxOpLatency = 15 * time.Second
+		)
+
+		ginkgo.By("Installing priority levels and flow schemas for the elevated and flooder users")
+		_, cleanupElevatedPL := createPriorityLevel(f, elevatedUser, 3)
+		defer cleanupElevatedPL()
+		_, cleanupElevatedFS := createNamespaceResourceFlowSchema(f, elevatedUser, 500, elevatedUser, []string{elevatedUser})
+		defer cleanupElevatedFS()
+		_, cleanupFlooderPL := createPriorityLevel(f, flooderUser, 1)
+		defer cleanupFlooderPL()
+		_, cleanupFlooderFS := createFlowSchema(f, flooderUser, 1000, flooderUser, []string{flooderUser})
+		defer cleanupFlooderFS()
+		waitForSteadyState(f, elevatedUser, elevatedUser)
+		waitForSteadyState(f, flooderUser, flooderUser)
+
+		ginkgo.By("Granting the elevated user permission to manage namespaces")
+		crb, err := f.ClientSet.RbacV1().ClusterRoleBindings().Create(context.TODO(), &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: elevatedUser},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: elevatedUser, APIGroup: rbacv1.GroupName}},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cluster-admin"},
+		}, metav1.CreateOptions{})
+		framework.ExpectNoError(err, "failed to create ClusterRoleBinding for the elevated user")
+		defer func() {
+			framework.ExpectNoError(f.ClientSet.RbacV1().ClusterRoleBindings().Delete(context.TODO(), crb.Name, metav1.DeleteOptions{}))
+		}()
+
+		config := f.ClientConfig()
+		config.Impersonate.UserName = elevatedUser
+		elevatedClient, err := clientset.NewForConfig(config)
+		framework.ExpectNoError(err, "failed to build impersonating clientset for the elevated user")
+
+		ginkgo.By("Flooding the apiserver with low priority traffic while measuring namespace lifecycle latency")
+		floodDone := make(chan struct{})
+		go func() {
+			defer close(floodDone)
+			defer ginkgo.GinkgoRecover()
+			completed := uniformQPSLoadConcurrent(f, flooderUser, 5, 50.0, 30*time.Second)
+			framework.Logf("flooder completed %d requests", completed)
+		}()
+
+		for i := 0; i < 5; i++ {
+			name := fmt.Sprintf("nsapftest-%d-%v", i, framework.RandomSuffix())
+			start := time.Now()
+			ns, err := elevatedClient.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+			createLatency := time.Since(start)
+			framework.ExpectNoError(err, "failed to create namespace %s as the elevated user", name)
+			f.AddNamespacesToDelete(ns)
+			start = time.Now()
+			err = elevatedClient.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+			deleteLatency := time.Since(start)
+			framework.ExpectNoError(err, "failed to delete namespace %s as the elevated user", name)
+			framework.Logf("namespace %s: create took %v, delete took %v", name, createLatency, deleteLatency)
+			if createLatency > maxOpLatency || deleteLatency > maxOpLatency {
+				framework.Failf("namespace lifecycle latency for the elevated user exceeded %v under low priority flooding (create %v, delete %v)",
+					maxOpLatency, createLatency, deleteLatency)
+			}
+		}
+		<-floodDone
+	})
+
+	ginkgo.It("should keep namespace lifecycle working while encryption-at-rest keys are rotated [Feature:EncryptionKeyRotation] [Disruptive]", func() {
+		provider := framework.TestContext.CloudConfig.Provider
+
+		ginkgo.By("Checking that the provider can rotate encryption-at-rest keys")
+		if err := provider.RotateEncryptionKeys(); err != nil {
+			if strings.Contains(err.Error(), "does not support") {
+				e2eskipper.Skipf("provider %q: %v", framework.TestContext.Provider, err)
+			}
+			framework.ExpectNoError(err, "failed to rotate encryption keys before starting namespace churn")
+		}
+
+		ginkgo.By("Creating namespaces encrypted with the current key")
+		totalNS := 10
+		nsNames := make([]string, totalNS)
+		framework.ExpectNoError(framework.RunParallel(totalNS, 0, func(n int) error {
+			ns, err := f.CreateNamespace(fmt.Sprintf("nsrotatetest-%v", n), nil)
+			if err != nil {
+				return err
+			}
+			nsNames[n] = ns.Name
+			return nil
+		}), "failed to create namespaces before key rotation")
+
+		ginkgo.By("Rotating the encryption keys while namespaces are created and deleted")
+		stopChurn := make(chan struct{})
+		churnDone := make(chan error, 1)
+		go func() {
+			defer ginkgo.GinkgoRecover()
+			for i := 0; ; i++ {
+				select {
+				case <-stopChurn:
+					churnDone <- nil
+					return
+				default:
+				}
+				ns, err := f.CreateNamespace(fmt.Sprintf("nsrotatechurn-%v", i), nil)
+				if err != nil {
+					churnDone <- fmt.Errorf("failed to create namespace during key rotation: %v", err)
+					return
+				}
+				if err := f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), ns.Name, metav1.DeleteOptions{}); err != nil {
+					churnDone <- fmt.Errorf("failed to delete namespace %s during key rotation: %v", ns.Name, err)
+					return
+				}
+			}
+		}()
+		rotateErr := provider.RotateEncryptionKeys()
+		close(stopChurn)
+		churnErr := <-churnDone
+		framework.ExpectNoError(rotateErr, "failed to rotate encryption keys during namespace churn")
+		framework.ExpectNoError(churnErr, "namespace lifecycle operation failed irrecoverably during key rotation")
+
+		ginkgo.By("Verifying namespaces written with the previous key are still readable")
+		for _, name := range nsNames {
+			_, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+			framework.ExpectNoError(err, "failed to read pre-rotation namespace %s after key rotation", name)
+		}
+
+		ginkgo.By("Deleting the pre-rotation namespaces with the new key in place")
+		for _, name := range nsNames {
+			err := f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+			framework.ExpectNoError(err, "failed to delete pre-rotation namespace %s after key rotation", name)
+		}
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, nsNames, 5*time.Minute),
+			"pre-rotation namespaces did not finish terminating after key rotation")
+	})
+
+	ginkgo.It("should delete fast enough while namespaces are being listed and watched [Feature:NamespaceReadLoad]",
+		func() {
+			extinguishWithReadLoad(f, 100, 10, 150, &namespaceReadLoad{
+				watchers:       5,
+				listers:        3,
+				maxListLatency: 30 * time.Second,
+			})
+		})
+
+	/*
+	   Release: v1.18
+	   Testname: Namespace patching
+	   Description: A Namespace is created.
+	   The Namespace is patched.
+	   The Namespace and MUST now include the new Label.
+	*/
+	framework.ConformanceIt("should patch a Namespace", func() {
+		ginkgo.By("creating a Namespace")
+		namespaceName := "nspatchtest-" + framework.RandomSuffix()
+		ns, err := f.CreateNamespace(namespaceName, nil)
+		framework.ExpectNoError(err, "failed creating Namespace")
+		namespaceName = ns.ObjectMeta.Name
+
+		ginkgo.By("patching the Namespace")
+		nspatch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]string{"testLabel": "testValue"},
+			},
+		})
+		framework.ExpectNoError(err, "failed to marshal JSON patch data")
+		_, err = f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), namespaceName, types.StrategicMergePatchType, nspatch, metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to patch Namespace")
+
+		ginkgo.By("get the Namespace and ensuring it has the label")
+		namespace, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), namespaceName, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to get Namespace")
+		framework.ExpectEqual(namespace.ObjectMeta.Labels["testLabel"], "testValue", "namespace not patched")
+	})
+
+	ginkgo.It("should remove annotations through merge patch nulls and strategic merge patch delete directives", func() {
+		ginkgo.By("creating a Namespace with two annotations")
+		ns, err := f.CreateNamespace("nspatchremove", nil)
+		framework.ExpectNoError(err, "failed creating Namespace")
+		patched, err := f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), ns.Name, types.MergePatchType,
+			[]byte(`{"metadata":{"annotations":{"e2e-keep":"kept","e2e-remove":"doomed"}}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to set annotations on Namespace %s", ns.Name)
+		framework.ExpectEqual(patched.Annotations["e2e-remove"], "doomed", "annotation was not set on Namespace %s", ns.Name)
+
+		// A JSON merge patch removes a single map key by setting it to null;
+		// this works on cluster-scoped resources exactly as it does on
+		// namespaced ones.
+		ginkgo.By("removing one annotation with a merge patch null value")
+		patched, err = f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), ns.Name, types.MergePatchType,
+			[]byte(`{"metadata":{"annotations":{"e2e-remove":null}}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to null out annotation on Namespace %s", ns.Name)
+		if _, present := patched.Annotations["e2e-remove"]; present {
+			framework.Failf("merge patch null did not remove annotation e2e-remove from Namespace %s", ns.Name)
+		}
+		framework.ExpectEqual(patched.Annotations["e2e-keep"], "kept",
+			"merge patch null removed an unrelated annotation from Namespace %s", ns.Name)
+
+		// Strategic merge patch supports the same per-key null removal plus a
+		// $patch: delete directive that clears the whole map.
+		ginkgo.By("re-adding the annotation and removing it with a strategic merge patch null value")
+		_, err = f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), ns.Name, types.StrategicMergePatchType,
+			[]byte(`{"metadata":{"annotations":{"e2e-remove":"doomed-again"}}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to re-add annotation on Namespace %s", ns.Name)
+		patched, err = f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), ns.Name, types.StrategicMergePatchType,
+			[]byte(`{"metadata":{"annotations":{"e2e-remove":null}}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to null out annotation via SMP on Namespace %s", ns.Name)
+		if _, present := patched.Annotations["e2e-remove"]; present {
+			framework.Failf("strategic merge patch null did not remove annotation e2e-remove from Namespace %s", ns.Name)
+		}
+		framework.ExpectEqual(patched.Annotations["e2e-keep"], "kept",
+			"strategic merge patch null removed an unrelated annotation from Namespace %s", ns.Name)
+
+		ginkgo.By("clearing all annotations with a strategic merge patch delete directive")
+		patched, err = f.ClientSet.CoreV1().Namespaces().Patch(context.TODO(), ns.Name, types.StrategicMergePatchType,
+			[]byte(`{"metadata":{"annotations":{"$patch":"delete"}}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to apply $patch: delete to annotations of Namespace %s", ns.Name)
+		if _, present := patched.Annotations["e2e-keep"]; present {
+			framework.Failf("$patch: delete directive did not clear annotation e2e-keep from Namespace %s", ns.Name)
+		}
+
+		ginkgo.By("verifying the removals were persisted")
+		namespace, err := f.ClientSet.CoreV1().Namespaces().Get(context.TODO(), ns.Name, metav1.GetOptions{})
+		framework.ExpectNoError(err, "failed to get Namespace %s", ns.Name)
+		for _, key := range []string{"e2e-keep", "e2e-remove"} {
+			if _, present := namespace.Annotations[key]; present {
+				framework.Failf("annotation %s survived on Namespace %s despite its removal", key, ns.Name)
+			}
+		}
+	})
+
+	ginkgo.It("should manage namespace fields through server-side apply with manager ownership and conflicts", func() {
+		if !f.ServerSupports("ServerSideApply") {
+			e2eskipper.Skipf("server does not support server-side apply with managedFields semantics")
+		}
+		ginkgo.By("creating a Namespace and applying a label under the first field manager")
+		ns, err := f.CreateNamespace("nsssa", nil)
+		framework.ExpectNoError(err, "failed creating Namespace")
+		applied, err := framework.ServerSideApplyNamespace(f.ClientSet, &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: ns.Name, Labels: map[string]string{"e2e-ssa-owner": "alpha"}},
+		}, "e2e-manager-a", false)
+		framework.ExpectNoError(err, "initial server-side apply to Namespace %s failed", ns.Name)
+		framework.ExpectEqual(applied.Labels["e2e-ssa-owner"], "alpha", "applied label missing from Namespace %s", ns.Name)
+		managers := framework.ManagedFieldsManagers(applied, metav1.ManagedFieldsOperationApply)
+		framework.ExpectEqual(len(managers) > 0 && managers[0] == "e2e-manager-a", true,
+			"managedFields of Namespace %s do not record e2e-manager-a as an Apply manager: %v", ns.Name, managers)
+
+		ginkgo.By("applying a conflicting value under a second manager without force")
+		_, err = framework.ServerSideApplyNamespace(f.ClientSet, &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: ns.Name, Labels: map[string]string{"e2e-ssa-owner": "beta"}},
+		}, "e2e-manager-b", false)
+		framework.ExpectEqual(apierrors.IsConflict(err), true,
+			"non-forced apply over a field owned by another manager returned %v instead of Conflict", err)
+
+		ginkgo.By("forcing the second manager's apply and verifying ownership moved")
+		forced, err := framework.ServerSideApplyNamespace(f.ClientSet, &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: ns.Name, Labels: map[string]string{"e2e-ssa-owner": "beta"}},
+		}, "e2e-manager-b", true)
+		framework.ExpectNoError(err, "forced server-side apply to Namespace %s failed", ns.Name)
+		framework.ExpectEqual(forced.Labels["e2e-ssa-owner"], "beta", "forced apply did not take over the label on Namespace %s", ns.Name)
+		ownsField := false
+		for _, manager := range framework.ManagedFieldsManagers(forced, metav1.ManagedFieldsOperationApply) {
+			if manager == "e2e-manager-b" {
+				ownsField = true
+			}
+		}
+		framework.ExpectEqual(ownsField, true, "managedFields of Namespace %s do not record e2e-manager-b after the forced apply", ns.Name)
+
+		ginkgo.By("applying a disjoint label through the unstructured client under a third manager")
+		manifest := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata": map[string]interface{}{
+				"name":   ns.Name,
+				"labels": map[string]interface{}{"e2e-ssa-extra": "gamma"},
+			},
+		}}
+		result, err := framework.ServerSideApplyUnstructured(f.DynamicClient,
+			schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, "", manifest, "e2e-manager-c", false)
+		framework.ExpectNoError(err, "unstructured server-side apply to Namespace %s failed", ns.Name)
+		framework.ExpectEqual(result.GetLabels()["e2e-ssa-extra"], "gamma",
+			"unstructured apply did not set its label on Namespace %s", ns.Name)
+		framework.ExpectEqual(result.GetLabels()["e2e-ssa-owner"], "beta",
+			"disjoint apply under a third manager disturbed another manager's field on Namespace %s", ns.Name)
+	})
+
+	ginkgo.It("should complete the generic dynamic lifecycle for configmaps and namespaces", func() {
+		ginkgo.By("Running the dynamic lifecycle against configmaps in the test namespace")
+		configMapHarness := dynamicresource.NewHarness(f.DynamicClient,
+			schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, f.Namespace.Name)
+		configMap := &unstructured.Unstructured{}
+		configMap.SetAPIVersion("v1")
+		configMap.SetKind("ConfigMap")
+		configMap.SetGenerateName("dynamic-lifecycle-")
+		configMapHarness.RunLifecycle(configMap, dynamicresource.Options{})
+
+		ginkgo.By("Running the dynamic lifecycle against namespaces")
+		namespaceHarness := dynamicresource.NewHarness(f.DynamicClient,
+			schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, "")
+		namespace := &unstructured.Unstructured{}
+		namespace.SetAPIVersion("v1")
+		namespace.SetKind("Namespace")
+		namespace.SetGenerateName("nsdynamiclifecycle-")
+		namespaceHarness.RunLifecycle(namespace, dynamicresource.Options{
+			// Namespaces do not serve deleteCollection.
+			SkipDeleteCollection: true,
+			DeletionTimeout:      5 * time.Minute,
+			PostCreate: func(obj *unstructured.Unstructured) {
+				// Even if a later lifecycle step fails, the framework must
+				// still tear the namespace down.
+				f.AddNamespacesToDelete(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: obj.GetName()}})
+			},
+		})
+	})
+
+})
+
+// namespaceControllerBusy reports whether the namespace controller is already
+// draining a significant backlog for other tests, which would skew the
+// relaxed parallel-lane deletion SLO below. Detection is best effort:
+// clusters where controller-manager metrics cannot be grabbed report false.
+func namespaceControllerBusy(f *framework.Framework, maxQueueDepth float64) bool {
+	grabber, err := e2emetrics.NewMetricsGrabber(f.ClientSet, nil, f.ClientConfig(), false, false, true, false, false, false)
+	if err != nil {
+		framework.Logf("Cannot check for namespace deletion interference: %v", err)
+		return false
+	}
+	metrics, err := grabber.GrabFromControllerManager()
+	if err != nil {
+		framework.Logf("Cannot grab controller-manager metrics to check for interference: %v", err)
+		return false
+	}
+	for _, sample := range metrics["workqueue_depth"] {
+		if string(sample.Metric["name"]) == "namespace" && float64(sample.Value) > maxQueueDepth {
+			framework.Logf("Namespace controller workqueue depth is %v (max tolerated %v)", sample.Value, maxQueueDepth)
+			return true
+		}
+	}
+	return false
+}
+
+// The scaled-down churn below stays within what the parallel lane tolerates:
+// 20 namespaces with a relaxed remainder give faster deletion-speed signal
+// than the [Serial] 100-namespace soaks without monopolizing the namespace
+// controller. Since other specs delete namespaces too, the spec first checks
+// the controller's queue and skips rather than report a flaky SLO violation.
+var _ = SIGDescribe("Namespaces", func() {
+
+	f := framework.NewDefaultFramework("namespaces-parallel")
+	f.NamespacePodSecurityEnforceLevel = admissionapi.LevelBaseline
+
+	ginkgo.It("should delete a small batch of namespaces fast enough for parallel-lane signal [Feature:NamespaceDeletionSpeedParallel]", func() {
+		if namespaceControllerBusy(f, 10) {
+			e2eskipper.Skipf("namespace controller is busy with other deletions; deletion-speed signal would be unreliable")
+		}
+		extinguish(f, 20, 2, 150)
+	})
+})
+
+// The generated patch-type coverage below exercises every wire format the
+// apiserver accepts for patches, not just the StrategicMergePatch the
+// conformance spec above uses. Namespaces cover the cluster-scoped path and
+// ConfigMaps the namespaced one.
+var _ = SIGDescribe("Namespace patch-type coverage", func() {
+
+	f := framework.NewDefaultFramework("nspatch")
+	// Both fixtures provision what they patch themselves, so the default
+	// framework namespace would just be per-spec create/delete overhead in
+	// the serial lane.
+	f.SkipNamespaceCreation = true
+
+	framework.DescribePatchCoverage(f, framework.PatchCoverageFixture{
+		Kind: "Namespace",
+		GVR:  schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+		Create: func(f *framework.Framework) (string, string, error) {
+			ns, err := f.CreateNamespace("nspatchtarget", nil)
+			if err != nil {
+				return "", "", err
+			}
+			return ns.Name, "", nil
+		},
+	})
+
+	framework.DescribePatchCoverage(f, framework.PatchCoverageFixture{
+		Kind: "ConfigMap",
+		GVR:  schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		Create: func(f *framework.Framework) (string, string, error) {
+			ns, err := f.CreateNamespace("nspatch-cm", nil)
+			if err != nil {
+				return "", "", err
+			}
+			cm, err := f.ClientSet.CoreV1().ConfigMaps(ns.Name).Create(context.TODO(), &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "patch-coverage-target",
+					Labels: map[string]string{"e2e-run": string(framework.RunID)},
+				},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				return "", "", err
+			}
+			return cm.Name, cm.Namespace, nil
+		},
+	})
 })
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apimachinery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kubernetes/test/e2e/framework"
+	e2eskipper "k8s.io/kubernetes/test/e2e/framework/skipper"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// storageVersionMigrationGVR is the StorageVersionMigration resource served
+// by the out-of-tree kube-storage-version-migrator. Clusters without the
+// migrator simply do not serve it, which is what the skip below checks.
+var storageVersionMigrationGVR = schema.GroupVersionResource{
+	Group:    "migration.k8s.io",
+	Version:  "v1alpha1",
+	Resource: "storageversionmigrations",
+}
+
+// This test requires the kube-storage-version-migrator to be installed; it is
+// Disruptive because a storage migration rewrites every stored object of the
+// migrated resource.
+var _ = SIGDescribe("Namespace deletion during storage version migration [Disruptive] [Feature:StorageVersionMigration]", func() {
+	f := framework.NewDefaultFramework("svm")
+	// The spec provisions its own namespace to migrate and delete; the
+	// framework-managed one would only add lifecycle overhead per spec.
+	f.SkipNamespaceCreation = true
+
+	ginkgo.It("should let a namespace terminate while its resource type is being migrated", func() {
+		e2eskipper.SkipIfMissingResource(f.DynamicClient, storageVersionMigrationGVR, "")
+
+		ginkgo.By("Creating a namespace populated with configmaps to give the migration work to do")
+		namespace, err := f.CreateNamespace("svm-target", nil)
+		framework.ExpectNoError(err, "failed to create the namespace to migrate and delete")
+		for i := 0; i < 20; i++ {
+			cm := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("svm-cm-%d", i)},
+				Data:       map[string]string{"index": fmt.Sprintf("%d", i)},
+			}
+			_, err := f.ClientSet.CoreV1().ConfigMaps(namespace.Name).Create(context.TODO(), cm, metav1.CreateOptions{})
+			framework.ExpectNoError(err, "failed to create configmap %s", cm.Name)
+		}
+
+		ginkgo.By("Triggering a storage version migration for configmaps")
+		migration, err := startStorageVersionMigration(f.DynamicClient, schema.GroupVersionResource{Version: "v1", Resource: "configmaps"})
+		framework.ExpectNoError(err, "failed to trigger the storage version migration")
+		defer deleteStorageVersionMigration(f.DynamicClient, migration.GetName())
+
+		ginkgo.By("Deleting the namespace while the migration is running")
+		err = f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete namespace %s", namespace.Name)
+		err = framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, framework.DefaultNamespaceDeletionTimeout)
+		framework.ExpectNoError(err, "namespace %s wedged while configmaps were being migrated", namespace.Name)
+
+		ginkgo.By("Waiting for the migration to reach a terminal condition")
+		outcome, err := waitForStorageVersionMigrationTerminal(f.DynamicClient, migration.GetName(), 10*time.Minute)
+		framework.ExpectNoError(err, "migration %s wedged while namespace %s was deleted under it", migration.GetName(), namespace.Name)
+		framework.ExpectEqual(outcome, "Succeeded",
+			"migration %s should tolerate the migrated namespace disappearing", migration.GetName())
+	})
+})
+
+// startStorageVersionMigration creates a StorageVersionMigration for the
+// given resource and records it in the suite checkpoint.
+func startStorageVersionMigration(dc dynamic.Interface, resource schema.GroupVersionResource) (*unstructured.Unstructured, error) {
+	migration := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": storageVersionMigrationGVR.GroupVersion().String(),
+		"kind":       "StorageVersionMigration",
+		"metadata": map[string]interface{}{
+			"generateName": "e2e-svm-",
+		},
+		"spec": map[string]interface{}{
+			"resource": map[string]interface{}{
+				"group":    resource.Group,
+				"version":  resource.Version,
+				"resource": resource.Resource,
+			},
+		},
+	}}
+	created, err := dc.Resource(storageVersionMigrationGVR).Create(context.TODO(), migration, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	framework.CheckpointClusterScopedCreated(storageVersionMigrationGVR, created.GetName())
+	return created, nil
+}
+
+// deleteStorageVersionMigration best-effort deletes a migration object and
+// drops it from the suite checkpoint.
+func deleteStorageVersionMigration(dc dynamic.Interface, name string) {
+	err := dc.Resource(storageVersionMigrationGVR).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		framework.Logf("Failed to delete storage version migration %s: %v", name, err)
+		return
+	}
+	framework.CheckpointClusterScopedDeleted(storageVersionMigrationGVR, name)
+}
+
+// waitForStorageVersionMigrationTerminal polls the migration until it reports
+// a Succeeded or Failed condition and returns which one it was.
+func waitForStorageVersionMigrationTerminal(dc dynamic.Interface, name string, timeout time.Duration) (string, error) {
+	var outcome string
+	err := wait.PollImmediate(framework.Poll, timeout, func() (bool, error) {
+		migration, err := dc.Resource(storageVersionMigrationGVR).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		conditions, _, err := unstructured.NestedSlice(migration.Object, "status", "conditions")
+		if err != nil {
+			return false, err
+		}
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			conditionType, _, _ := unstructured.NestedString(condition, "type")
+			conditionStatus, _, _ := unstructured.NestedString(condition, "status")
+			if conditionStatus != string(v1.ConditionTrue) {
+				continue
+			}
+			if conditionType == "Succeeded" || conditionType == "Failed" {
+				outcome = conditionType
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("migration %s never reached a terminal condition: %v", name, err)
+	}
+	return outcome, nil
+}
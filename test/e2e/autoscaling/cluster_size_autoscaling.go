@@ -370,6 +370,58 @@ var _ = SIGDescribe("Cluster size autoscaling [Slow]", func() {
 		framework.ExpectEqual(len(nodes.Items), status.target+unmanagedNodes)
 	})
 
+	ginkgo.It("should cancel ongoing scale-up when the namespace holding the pending pods is deleted [Feature:ClusterSizeAutoscaling]", func() {
+		// Wait for the situation to stabilize - CA should be running and have up-to-date node readiness info.
+		_, err := waitForScaleUpStatus(c, func(s *scaleUpStatus) bool {
+			return s.ready == s.target && s.ready <= nodeCount
+		}, scaleUpTriggerTimeout)
+		framework.ExpectNoError(err)
+
+		ginkgo.By("Creating a scratch namespace for the pending pods")
+		namespace, err := f.CreateNamespace("autoscaling-nsdelete", nil)
+		framework.ExpectNoError(err)
+
+		ginkgo.By("Scheduling more pods than can fit in the scratch namespace")
+		request := int64(1024 * 1024 * nodeCount * memAllocatableMb / 100)
+		config := &testutils.RCConfig{
+			Client:     f.ClientSet,
+			Name:       "memory-reservation-nsdelete",
+			Namespace:  namespace.Name,
+			Timeout:    time.Second,
+			Image:      imageutils.GetPauseImageName(),
+			Replicas:   100,
+			MemRequest: request,
+		}
+		// The pods are meant to stay pending; the RunRC timeout error only
+		// says they did not all start.
+		if err := e2erc.RunRC(*config); err != nil {
+			klog.Infof("Ignoring RC startup error for intentionally pending pods: %v", err)
+		}
+
+		ginkgo.By("Waiting for a scale-up to be in progress")
+		_, err = waitForScaleUpStatus(c, func(s *scaleUpStatus) bool {
+			return s.status == caOngoingScaleUpStatus
+		}, scaleUpTriggerTimeout)
+		framework.ExpectNoError(err)
+
+		ginkgo.By("Deleting the namespace mid-scale-up")
+		framework.ExpectNoError(f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{}))
+		framework.ExpectNoError(framework.WaitForNamespacesDeleted(f.ClientSet, []string{namespace.Name}, scaleUpTimeout))
+
+		ginkgo.By("Waiting for the scale-up to be cancelled or finished with nothing left to do")
+		status, err := waitForScaleUpStatus(c, func(s *scaleUpStatus) bool {
+			return s.status == caNoScaleUpStatus
+		}, scaleUpTimeout)
+		framework.ExpectNoError(err)
+		// Ready matching the cloud provider target means no machine resources
+		// are stuck being created for pods that no longer exist.
+		framework.ExpectEqual(status.ready, status.target, "cloud provider target disagrees with ready nodes after the namespace was deleted")
+
+		ginkgo.By("Waiting for the cluster to return to its original size")
+		framework.ExpectNoError(WaitForClusterSizeFunc(f.ClientSet,
+			func(size int) bool { return size <= nodeCount }, scaleDownTimeout))
+	})
+
 	ginkgo.It("should increase cluster size if pending pods are small and there is another node pool that is not autoscaled [Feature:ClusterSizeAutoscalingScaleUp]", func() {
 		e2eskipper.SkipUnlessProviderIs("gke")
 
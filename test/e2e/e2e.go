@@ -36,6 +36,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/component-base/logs"
 	"k8s.io/component-base/version"
 	commontest "k8s.io/kubernetes/test/e2e/common"
@@ -57,6 +58,7 @@ import (
 	_ "k8s.io/kubernetes/test/e2e/framework/providers/gce"
 	_ "k8s.io/kubernetes/test/e2e/framework/providers/kubemark"
 	_ "k8s.io/kubernetes/test/e2e/framework/providers/openstack"
+	_ "k8s.io/kubernetes/test/e2e/framework/providers/vcluster"
 	_ "k8s.io/kubernetes/test/e2e/framework/providers/vsphere"
 
 	// Ensure that logging flags are part of the command line.
@@ -100,6 +102,14 @@ func RunE2ETests(t *testing.T) {
 	logs.InitLogs()
 	defer logs.FlushLogs()
 	progressReporter = e2ereporters.NewProgressReporter(framework.TestContext.ProgressReportURL)
+	if address := framework.TestContext.ProgressListenAddress; address != "" {
+		if stop, err := e2ereporters.StartProgressServer(progressReporter, address); err != nil {
+			// In parallel runs only the first node to bind the address serves.
+			klog.Warningf("Not serving progress endpoint: %v", err)
+		} else {
+			defer stop()
+		}
+	}
 	gomega.RegisterFailHandler(framework.Fail)
 
 	// Run tests through the Ginkgo runner with output to console + JUnit for Jenkins
@@ -194,6 +204,40 @@ func setupSuite() {
 		klog.Fatal("Error loading client: ", err)
 	}
 
+	// Include live namespace counts in progress updates so that anyone
+	// following the webhook can correlate slow specs with namespace pile-up.
+	progressReporter.SetNamespaceCountFunc(func() (int, int, error) {
+		nsList, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return 0, 0, err
+		}
+		active, terminating := 0, 0
+		for _, ns := range nsList.Items {
+			if ns.Status.Phase == v1.NamespaceTerminating {
+				terminating++
+			} else {
+				active++
+			}
+		}
+		return active, terminating, nil
+	})
+
+	// An interrupted run leaves its leftovers listed in the suite checkpoint;
+	// reap them first so they cannot cascade into this run's soaks.
+	if framework.TestContext.CleanupFromCheckpoint {
+		config, err := framework.LoadConfig()
+		if err != nil {
+			klog.Fatal("Error loading client config: ", err)
+		}
+		dc, err := dynamic.NewForConfig(config)
+		if err != nil {
+			klog.Fatal("Error loading dynamic client: ", err)
+		}
+		if err := framework.CleanupFromCheckpoint(c, dc); err != nil {
+			framework.Failf("Error cleaning up from the suite checkpoint: %v", err)
+		}
+	}
+
 	// Delete any namespaces except those created by the system. This ensures no
 	// lingering resources are left over from a previous test run.
 	if framework.TestContext.CleanStart {
@@ -212,6 +256,19 @@ func setupSuite() {
 		}
 	}
 
+	// Even without a clean start, namespaces leaked by earlier aborted runs
+	// poison the counts of the lifecycle soaks. Reap the ones old enough that
+	// no concurrent run can still own them.
+	if maxAge := framework.TestContext.LeakedNamespaceMaxAge; maxAge > 0 {
+		deleted, err := framework.CleanupLeakedTestNamespaces(c, maxAge)
+		if err != nil {
+			framework.Logf("WARNING: failed to clean up leaked test namespaces: %v", err)
+		}
+		if len(deleted) > 0 {
+			framework.Logf("Deleted %d namespaces leaked by earlier e2e runs: %v", len(deleted), deleted)
+		}
+	}
+
 	// In large clusters we may get to this point but still have a bunch
 	// of nodes without Routes created. Since this would make a node
 	// unschedulable, we need to wait until all of them are schedulable.
@@ -251,6 +308,10 @@ func setupSuite() {
 	// Log the version of the server and this client.
 	framework.Logf("e2e test version: %s", version.Get().GitVersion)
 
+	// Focused suite binaries compile in a subset of the SIG packages; record
+	// which ones this binary actually contains.
+	framework.Logf("Suite composed of SIGs: %v", framework.RegisteredSIGs())
+
 	dc := c.DiscoveryClient
 
 	serverVersion, serverErr := dc.ServerVersion()
@@ -265,6 +326,11 @@ func setupSuite() {
 		nodeKiller := framework.NewNodeKiller(framework.TestContext.NodeKiller, c, framework.TestContext.Provider)
 		go nodeKiller.Run(framework.TestContext.NodeKiller.NodeKillerStopCh)
 	}
+
+	// The opt-in background canary measures namespace lifecycle latency for
+	// the whole run; its timeline separates pre-existing cluster degradation
+	// from degradation caused by the lifecycle soaks.
+	framework.StartSuiteNamespaceCanary(c)
 }
 
 // logClusterImageSources writes out cluster image sources.
@@ -23,7 +23,6 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
-	"time"
 
 	"github.com/onsi/ginkgo/v2"
 	"gopkg.in/yaml.v2"
@@ -131,7 +130,7 @@ func TestMain(m *testing.M) {
 		testfiles.AddFileSource(testfiles.RootFileSource{Root: framework.TestContext.RepoRoot})
 	}
 
-	rand.Seed(time.Now().UnixNano())
+	rand.Seed(framework.InitializeTestSeed())
 	os.Exit(m.Run())
 }
 
@@ -139,11 +138,17 @@ func TestE2E(t *testing.T) {
 	RunE2ETests(t)
 }
 
+var _ = ginkgo.ReportBeforeEach(func(report ginkgo.SpecReport) {
+	progressReporter.ProcessSpecStart(report)
+})
+
 var _ = ginkgo.ReportAfterEach(func(report ginkgo.SpecReport) {
 	progressReporter.ProcessSpecReport(report)
 })
 
 var _ = ginkgo.ReportAfterSuite("Kubernetes e2e suite report", func(report ginkgo.Report) {
+	framework.WriteEnrichedJSONReport(report)
+
 	var err error
 	// The DetailsRepoerter will output details about every test (name, files, lines, etc) which helps
 	// when documenting our tests.
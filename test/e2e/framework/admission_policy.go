@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// ValidatingAdmissionPolicy is alpha and not part of the typed clientset
+// vendored here, so the fixture installer drives it through the dynamic
+// client and callers gate on ServerSupportsAdmissionPolicy.
+var (
+	validatingAdmissionPolicyGVR = schema.GroupVersionResource{
+		Group:    "admissionregistration.k8s.io",
+		Version:  "v1alpha1",
+		Resource: "validatingadmissionpolicies",
+	}
+	validatingAdmissionPolicyBindingGVR = schema.GroupVersionResource{
+		Group:    "admissionregistration.k8s.io",
+		Version:  "v1alpha1",
+		Resource: "validatingadmissionpolicybindings",
+	}
+)
+
+// ServerSupportsAdmissionPolicy reports whether the cluster serves the
+// ValidatingAdmissionPolicy API.
+func ServerSupportsAdmissionPolicy(c clientset.Interface) bool {
+	resourceList, err := c.Discovery().ServerResourcesForGroupVersion(validatingAdmissionPolicyGVR.GroupVersion().String())
+	if err != nil {
+		return false
+	}
+	for _, resource := range resourceList.APIResources {
+		if resource.Name == validatingAdmissionPolicyGVR.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// InstallNamespaceDeleteDenyPolicy installs a ValidatingAdmissionPolicy and a
+// binding that deny DELETE of namespaces carrying the given label, failing
+// closed with the given message. It returns a cleanup function removing both
+// objects; cleanup is safe to call even after a partial install.
+func InstallNamespaceDeleteDenyPolicy(dc dynamic.Interface, name, labelKey, labelValue, message string) (func(), error) {
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": validatingAdmissionPolicyGVR.GroupVersion().String(),
+		"kind":       "ValidatingAdmissionPolicy",
+		"metadata": map[string]interface{}{
+			"name": name,
+		},
+		"spec": map[string]interface{}{
+			"failurePolicy": "Fail",
+			"matchConstraints": map[string]interface{}{
+				"resourceRules": []interface{}{
+					map[string]interface{}{
+						"apiGroups":   []interface{}{""},
+						"apiVersions": []interface{}{"v1"},
+						"operations":  []interface{}{"DELETE"},
+						"resources":   []interface{}{"namespaces"},
+					},
+				},
+			},
+			"validations": []interface{}{
+				map[string]interface{}{
+					"expression": "false",
+					"message":    message,
+				},
+			},
+		},
+	}}
+	binding := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": validatingAdmissionPolicyBindingGVR.GroupVersion().String(),
+		"kind":       "ValidatingAdmissionPolicyBinding",
+		"metadata": map[string]interface{}{
+			"name": name,
+		},
+		"spec": map[string]interface{}{
+			"policyName": name,
+			"matchResources": map[string]interface{}{
+				"objectSelector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						labelKey: labelValue,
+					},
+				},
+			},
+		},
+	}}
+	cleanup := func() {
+		if err := dc.Resource(validatingAdmissionPolicyBindingGVR).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+			Logf("Failed to delete ValidatingAdmissionPolicyBinding %s: %v", name, err)
+		}
+		if err := dc.Resource(validatingAdmissionPolicyGVR).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+			Logf("Failed to delete ValidatingAdmissionPolicy %s: %v", name, err)
+		}
+	}
+	if _, err := dc.Resource(validatingAdmissionPolicyGVR).Create(context.TODO(), policy, metav1.CreateOptions{}); err != nil {
+		return func() {}, fmt.Errorf("failed to create ValidatingAdmissionPolicy %s: %v", name, err)
+	}
+	if _, err := dc.Resource(validatingAdmissionPolicyBindingGVR).Create(context.TODO(), binding, metav1.CreateOptions{}); err != nil {
+		return cleanup, fmt.Errorf("failed to create ValidatingAdmissionPolicyBinding %s: %v", name, err)
+	}
+	return cleanup, nil
+}
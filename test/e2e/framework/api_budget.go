@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// APICallRecord is one aggregated class of API requests issued during a spec.
+type APICallRecord struct {
+	Verb  string `json:"verb"`
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+}
+
+type apiCallKey struct {
+	verb string
+	path string
+}
+
+// APICallSummary counts every API request the framework clients issue during
+// one spec, aggregated per verb and normalized path. With a configured
+// budget it turns test-induced apiserver load into something that can be
+// tracked and regressed on.
+type APICallSummary struct {
+	mu     sync.Mutex
+	total  int
+	counts map[apiCallKey]int
+}
+
+// NewAPICallSummary returns an empty summary.
+func NewAPICallSummary() *APICallSummary {
+	return &APICallSummary{counts: map[apiCallKey]int{}}
+}
+
+func (s *APICallSummary) record(verb, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.total++
+	s.counts[apiCallKey{verb: verb, path: normalizeAPIPath(path)}]++
+}
+
+// Total returns the number of API calls recorded so far.
+func (s *APICallSummary) Total() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
+
+// Records returns the aggregated records sorted by count, largest first.
+func (s *APICallSummary) Records() []APICallRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]APICallRecord, 0, len(s.counts))
+	for key, count := range s.counts {
+		records = append(records, APICallRecord{Verb: key.verb, Path: key.path, Count: count})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Count > records[j].Count })
+	return records
+}
+
+// EnforceBudget compares the recorded call count against the configured
+// budget and, when exceeded, either fails the spec or logs a warning naming
+// the top offenders, depending on the action ("fail" or "warn").
+func (s *APICallSummary) EnforceBudget(budget int, action string) {
+	total := s.Total()
+	if budget <= 0 || total <= budget {
+		return
+	}
+	var top []string
+	for i, record := range s.Records() {
+		if i == 5 {
+			break
+		}
+		top = append(top, fmt.Sprintf("%d x %s %s", record.Count, record.Verb, record.Path))
+	}
+	message := fmt.Sprintf("spec issued %d API calls, exceeding the budget of %d; top offenders: %s",
+		total, budget, strings.Join(top, "; "))
+	if action == "fail" {
+		Failf(message)
+	} else {
+		Logf("WARNING: %s", message)
+	}
+}
+
+var (
+	currentAPICallSummary     *APICallSummary
+	currentAPICallSummaryLock sync.Mutex
+)
+
+// setCurrentAPICallSummary installs (or clears, with nil) the summary that
+// counts API calls for the spec that is about to run.
+func setCurrentAPICallSummary(summary *APICallSummary) {
+	currentAPICallSummaryLock.Lock()
+	defer currentAPICallSummaryLock.Unlock()
+	currentAPICallSummary = summary
+}
+
+func recordAPICall(verb, path string) {
+	currentAPICallSummaryLock.Lock()
+	summary := currentAPICallSummary
+	currentAPICallSummaryLock.Unlock()
+	if summary != nil {
+		summary.record(verb, path)
+	}
+}
+
+type apiCallCountingTransport struct {
+	delegate http.RoundTripper
+}
+
+func (t *apiCallCountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recordAPICall(req.Method, req.URL.Path)
+	return t.delegate.RoundTrip(req)
+}
+
+// wrapTransportForAPICallAccounting is installed into the framework client
+// config when an API call budget is configured, so every request counts
+// against the current spec's summary.
+func wrapTransportForAPICallAccounting(rt http.RoundTripper) http.RoundTripper {
+	return &apiCallCountingTransport{delegate: rt}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// APIErrorRecord is one aggregated class of non-2xx API responses observed
+// during a spec.
+type APIErrorRecord struct {
+	Verb  string `json:"verb"`
+	Path  string `json:"path"`
+	Code  int    `json:"code"`
+	Count int    `json:"count"`
+}
+
+type apiErrorKey struct {
+	verb string
+	path string
+	code int
+}
+
+// APIErrorSummary aggregates all non-2xx responses seen by the framework
+// clients during one spec. The soak tests retry through thousands of
+// conflict and timeout responses that otherwise never surface anywhere.
+type APIErrorSummary struct {
+	mu     sync.Mutex
+	counts map[apiErrorKey]int
+}
+
+// NewAPIErrorSummary returns an empty summary.
+func NewAPIErrorSummary() *APIErrorSummary {
+	return &APIErrorSummary{counts: map[apiErrorKey]int{}}
+}
+
+func (s *APIErrorSummary) record(verb, path string, code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[apiErrorKey{verb: verb, path: normalizeAPIPath(path), code: code}]++
+}
+
+// Records returns the aggregated records sorted by count, largest first.
+func (s *APIErrorSummary) Records() []APIErrorRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]APIErrorRecord, 0, len(s.counts))
+	for key, count := range s.counts {
+		records = append(records, APIErrorRecord{Verb: key.verb, Path: key.path, Code: key.code, Count: count})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Count > records[j].Count })
+	return records
+}
+
+// WriteArtifact stores the summary as a JSON artifact named after the tag in
+// the report directory, or logs it when no report directory is configured.
+// Empty summaries produce no output.
+func (s *APIErrorSummary) WriteArtifact(tag string) {
+	records := s.Records()
+	if len(records) == 0 {
+		return
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		Logf("Failed to encode API error summary: %v", err)
+		return
+	}
+	if TestContext.ReportDir == "" {
+		Logf("API error summary for %q:\n%s", tag, string(data))
+		return
+	}
+	filePath, err := SpecArtifactPath(fmt.Sprintf("api_errors_%s.json", tag))
+	if err != nil {
+		Logf("Failed to place API error summary: %v", err)
+		return
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		Logf("Failed to write API error summary to %q: %v", filePath, err)
+	}
+}
+
+// normalizeAPIPath collapses object-specific path segments so that responses
+// for many namespaces or objects aggregate into one record.
+func normalizeAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == "namespaces" && segments[i+1] != "" {
+			segments[i+1] = "{namespace}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+var (
+	currentAPIErrorSummary     *APIErrorSummary
+	currentAPIErrorSummaryLock sync.Mutex
+)
+
+// setCurrentAPIErrorSummary installs (or clears, with nil) the summary that
+// captures non-2xx responses for the spec that is about to run.
+func setCurrentAPIErrorSummary(summary *APIErrorSummary) {
+	currentAPIErrorSummaryLock.Lock()
+	defer currentAPIErrorSummaryLock.Unlock()
+	currentAPIErrorSummary = summary
+}
+
+func recordAPIError(verb, path string, code int) {
+	currentAPIErrorSummaryLock.Lock()
+	summary := currentAPIErrorSummary
+	currentAPIErrorSummaryLock.Unlock()
+	if summary != nil {
+		summary.record(verb, path, code)
+	}
+}
+
+type apiErrorCapturingTransport struct {
+	delegate http.RoundTripper
+}
+
+func (t *apiErrorCapturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.delegate.RoundTrip(req)
+	if err == nil && resp != nil && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		recordAPIError(req.Method, req.URL.Path, resp.StatusCode)
+	}
+	// This transport is always installed, which makes it the cheapest place
+	// to count the objects a spec creates for its timing summary.
+	if err == nil && resp != nil && req.Method == http.MethodPost && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		recordSpecObjectCreated()
+	}
+	return resp, err
+}
+
+// wrapTransportForAPIErrorCapture is installed into the framework client
+// config so every non-2xx response is visible to the current spec's summary.
+func wrapTransportForAPIErrorCapture(rt http.RoundTripper) http.RoundTripper {
+	return &apiErrorCapturingTransport{delegate: rt}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+)
+
+// hungStackBufferSize bounds the goroutine dump taken for a hung API call.
+const hungStackBufferSize = 10 << 20
+
+// apiCallWatchdogTransport aborts API calls that block beyond a hard ceiling
+// without carrying their own deadline. Hung calls otherwise stall a spec
+// until the suite timeout, by which point the interesting stacks are gone;
+// the watchdog dumps all goroutine stacks while the call is still blocked,
+// then returns an error so the failure surfaces at the call site.
+type apiCallWatchdogTransport struct {
+	delegate http.RoundTripper
+	ceiling  time.Duration
+}
+
+func (t *apiCallWatchdogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Calls that bound themselves need no supervision, and watches block by
+	// design.
+	if _, hasDeadline := req.Context().Deadline(); hasDeadline || req.URL.Query().Get("watch") == "true" {
+		return t.delegate.RoundTrip(req)
+	}
+
+	type roundTripResult struct {
+		resp *http.Response
+		err  error
+	}
+	resultCh := make(chan roundTripResult, 1)
+	go func() {
+		resp, err := t.delegate.RoundTrip(req)
+		resultCh <- roundTripResult{resp: resp, err: err}
+	}()
+
+	timer := time.NewTimer(t.ceiling)
+	defer timer.Stop()
+	select {
+	case result := <-resultCh:
+		return result.resp, result.err
+	case <-timer.C:
+		// Dump while the call is still hung so its stack is in the dump.
+		// The call itself is abandoned: whenever the connection finally
+		// unblocks, the drain below disposes of the late response.
+		dumpHungAPICall(req, t.ceiling)
+		go func() {
+			if result := <-resultCh; result.resp != nil {
+				result.resp.Body.Close()
+			}
+		}()
+		return nil, fmt.Errorf("API call watchdog: %s %s blocked for more than %v without a deadline; goroutine stacks were dumped", req.Method, req.URL.Path, t.ceiling)
+	}
+}
+
+// dumpHungAPICall writes the request details and a full goroutine dump to the
+// report directory (or the log) so the blocked code path can be identified.
+func dumpHungAPICall(req *http.Request, ceiling time.Duration) {
+	Logf("API call watchdog: %s %s has been blocked for more than %v without a deadline, dumping goroutine stacks", req.Method, req.URL.Path, ceiling)
+	buf := make([]byte, hungStackBufferSize)
+	buf = buf[:runtime.Stack(buf, true)]
+	header := fmt.Sprintf("hung API call: %s %s (blocked > %v)\n\n", req.Method, req.URL.String(), ceiling)
+	if TestContext.ReportDir == "" {
+		Logf("%s%s", header, string(buf))
+		return
+	}
+	filePath, err := SpecArtifactPath(fmt.Sprintf("hung_api_call_%d.txt", time.Now().UnixNano()))
+	if err != nil {
+		Logf("Failed to place hung API call dump: %v", err)
+		Logf("%s%s", header, string(buf))
+		return
+	}
+	if err := os.WriteFile(filePath, append([]byte(header), buf...), 0644); err != nil {
+		Logf("Failed to write hung API call dump to %q: %v", filePath, err)
+		Logf("%s%s", header, string(buf))
+		return
+	}
+	Logf("Wrote hung API call dump to %s", filePath)
+}
+
+// wrapTransportForAPICallWatchdog supervises deadline-less API calls with the
+// configured hard ceiling.
+func wrapTransportForAPICallWatchdog(rt http.RoundTripper) http.RoundTripper {
+	return &apiCallWatchdogTransport{delegate: rt, ceiling: TestContext.APICallHardCeiling}
+}
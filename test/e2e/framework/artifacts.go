@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// The artifact writers in this package historically dropped their files
+// directly into the report dir, which turns a soak-heavy run into one flat
+// directory of hundreds of JSON files. With --per-spec-artifacts the layout
+// becomes
+//
+//	<report-dir>/<sanitized spec name>/attempt-N/<artifact>
+//
+// so CI collection can archive and link per-spec diagnostics (timelines,
+// dumps, metrics snapshots) as one unit, and retried specs keep the
+// artifacts of every attempt. Suite-level artifacts (the checkpoint, the
+// seed, the enriched report) stay at the root.
+
+// SpecArtifactPath returns where a diagnostic artifact with the given file
+// name should be written, creating the directory if needed. Inside a spec
+// with --per-spec-artifacts set that is the spec's attempt directory;
+// otherwise (suite-level callers, or the flag unset) it falls back to the
+// report dir root. An error means no report directory is configured at all.
+func SpecArtifactPath(filename string) (string, error) {
+	if TestContext.ReportDir == "" {
+		return "", fmt.Errorf("no report directory configured")
+	}
+	dir := TestContext.ReportDir
+	if TestContext.PerSpecArtifacts {
+		if report := ginkgo.CurrentSpecReport(); report.LeafNodeText != "" {
+			attempt := report.NumAttempts
+			if attempt < 1 {
+				attempt = 1
+			}
+			dir = filepath.Join(dir, sanitizeSpecLogName(report.FullText()), fmt.Sprintf("attempt-%d", attempt))
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create the artifact directory %s: %v", dir, err)
+	}
+	return filepath.Join(dir, filename), nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/onsi/ginkgo/v2"
+
+	e2essh "k8s.io/kubernetes/test/e2e/framework/ssh"
+)
+
+// Audit slice collection. Clusters running the apiserver with audit logging
+// enabled write every request to a log file on the master; when the suite is
+// told where that file lives (--audit-log-path), a failing spec gets the
+// slice of audit events that touched its namespaces during its run written
+// alongside the ginkgo report. The main consumer is namespace deletion
+// timeouts, where the slice shows which client kept recreating content in
+// the terminating namespace.
+
+// auditSliceMaxBytes caps how much of the audit log is pulled over SSH for
+// one spec. The grep below already narrows the slice to the spec's
+// namespaces, so hitting the cap means the spec itself was extremely chatty.
+const auditSliceMaxBytes = 10 << 20
+
+// auditEventSlim is the subset of an audit.k8s.io/v1 event needed to filter
+// the log by time window and namespace. Lines are kept verbatim; this struct
+// only steers the filtering.
+type auditEventSlim struct {
+	StageTimestamp metav1.MicroTime `json:"stageTimestamp"`
+	ObjectRef      *struct {
+		Namespace string `json:"namespace"`
+	} `json:"objectRef"`
+}
+
+// collectAuditSlice returns the audit log lines that reference one of the
+// namespaces and whose stage timestamp falls after since. It reaches the log
+// through SSH to the master, so it only works on providers where
+// e2essh.SSH can reach the control plane.
+func collectAuditSlice(logPath string, since time.Time, namespaces []string) (string, error) {
+	if len(namespaces) == 0 {
+		return "", nil
+	}
+	// Pre-filter on the master so only the spec's slice crosses the wire;
+	// precise time filtering happens below, after parsing.
+	patterns := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		patterns = append(patterns, fmt.Sprintf("-e %q", ns))
+	}
+	cmd := fmt.Sprintf("sudo grep -h -F %s %q | tail -c %d", strings.Join(patterns, " "), logPath, auditSliceMaxBytes)
+	result, err := e2essh.SSH(cmd, net.JoinHostPort(APIAddress(), e2essh.SSHPort), TestContext.Provider)
+	if err != nil {
+		return "", fmt.Errorf("failed to read audit log %s from the master: %v", logPath, err)
+	}
+	if result.Code != 0 {
+		// grep exits 1 on no matches, which simply means an empty slice.
+		if result.Code == 1 && result.Stderr == "" {
+			return "", nil
+		}
+		return "", fmt.Errorf("reading audit log %s failed with code %d: %s", logPath, result.Code, result.Stderr)
+	}
+
+	nsSet := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		nsSet[ns] = true
+	}
+	var kept []string
+	for _, line := range strings.Split(result.Stdout, "\n") {
+		if line == "" {
+			continue
+		}
+		var event auditEventSlim
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			// The cap above can truncate the first line mid-event.
+			continue
+		}
+		if event.ObjectRef == nil || !nsSet[event.ObjectRef.Namespace] {
+			continue
+		}
+		if !event.StageTimestamp.IsZero() && event.StageTimestamp.Time.Before(since) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n"), nil
+}
+
+// dumpAuditSlice collects the spec's audit slice and writes it as an
+// artifact in ReportDir, falling back to a report entry. Collection failures
+// are logged, never failed on: the slice is debugging aid, not test subject.
+func (f *Framework) dumpAuditSlice() {
+	namespaces := make([]string, 0, len(f.namespacesToDelete))
+	for _, ns := range f.namespacesToDelete {
+		if ns != nil {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+	slice, err := collectAuditSlice(TestContext.AuditLogPath, f.specStartTime, namespaces)
+	if err != nil {
+		Logf("Cannot collect the audit slice for this spec: %v", err)
+		return
+	}
+	if slice == "" {
+		Logf("Audit slice for namespaces %v is empty", namespaces)
+		return
+	}
+	if TestContext.ReportDir != "" {
+		filePath, err := SpecArtifactPath(fmt.Sprintf("audit_slice_%s.log", f.UniqueName))
+		if err == nil {
+			if err := os.WriteFile(filePath, []byte(slice), 0644); err == nil {
+				Logf("Wrote the audit slice for namespaces %v to %s", namespaces, filePath)
+				return
+			}
+			Logf("Failed to write the audit slice to %s: %v", filePath, err)
+		} else {
+			Logf("Failed to place the audit slice: %v", err)
+		}
+	}
+	ginkgo.AddReportEntry("audit-slice", slice)
+}
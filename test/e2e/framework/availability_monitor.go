@@ -0,0 +1,246 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// The availability monitor probes the apiserver at a low rate for the
+// duration of Serial and Disruptive specs: /healthz, /readyz and one cheap
+// namespaced GET per round. When a namespace drain misses its deadline the
+// usual suspicion is apiserver overload; the recorded gap timeline turns that
+// suspicion into evidence (or rules it out). Opt-in via
+// --api-availability-probe-interval because even a low-QPS prober is traffic.
+
+// APIProbeResult is one probing round against the apiserver.
+type APIProbeResult struct {
+	Time time.Time
+	// HealthzOK, ReadyzOK and GetOK report the three checks of the round:
+	// the liveness and readiness endpoints plus a GET of the default
+	// namespace through the regular resource path.
+	HealthzOK bool
+	ReadyzOK  bool
+	GetOK     bool
+	// Error carries the first failure of the round.
+	Error string `json:",omitempty"`
+}
+
+// ok reports whether the whole round succeeded.
+func (r APIProbeResult) ok() bool {
+	return r.HealthzOK && r.ReadyzOK && r.GetOK
+}
+
+// AvailabilityGap is a run of consecutive failed probing rounds.
+type AvailabilityGap struct {
+	Start        time.Time
+	End          time.Time
+	FailedProbes int
+	// LastError is the error of the final failed round in the gap.
+	LastError string
+}
+
+// APIAvailabilityMonitor probes the apiserver in the background. Create one
+// with StartAPIAvailabilityMonitor.
+type APIAvailabilityMonitor struct {
+	client   clientset.Interface
+	interval time.Duration
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	probesLock sync.Mutex
+	probes     []APIProbeResult
+}
+
+// StartAPIAvailabilityMonitor begins probing the apiserver every interval
+// until Stop is called.
+func StartAPIAvailabilityMonitor(c clientset.Interface, interval time.Duration) *APIAvailabilityMonitor {
+	monitor := &APIAvailabilityMonitor{
+		client:   c,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	go monitor.run()
+	return monitor
+}
+
+func (m *APIAvailabilityMonitor) run() {
+	defer close(m.doneCh)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.probe()
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probe performs one round of checks. Each check gets its own deadline so a
+// hanging apiserver shows up as a failed round rather than a stalled monitor.
+func (m *APIAvailabilityMonitor) probe() {
+	result := APIProbeResult{Time: time.Now()}
+	ctx, cancel := context.WithTimeout(context.Background(), m.interval)
+	defer cancel()
+
+	recordFailure := func(check string, err error) {
+		if result.Error == "" {
+			result.Error = fmt.Sprintf("%s: %v", check, err)
+		}
+	}
+	if _, err := m.client.Discovery().RESTClient().Get().AbsPath("/healthz").DoRaw(ctx); err != nil {
+		recordFailure("healthz", err)
+	} else {
+		result.HealthzOK = true
+	}
+	if _, err := m.client.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx); err != nil {
+		recordFailure("readyz", err)
+	} else {
+		result.ReadyzOK = true
+	}
+	if _, err := m.client.CoreV1().Namespaces().Get(ctx, metav1.NamespaceDefault, metav1.GetOptions{}); err != nil {
+		recordFailure("get default namespace", err)
+	} else {
+		result.GetOK = true
+	}
+
+	m.probesLock.Lock()
+	m.probes = append(m.probes, result)
+	m.probesLock.Unlock()
+	if result.Error != "" {
+		Logf("API availability probe failed: %s", result.Error)
+	}
+}
+
+// Stop ends the probing loop and returns all recorded rounds.
+func (m *APIAvailabilityMonitor) Stop() []APIProbeResult {
+	close(m.stopCh)
+	<-m.doneCh
+	m.probesLock.Lock()
+	defer m.probesLock.Unlock()
+	probes := make([]APIProbeResult, len(m.probes))
+	copy(probes, m.probes)
+	return probes
+}
+
+// Gaps condenses probing rounds into runs of consecutive failures.
+func Gaps(probes []APIProbeResult) []AvailabilityGap {
+	var gaps []AvailabilityGap
+	var current *AvailabilityGap
+	for _, probe := range probes {
+		if probe.ok() {
+			current = nil
+			continue
+		}
+		if current == nil {
+			gaps = append(gaps, AvailabilityGap{Start: probe.Time})
+			current = &gaps[len(gaps)-1]
+		}
+		current.End = probe.Time
+		current.FailedProbes++
+		current.LastError = probe.Error
+	}
+	return gaps
+}
+
+// specMonitor is the monitor bracketing the currently running Serial or
+// Disruptive spec.
+var (
+	specMonitorLock sync.Mutex
+	specMonitor     *APIAvailabilityMonitor
+)
+
+func init() {
+	RegisterSpecHook(SpecHook{
+		Name:       "api-availability-monitor",
+		BeforeSpec: startSpecAvailabilityMonitor,
+		AfterSpec:  stopSpecAvailabilityMonitor,
+	})
+}
+
+// availabilityMonitorApplies reports whether the current spec should be
+// bracketed: the prober must be enabled and the spec Serial or Disruptive,
+// the lanes where apiserver overload is a realistic failure mode.
+func availabilityMonitorApplies() bool {
+	if TestContext.APIAvailabilityProbeInterval <= 0 {
+		return false
+	}
+	spec := ginkgo.CurrentSpecReport().FullText()
+	return strings.Contains(spec, "[Serial]") || strings.Contains(spec, "[Disruptive]")
+}
+
+func startSpecAvailabilityMonitor(f *Framework) {
+	if !availabilityMonitorApplies() || f.ClientSet == nil {
+		return
+	}
+	specMonitorLock.Lock()
+	defer specMonitorLock.Unlock()
+	if specMonitor == nil {
+		specMonitor = StartAPIAvailabilityMonitor(f.ClientSet, TestContext.APIAvailabilityProbeInterval)
+	}
+}
+
+func stopSpecAvailabilityMonitor(f *Framework, failed bool) {
+	specMonitorLock.Lock()
+	monitor := specMonitor
+	specMonitor = nil
+	specMonitorLock.Unlock()
+	if monitor == nil {
+		return
+	}
+	probes := monitor.Stop()
+	gaps := Gaps(probes)
+	if len(gaps) == 0 {
+		Logf("API availability monitor: %d probes, no gaps", len(probes))
+		return
+	}
+	timeline, err := json.MarshalIndent(struct {
+		Gaps   []AvailabilityGap
+		Probes []APIProbeResult
+	}{Gaps: gaps, Probes: probes}, "", "  ")
+	if err != nil {
+		Logf("Failed to encode the API availability timeline: %v", err)
+		return
+	}
+	ginkgo.AddReportEntry("api-availability", string(timeline))
+	Logf("WARNING: the apiserver had %d availability gap(s) during this spec; first gap started at %v", len(gaps), gaps[0].Start)
+	if TestContext.ReportDir != "" {
+		filePath, err := SpecArtifactPath(fmt.Sprintf("api_availability_%s.json", f.UniqueName))
+		if err != nil {
+			Logf("Failed to place the API availability timeline: %v", err)
+			return
+		}
+		if err := os.WriteFile(filePath, timeline, 0644); err != nil {
+			Logf("Failed to write the API availability timeline to %q: %v", filePath, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// NamespaceBulkOptions tunes CreateNamespaces.
+type NamespaceBulkOptions struct {
+	// Parallelism caps how many create calls run concurrently; zero or
+	// negative selects a default that stays well under the client-side rate
+	// limit, so a 100-namespace burst queues instead of erroring.
+	Parallelism int
+	// Labels are applied to every created namespace, in addition to the
+	// labels the framework always sets.
+	Labels map[string]string
+	// UseGenerateName delegates naming to the server via
+	// metadata.generateName, so workers cannot collide on names with each
+	// other or with a parallel suite sharing the cluster.
+	UseGenerateName bool
+}
+
+// defaultNamespaceCreationParallelism bounds bulk creation bursts. The
+// default client config allows 20 QPS, so more workers than this only pile
+// up in the rate limiter.
+const defaultNamespaceCreationParallelism = 16
+
+// namespaceCreationAttempts is how many times one worker retries a transient
+// creation failure before giving up on its namespace.
+const namespaceCreationAttempts = 3
+
+// CreateNamespaces creates count namespaces with the given base name prefix
+// using a bounded pool of workers, retrying transient failures (429 throttle
+// responses, name conflicts, server timeouts) with apiserver-suggested
+// backoff. Every created namespace is registered for deletion in AfterEach.
+// On success the returned names are in index order; on failure the error
+// aggregates every worker's last error rather than just the first.
+func (f *Framework) CreateNamespaces(ctx context.Context, count int, prefix string, opts NamespaceBulkOptions) ([]string, error) {
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultNamespaceCreationParallelism
+	}
+	names := make([]string, count)
+	var (
+		mu   sync.Mutex
+		errs []string
+	)
+	runErr := RunParallel(count, parallelism, func(i int) error {
+		var lastErr error
+		for attempt := 0; attempt < namespaceCreationAttempts; attempt++ {
+			var ns *v1.Namespace
+			ns, lastErr = f.createNamespace(ctx, fmt.Sprintf("%s-%v", prefix, i), opts.Labels, opts.UseGenerateName)
+			if lastErr == nil {
+				names[i] = ns.Name
+				return nil
+			}
+			if !transientNamespaceCreationError(lastErr) {
+				break
+			}
+			delay := Poll
+			if seconds, suggested := apierrors.SuggestsClientDelay(lastErr); suggested {
+				delay = time.Duration(seconds) * time.Second
+			}
+			RecordRetry("transient", "bulk creation of namespace %s-%v: %v", prefix, i, lastErr)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		mu.Lock()
+		errs = append(errs, fmt.Sprintf("namespace %s-%v: %v", prefix, i, lastErr))
+		mu.Unlock()
+		return lastErr
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to create %d of %d namespaces:\n%s", len(errs), count, strings.Join(errs, "\n"))
+	}
+	if runErr != nil {
+		// No per-worker errors were recorded, so this can only be a panic
+		// surfaced by RunParallel.
+		return nil, runErr
+	}
+	return names, nil
+}
+
+// transientNamespaceCreationError reports whether a failed namespace create
+// is worth retrying from the caller's side. Name conflicts are included
+// because the creation path regenerates the name on its next attempt.
+func transientNamespaceCreationError(err error) bool {
+	return apierrors.IsTooManyRequests(err) ||
+		apierrors.IsConflict(err) ||
+		apierrors.IsAlreadyExists(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err)
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// The namespace canary runs next to a serial suite and creates and deletes a
+// tiny namespace at a fixed interval, recording how long each step took.
+// When a lifecycle soak later misses its deadline, the canary timeline
+// answers the first triage question: was the cluster already degraded before
+// the soak started, or did the soak degrade it?
+
+// CanarySample is one create/delete round-trip of the canary namespace.
+type CanarySample struct {
+	// Start is when the round-trip began.
+	Start time.Time
+	// CreateLatency is the time from the create call until the namespace was
+	// initialized and usable.
+	CreateLatency time.Duration
+	// DeleteLatency is the time from the delete call until the namespace was
+	// fully gone.
+	DeleteLatency time.Duration
+	// Error is non-empty when the round-trip failed; the latencies of the
+	// failed step are zero.
+	Error string `json:",omitempty"`
+}
+
+// NamespaceCanary periodically measures namespace lifecycle latency in the
+// background. Create one with StartNamespaceCanary.
+type NamespaceCanary struct {
+	client   clientset.Interface
+	interval time.Duration
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	samplesLock sync.Mutex
+	samples     []CanarySample
+}
+
+// StartNamespaceCanary begins probing namespace lifecycle latency every
+// interval until Stop is called. The canary namespaces are labelled
+// "e2e-canary=<RunID>" so leak detection can attribute them.
+func StartNamespaceCanary(c clientset.Interface, interval time.Duration) *NamespaceCanary {
+	canary := &NamespaceCanary{
+		client:   c,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	go canary.run()
+	return canary
+}
+
+func (nc *NamespaceCanary) run() {
+	defer close(nc.doneCh)
+	ticker := time.NewTicker(nc.interval)
+	defer ticker.Stop()
+	for i := 0; ; i++ {
+		nc.probe(i)
+		select {
+		case <-nc.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probe performs one create/wait/delete/wait round-trip and records it.
+func (nc *NamespaceCanary) probe(seq int) {
+	sample := CanarySample{Start: time.Now()}
+	name := fmt.Sprintf("e2e-canary-%v-%d", RunID, seq)
+	_, err := nc.client.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"e2e-canary": string(RunID)},
+		},
+	}, metav1.CreateOptions{})
+	if err == nil {
+		err = WaitForNamespaceInitialized(context.TODO(), nc.client, name)
+	}
+	if err != nil {
+		sample.Error = fmt.Sprintf("creating canary namespace %s: %v", name, err)
+		nc.record(sample)
+		return
+	}
+	sample.CreateLatency = time.Since(sample.Start)
+
+	deleteStart := time.Now()
+	err = nc.client.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err == nil {
+		err = WaitForNamespacesDeleted(nc.client, []string{name}, nc.interval)
+	}
+	if err != nil {
+		sample.Error = fmt.Sprintf("deleting canary namespace %s: %v", name, err)
+		nc.record(sample)
+		return
+	}
+	sample.DeleteLatency = time.Since(deleteStart)
+	nc.record(sample)
+}
+
+func (nc *NamespaceCanary) record(sample CanarySample) {
+	nc.samplesLock.Lock()
+	defer nc.samplesLock.Unlock()
+	nc.samples = append(nc.samples, sample)
+	if sample.Error != "" {
+		Logf("Namespace canary round-trip failed: %s", sample.Error)
+	}
+}
+
+// Samples returns a copy of the rounds recorded so far.
+func (nc *NamespaceCanary) Samples() []CanarySample {
+	nc.samplesLock.Lock()
+	defer nc.samplesLock.Unlock()
+	samples := make([]CanarySample, len(nc.samples))
+	copy(samples, nc.samples)
+	return samples
+}
+
+// Stop ends the canary loop, waits for an in-flight round-trip to finish and
+// returns all recorded samples.
+func (nc *NamespaceCanary) Stop() []CanarySample {
+	close(nc.stopCh)
+	<-nc.doneCh
+	return nc.Samples()
+}
+
+// WriteArtifact stores the canary timeline as a JSON artifact in the report
+// directory, or logs it when no report directory is configured.
+func (nc *NamespaceCanary) WriteArtifact(tag string) {
+	data, err := json.MarshalIndent(nc.Samples(), "", "  ")
+	if err != nil {
+		Logf("Failed to encode the namespace canary timeline: %v", err)
+		return
+	}
+	if TestContext.ReportDir == "" {
+		Logf("Namespace canary timeline for %q:\n%s", tag, string(data))
+		return
+	}
+	filePath, err := SpecArtifactPath(fmt.Sprintf("namespace_canary_%s.json", tag))
+	if err != nil {
+		Logf("Failed to place the namespace canary timeline: %v", err)
+		return
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		Logf("Failed to write the namespace canary timeline to %q: %v", filePath, err)
+	}
+}
+
+// NamespaceHeartbeatSLO bounds the two steps of a heartbeat round-trip. Zero
+// fields select defaults loose enough for any healthy cluster.
+type NamespaceHeartbeatSLO struct {
+	// CreateLatency bounds the time from the create call until the namespace
+	// is initialized and usable. Default 30 seconds.
+	CreateLatency time.Duration
+	// DeleteLatency bounds the time from the delete call until the namespace
+	// is fully gone. Default 2 minutes.
+	DeleteLatency time.Duration
+}
+
+// MeasureNamespaceHeartbeat performs one create/wait/delete/wait round-trip
+// with a throwaway namespace and returns its latencies. Unlike the canary it
+// runs in the caller's goroutine and exactly once.
+func MeasureNamespaceHeartbeat(c clientset.Interface) (CanarySample, error) {
+	sample := CanarySample{Start: time.Now()}
+	name := fmt.Sprintf("e2e-heartbeat-%v-%s", RunID, RandomSuffix())
+	_, err := c.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"e2e-heartbeat": string(RunID)},
+		},
+	}, metav1.CreateOptions{})
+	if err == nil {
+		err = WaitForNamespaceInitialized(context.TODO(), c, name)
+	}
+	if err != nil {
+		sample.Error = fmt.Sprintf("creating heartbeat namespace %s: %v", name, err)
+		return sample, errors.New(sample.Error)
+	}
+	sample.CreateLatency = time.Since(sample.Start)
+
+	deleteStart := time.Now()
+	err = c.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err == nil {
+		err = WaitForNamespacesDeleted(c, []string{name}, ScaleTimeout(DefaultNamespaceDeletionTimeout))
+	}
+	if err != nil {
+		sample.Error = fmt.Sprintf("deleting heartbeat namespace %s: %v", name, err)
+		return sample, errors.New(sample.Error)
+	}
+	sample.DeleteLatency = time.Since(deleteStart)
+	return sample, nil
+}
+
+// AssertNamespaceLifecycleHealthy runs one heartbeat round-trip and holds it
+// against the SLO. It exists so every [Serial] suite can assert namespace
+// controller health in one line, instead of regressions only surfacing in the
+// dedicated namespace lane; a good place to call it is a suite's own
+// AfterEach or a disruption test's recovery check.
+func AssertNamespaceLifecycleHealthy(c clientset.Interface, slo NamespaceHeartbeatSLO) {
+	if slo.CreateLatency <= 0 {
+		slo.CreateLatency = 30 * time.Second
+	}
+	if slo.DeleteLatency <= 0 {
+		slo.DeleteLatency = 2 * time.Minute
+	}
+	sample, err := MeasureNamespaceHeartbeat(c)
+	if err != nil {
+		Failf("namespace lifecycle heartbeat failed: %v", err)
+	}
+	Logf("Namespace lifecycle heartbeat: create %v, delete %v", sample.CreateLatency, sample.DeleteLatency)
+	EnforceSLO(SLOResult{
+		Name:      "namespace-heartbeat-create",
+		Measured:  sample.CreateLatency.Seconds(),
+		Objective: ScaleTimeout(slo.CreateLatency).Seconds(),
+		Unit:      "s",
+		Description: fmt.Sprintf("heartbeat namespace took %v to become usable (SLO: %v)",
+			sample.CreateLatency, ScaleTimeout(slo.CreateLatency)),
+	})
+	EnforceSLO(SLOResult{
+		Name:      "namespace-heartbeat-delete",
+		Measured:  sample.DeleteLatency.Seconds(),
+		Objective: ScaleTimeout(slo.DeleteLatency).Seconds(),
+		Unit:      "s",
+		Description: fmt.Sprintf("heartbeat namespace took %v to finish deleting (SLO: %v)",
+			sample.DeleteLatency, ScaleTimeout(slo.DeleteLatency)),
+	})
+}
+
+// suiteCanary is the canary started by the suite when
+// --namespace-canary-interval is set; see StartSuiteNamespaceCanary.
+var suiteCanary *NamespaceCanary
+
+// StartSuiteNamespaceCanary starts the background canary for the whole suite
+// when one was requested via --namespace-canary-interval. It is expected to
+// be called once, from the suite setup on the first Ginkgo node.
+func StartSuiteNamespaceCanary(c clientset.Interface) {
+	if TestContext.NamespaceCanaryInterval <= 0 || suiteCanary != nil {
+		return
+	}
+	Logf("Starting the namespace canary with a %v interval", TestContext.NamespaceCanaryInterval)
+	suiteCanary = StartNamespaceCanary(c, TestContext.NamespaceCanaryInterval)
+}
+
+// StopSuiteNamespaceCanary stops the suite canary, if one is running, and
+// writes its timeline artifact.
+func StopSuiteNamespaceCanary() {
+	if suiteCanary == nil {
+		return
+	}
+	samples := suiteCanary.Stop()
+	suiteCanary.WriteArtifact("suite")
+	suiteCanary = nil
+	Logf("Namespace canary stopped after %d round-trips", len(samples))
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// The suite checkpoint records every namespace and cluster-scoped object the
+// framework created, so that a run killed mid-flight leaves behind a manifest
+// of its leftovers instead of a mystery. The file lives in the report dir and
+// is rewritten atomically on every change; --cleanup-from-checkpoint consumes
+// it at the start of the next run.
+
+// checkpointFileName is the well-known name of the checkpoint inside the
+// report dir. It is deliberately not derived from RunID: the next run must be
+// able to find the previous run's manifest.
+const checkpointFileName = "suite_checkpoint.json"
+
+// CheckpointObject identifies one cluster-scoped object created by the suite.
+type CheckpointObject struct {
+	Group    string `json:"group,omitempty"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+}
+
+// checkpointState is the on-disk layout of the checkpoint file.
+type checkpointState struct {
+	Namespaces    []string           `json:"namespaces,omitempty"`
+	ClusterScoped []CheckpointObject `json:"clusterScoped,omitempty"`
+}
+
+var checkpointLock sync.Mutex
+
+// checkpointPath returns the checkpoint location, or "" when checkpointing is
+// disabled because no report dir is configured.
+func checkpointPath() string {
+	if TestContext.ReportDir == "" {
+		return ""
+	}
+	return filepath.Join(TestContext.ReportDir, checkpointFileName)
+}
+
+// CheckpointNamespaceCreated records a namespace in the suite checkpoint.
+// Leased namespaces are not recorded: the suite does not own them and a later
+// cleanup run must not delete them.
+func CheckpointNamespaceCreated(name string) {
+	if UsingExistingNamespaces() {
+		return
+	}
+	mutateCheckpoint(func(state *checkpointState) {
+		for _, existing := range state.Namespaces {
+			if existing == name {
+				return
+			}
+		}
+		state.Namespaces = append(state.Namespaces, name)
+	})
+}
+
+// CheckpointNamespaceDeleted removes a namespace from the suite checkpoint
+// once its deletion has been issued.
+func CheckpointNamespaceDeleted(name string) {
+	mutateCheckpoint(func(state *checkpointState) {
+		for i, existing := range state.Namespaces {
+			if existing == name {
+				state.Namespaces = append(state.Namespaces[:i], state.Namespaces[i+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// CheckpointClusterScopedCreated records a cluster-scoped object in the suite
+// checkpoint. Fixtures that create cluster-scoped objects outside a namespace
+// should call this next to the create and the matching Deleted next to the
+// delete.
+func CheckpointClusterScopedCreated(gvr schema.GroupVersionResource, name string) {
+	mutateCheckpoint(func(state *checkpointState) {
+		obj := CheckpointObject{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource, Name: name}
+		for _, existing := range state.ClusterScoped {
+			if existing == obj {
+				return
+			}
+		}
+		state.ClusterScoped = append(state.ClusterScoped, obj)
+	})
+}
+
+// CheckpointClusterScopedDeleted removes a cluster-scoped object from the
+// suite checkpoint.
+func CheckpointClusterScopedDeleted(gvr schema.GroupVersionResource, name string) {
+	mutateCheckpoint(func(state *checkpointState) {
+		obj := CheckpointObject{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource, Name: name}
+		for i, existing := range state.ClusterScoped {
+			if existing == obj {
+				state.ClusterScoped = append(state.ClusterScoped[:i], state.ClusterScoped[i+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// mutateCheckpoint applies fn to the current checkpoint state and writes the
+// result back atomically (temp file plus rename), so a kill at any point
+// leaves either the old or the new manifest, never a torn one. Checkpoint
+// failures are logged and swallowed; the manifest is a safety net and must
+// never fail a test.
+func mutateCheckpoint(fn func(*checkpointState)) {
+	path := checkpointPath()
+	if path == "" {
+		return
+	}
+	checkpointLock.Lock()
+	defer checkpointLock.Unlock()
+	state, err := readCheckpoint(path)
+	if err != nil {
+		Logf("WARNING: failed to read the suite checkpoint: %v", err)
+		return
+	}
+	fn(state)
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		Logf("WARNING: failed to encode the suite checkpoint: %v", err)
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		Logf("WARNING: failed to write the suite checkpoint: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		Logf("WARNING: failed to update the suite checkpoint: %v", err)
+	}
+}
+
+// readCheckpoint loads the checkpoint at path; a missing file is an empty
+// checkpoint.
+func readCheckpoint(path string) (*checkpointState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &checkpointState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := &checkpointState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	return state, nil
+}
+
+// CleanupFromCheckpoint deletes everything a previous, interrupted run left
+// behind according to its checkpoint, then removes the checkpoint itself.
+// Objects that are already gone are fine; anything else that cannot be
+// deleted is reported so the run stops before the leftovers can poison it.
+func CleanupFromCheckpoint(c clientset.Interface, dc dynamic.Interface) error {
+	path := checkpointPath()
+	if path == "" {
+		return fmt.Errorf("--cleanup-from-checkpoint requires --report-dir")
+	}
+	checkpointLock.Lock()
+	state, err := readCheckpoint(path)
+	checkpointLock.Unlock()
+	if err != nil {
+		return err
+	}
+	if len(state.Namespaces) == 0 && len(state.ClusterScoped) == 0 {
+		Logf("No leftovers recorded in the suite checkpoint")
+		return os.RemoveAll(path)
+	}
+	Logf("Cleaning up %d namespaces and %d cluster-scoped objects left by an interrupted run",
+		len(state.Namespaces), len(state.ClusterScoped))
+
+	var deleted []string
+	for _, name := range state.Namespaces {
+		err := c.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+		switch {
+		case err == nil:
+			deleted = append(deleted, name)
+		case apierrors.IsNotFound(err):
+			// Already cleaned up, possibly by the interrupted run itself.
+		default:
+			return fmt.Errorf("failed to delete leftover namespace %s: %v", name, err)
+		}
+	}
+	if len(deleted) > 0 {
+		if err := WaitForNamespacesDeleted(c, deleted, DefaultNamespaceDeletionTimeout); err != nil {
+			LogStuckNamespaceAnalysis(c, dc, deleted...)
+			return fmt.Errorf("leftover namespaces did not finish terminating: %v", err)
+		}
+	}
+
+	for _, obj := range state.ClusterScoped {
+		gvr := schema.GroupVersionResource{Group: obj.Group, Version: obj.Version, Resource: obj.Resource}
+		err := dc.Resource(gvr).Delete(context.TODO(), obj.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete leftover %s %s: %v", gvr.Resource, obj.Name, err)
+		}
+	}
+
+	return os.RemoveAll(path)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// GVRDeletionRecord describes when the namespace controller finished emptying
+// one resource while a namespace was being deleted, as observed from the
+// client side. ClearedSeconds counts from the start of the trace; a zero
+// ClearedAt means the resource still had content when tracing stopped.
+type GVRDeletionRecord struct {
+	GVR            string    `json:"gvr"`
+	MaxObjects     int       `json:"maxObjects"`
+	FirstObserved  time.Time `json:"firstObserved"`
+	ClearedAt      time.Time `json:"clearedAt,omitempty"`
+	ClearedSeconds float64   `json:"clearedSeconds,omitempty"`
+}
+
+// DeletionOrderTracer periodically inventories a namespace while it is being
+// deleted and reconstructs the order in which each group/version/resource was
+// emptied. The namespace controller does not expose its per-GVR progress, so
+// this is a best-effort client-side reconstruction whose resolution is bounded
+// by the polling interval; it is still enough to flag pathological orderings
+// such as pods being drained last.
+type DeletionOrderTracer struct {
+	c         clientset.Interface
+	dc        dynamic.Interface
+	namespace string
+	started   time.Time
+
+	mu            sync.Mutex
+	firstObserved map[string]time.Time
+	maxObjects    map[string]int
+	clearedAt     map[string]time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// deletionOrderTraceInterval bounds how precisely the tracer can place the
+// point at which a resource was emptied.
+const deletionOrderTraceInterval = 2 * time.Second
+
+// StartDeletionOrderTracer begins tracing the given namespace. Call it just
+// before issuing the namespace delete and Stop once the namespace is gone.
+func StartDeletionOrderTracer(c clientset.Interface, dc dynamic.Interface, namespace string) *DeletionOrderTracer {
+	tracer := &DeletionOrderTracer{
+		c:             c,
+		dc:            dc,
+		namespace:     namespace,
+		started:       time.Now(),
+		firstObserved: map[string]time.Time{},
+		maxObjects:    map[string]int{},
+		clearedAt:     map[string]time.Time{},
+		stopCh:        make(chan struct{}),
+	}
+	tracer.wg.Add(1)
+	go func() {
+		defer tracer.wg.Done()
+		ticker := time.NewTicker(deletionOrderTraceInterval)
+		defer ticker.Stop()
+		for {
+			tracer.observe()
+			select {
+			case <-tracer.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return tracer
+}
+
+func (tracer *DeletionOrderTracer) observe() {
+	inventory, err := InventoryNamespaceContent(tracer.c, tracer.dc, tracer.namespace)
+	if err != nil {
+		Logf("Deletion order trace of namespace %s skipped an observation: %v", tracer.namespace, err)
+		return
+	}
+	now := time.Now()
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	for gvr, objects := range inventory {
+		if _, seen := tracer.firstObserved[gvr]; !seen {
+			tracer.firstObserved[gvr] = now
+		}
+		if len(objects) > tracer.maxObjects[gvr] {
+			tracer.maxObjects[gvr] = len(objects)
+		}
+	}
+	for gvr := range tracer.firstObserved {
+		if _, cleared := tracer.clearedAt[gvr]; cleared {
+			continue
+		}
+		if _, present := inventory[gvr]; !present {
+			tracer.clearedAt[gvr] = now
+		}
+	}
+}
+
+// Stop ends the trace. It is safe to call more than once.
+func (tracer *DeletionOrderTracer) Stop() {
+	tracer.stopOnce.Do(func() {
+		close(tracer.stopCh)
+	})
+	tracer.wg.Wait()
+}
+
+// Records returns one record per observed resource, ordered by when the
+// resource was emptied; resources that never cleared sort last.
+func (tracer *DeletionOrderTracer) Records() []GVRDeletionRecord {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	records := make([]GVRDeletionRecord, 0, len(tracer.firstObserved))
+	for gvr, first := range tracer.firstObserved {
+		record := GVRDeletionRecord{
+			GVR:           gvr,
+			MaxObjects:    tracer.maxObjects[gvr],
+			FirstObserved: first,
+		}
+		if cleared, ok := tracer.clearedAt[gvr]; ok {
+			record.ClearedAt = cleared
+			record.ClearedSeconds = cleared.Sub(tracer.started).Seconds()
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].ClearedAt.IsZero() != records[j].ClearedAt.IsZero() {
+			return records[j].ClearedAt.IsZero()
+		}
+		if !records[i].ClearedAt.Equal(records[j].ClearedAt) {
+			return records[i].ClearedAt.Before(records[j].ClearedAt)
+		}
+		return records[i].GVR < records[j].GVR
+	})
+	return records
+}
+
+// WriteArtifact stores the reconstructed ordering as a JSON artifact named
+// after the tag in the report directory, or logs it when no report directory
+// is configured. Traces that observed no content produce no output.
+func (tracer *DeletionOrderTracer) WriteArtifact(tag string) {
+	records := tracer.Records()
+	if len(records) == 0 {
+		return
+	}
+	if last := records[len(records)-1]; last.GVR == "/v1, Resource=pods" && len(records) > 1 && !last.ClearedAt.IsZero() {
+		Logf("Deletion order trace of namespace %s: pods were the last resource to be emptied", tracer.namespace)
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		Logf("Failed to encode deletion order trace of namespace %s: %v", tracer.namespace, err)
+		return
+	}
+	if TestContext.ReportDir == "" {
+		Logf("Deletion order trace for %q:\n%s", tag, string(data))
+		return
+	}
+	filePath, err := SpecArtifactPath(fmt.Sprintf("deletion_order_%s.json", tag))
+	if err != nil {
+		Logf("Failed to place deletion order trace: %v", err)
+		return
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		Logf("Failed to write deletion order trace to %q: %v", filePath, err)
+	}
+}
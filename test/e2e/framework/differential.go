@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// Differential conformance runs the same API probe against two clusters in
+// lockstep and diffs what came back. Platform teams qualifying an upgrade
+// point --secondary-kubeconfig at the candidate control plane and get a
+// behavioral diff — different error reasons, different outcomes, latency
+// ratios — instead of eyeballing two separate suite runs.
+
+// DifferentialObservation is what one cluster answered to one probe.
+type DifferentialObservation struct {
+	// Outcome is the normalized result: "success" or the API error reason.
+	Outcome string
+	// Detail carries probe-specific findings (a condition, a message) that
+	// should match across clusters; empty details are never diffed.
+	Detail string `json:",omitempty"`
+	// Latency is how long the probe took against this cluster.
+	Latency time.Duration
+}
+
+// DifferentialProbe is one behavior to compare across the two clusters. Run
+// must leave the cluster the way it found it; it is invoked once per cluster.
+type DifferentialProbe struct {
+	Name string
+	Run  func(c clientset.Interface) DifferentialObservation
+}
+
+// DifferentialEntry is the per-probe comparison in the report.
+type DifferentialEntry struct {
+	Probe     string
+	Primary   DifferentialObservation
+	Secondary DifferentialObservation
+	// Divergent is set when the two clusters answered differently in outcome
+	// or detail; latency differences alone are reported, not flagged.
+	Divergent bool
+	// LatencyRatio is secondary latency over primary latency.
+	LatencyRatio float64 `json:",omitempty"`
+}
+
+// DifferentialReport aggregates the probe comparisons of one differential run.
+type DifferentialReport struct {
+	Entries []DifferentialEntry
+}
+
+// OutcomeForError normalizes an error into a probe outcome so that both
+// clusters' answers compare structurally: nil maps to "success" and API
+// errors to their metav1 status reason. Probes should prefer it over raw
+// error strings, which embed cluster-specific names and hosts.
+func OutcomeForError(err error) string {
+	if err == nil {
+		return "success"
+	}
+	if reason := apierrors.ReasonForError(err); reason != "" {
+		return string(reason)
+	}
+	return fmt.Sprintf("unrecognized error: %v", err)
+}
+
+// RunDifferentialProbes runs every probe against the primary and then the
+// secondary cluster and returns the comparison. Probes run in lockstep — both
+// clusters answer probe N before probe N+1 starts — so time-dependent
+// behavior is sampled under comparable conditions.
+func RunDifferentialProbes(primary, secondary clientset.Interface, probes []DifferentialProbe) DifferentialReport {
+	report := DifferentialReport{}
+	for _, probe := range probes {
+		primaryStart := time.Now()
+		primaryObservation := probe.Run(primary)
+		primaryObservation.Latency = time.Since(primaryStart)
+
+		secondaryStart := time.Now()
+		secondaryObservation := probe.Run(secondary)
+		secondaryObservation.Latency = time.Since(secondaryStart)
+
+		entry := DifferentialEntry{
+			Probe:     probe.Name,
+			Primary:   primaryObservation,
+			Secondary: secondaryObservation,
+			Divergent: primaryObservation.Outcome != secondaryObservation.Outcome ||
+				primaryObservation.Detail != secondaryObservation.Detail,
+		}
+		if primaryObservation.Latency > 0 {
+			entry.LatencyRatio = secondaryObservation.Latency.Seconds() / primaryObservation.Latency.Seconds()
+		}
+		Logf("Differential probe %q: primary %s in %v, secondary %s in %v",
+			probe.Name, primaryObservation.Outcome, primaryObservation.Latency,
+			secondaryObservation.Outcome, secondaryObservation.Latency)
+		report.Entries = append(report.Entries, entry)
+	}
+	return report
+}
+
+// Divergences returns a human-readable line per probe where the clusters
+// disagreed.
+func (r DifferentialReport) Divergences() []string {
+	var divergences []string
+	for _, entry := range r.Entries {
+		if !entry.Divergent {
+			continue
+		}
+		divergences = append(divergences, fmt.Sprintf("probe %q: primary answered %q (%s), secondary answered %q (%s)",
+			entry.Probe, entry.Primary.Outcome, entry.Primary.Detail, entry.Secondary.Outcome, entry.Secondary.Detail))
+	}
+	return divergences
+}
+
+// WriteArtifact stores the diff report as a JSON artifact in the report
+// directory, or logs it when no report directory is configured.
+func (r DifferentialReport) WriteArtifact(tag string) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		Logf("Failed to encode the differential report: %v", err)
+		return
+	}
+	if TestContext.ReportDir == "" {
+		Logf("Differential report for %q:\n%s", tag, string(data))
+		return
+	}
+	filePath, err := SpecArtifactPath(fmt.Sprintf("differential_%s.json", tag))
+	if err != nil {
+		Logf("Failed to place the differential report: %v", err)
+		return
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		Logf("Failed to write the differential report to %q: %v", filePath, err)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package disruption restarts or partitions control plane components while a
+// test is running, to verify that controllers resume their work correctly.
+// The restart path is provider-aware: on the ssh providers (gce, aws,
+// kubeadm) the component process is killed and its supervisor brings it back;
+// on kind the process is killed inside the control-plane container. Every
+// caller must be tagged [Disruptive].
+package disruption
+
+import (
+	"context"
+	"fmt"
+	"net"
+	osexec "os/exec"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/test/e2e/framework"
+	e2essh "k8s.io/kubernetes/test/e2e/framework/ssh"
+)
+
+const (
+	// APIServer is the process name of the kube-apiserver.
+	APIServer = "kube-apiserver"
+	// ControllerManager is the process name of the kube-controller-manager.
+	ControllerManager = "kube-controller-manager"
+)
+
+// Supported reports whether the current provider has a restart path. Specs
+// should skip when it returns false rather than fail inside the helpers.
+func Supported() bool {
+	switch framework.TestContext.Provider {
+	case "gce":
+		return framework.MasterOSDistroIs("gci")
+	case "aws", "kubeadm":
+		return true
+	case "kind":
+		_, err := osexec.LookPath("docker")
+		return err == nil
+	}
+	return false
+}
+
+// RestartAPIServer kills the kube-apiserver on the control plane and relies
+// on its supervisor to bring it back.
+func RestartAPIServer() error {
+	return restartComponent(APIServer)
+}
+
+// RestartControllerManager kills the kube-controller-manager on the control
+// plane and relies on its supervisor to bring it back.
+func RestartControllerManager() error {
+	return restartComponent(ControllerManager)
+}
+
+func restartComponent(component string) error {
+	switch framework.TestContext.Provider {
+	case "gce", "aws", "kubeadm":
+		cmd := fmt.Sprintf("pidof %s | xargs sudo kill", component)
+		framework.Logf("Restarting %s via ssh, running: %v", component, cmd)
+		result, err := e2essh.SSH(cmd, net.JoinHostPort(framework.APIAddress(), e2essh.SSHPort), framework.TestContext.Provider)
+		if err != nil || result.Code != 0 {
+			e2essh.LogResult(result)
+			return fmt.Errorf("couldn't restart %s: %v", component, err)
+		}
+		return nil
+	case "kind":
+		node, err := kindControlPlaneContainer()
+		if err != nil {
+			return err
+		}
+		framework.Logf("Restarting %s inside kind node %s", component, node)
+		if out, err := osexec.Command("docker", "exec", node, "pkill", "-f", component).CombinedOutput(); err != nil {
+			return fmt.Errorf("couldn't restart %s in kind node %s: %v (output: %s)", component, node, err, out)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported provider for restarting %s: %s", component, framework.TestContext.Provider)
+}
+
+func kindControlPlaneContainer() (string, error) {
+	out, err := osexec.Command("docker", "ps", "--format", "{{.Names}}", "--filter", "name=control-plane").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list kind control-plane containers: %v", err)
+	}
+	names := strings.Fields(string(out))
+	if len(names) == 0 {
+		return "", fmt.Errorf("no kind control-plane container found")
+	}
+	return names[0], nil
+}
+
+// PartitionAPIServerFromHost blocks traffic from the given host to the
+// apiserver for the duration, then removes the rule. Only the ssh providers
+// support this.
+func PartitionAPIServerFromHost(host string, duration time.Duration) error {
+	if !framework.ProviderIs("gce", "aws", "kubeadm") {
+		return fmt.Errorf("unsupported provider for partitioning: %s", framework.TestContext.Provider)
+	}
+	rule := fmt.Sprintf("OUTPUT --destination %s --jump REJECT", framework.APIAddress())
+	cmd := fmt.Sprintf("sudo iptables --insert %s && sleep %d && sudo iptables --delete %s",
+		rule, int(duration.Seconds()), rule)
+	framework.Logf("Partitioning %s from the apiserver for %v", host, duration)
+	result, err := e2essh.SSH(cmd, net.JoinHostPort(host, e2essh.SSHPort), framework.TestContext.Provider)
+	if err != nil || result.Code != 0 {
+		e2essh.LogResult(result)
+		return fmt.Errorf("couldn't partition %s from the apiserver: %v", host, err)
+	}
+	return nil
+}
+
+// WaitForAPIServerReady polls the apiserver's /readyz through the client
+// until it answers or the timeout expires.
+func WaitForAPIServerReady(c clientset.Interface, timeout time.Duration) error {
+	return wait.PollImmediate(framework.Poll, timeout, func() (bool, error) {
+		_, err := c.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(context.TODO())
+		if err != nil {
+			framework.Logf("apiserver not ready yet: %v", err)
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+// WaitForControllerManagerHealthy waits until the controller-manager's leader
+// election lease has been renewed after the given time, which proves a
+// controller-manager is running and holds the lock. Lease-based health works
+// on every provider, unlike scraping localhost health endpoints.
+func WaitForControllerManagerHealthy(c clientset.Interface, since time.Time, timeout time.Duration) error {
+	return wait.PollImmediate(5*time.Second, timeout, func() (bool, error) {
+		lease, err := c.CoordinationV1().Leases(metav1.NamespaceSystem).Get(context.TODO(), ControllerManager, metav1.GetOptions{})
+		if err != nil {
+			framework.Logf("failed to get the controller-manager lease: %v", err)
+			return false, nil
+		}
+		if lease.Spec.RenewTime != nil && lease.Spec.RenewTime.After(since) {
+			return true, nil
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// ObjectDumpEncoder renders objects for inclusion in dump/diagnostic
+// artifacts such as the Terminating namespace dump. Additional encodings
+// (for example CBOR, once the cluster and client libraries support it) can be
+// plugged in via RegisterObjectDumpEncoder without changing the dump
+// subsystem.
+type ObjectDumpEncoder interface {
+	// Name is the encoding name used to select the encoder, e.g. "yaml".
+	Name() string
+	// FileExtension is appended to artifact file names, e.g. "yaml".
+	FileExtension() string
+	// Encode renders the object.
+	Encode(obj runtime.Object) ([]byte, error)
+}
+
+var (
+	dumpEncodersLock sync.RWMutex
+	dumpEncoders     = map[string]ObjectDumpEncoder{}
+)
+
+// RegisterObjectDumpEncoder makes an encoder available for object dumps.
+// Registering the same name twice is a coding error and panics.
+func RegisterObjectDumpEncoder(encoder ObjectDumpEncoder) {
+	dumpEncodersLock.Lock()
+	defer dumpEncodersLock.Unlock()
+	if _, ok := dumpEncoders[encoder.Name()]; ok {
+		panic(fmt.Sprintf("object dump encoder %q is already registered", encoder.Name()))
+	}
+	dumpEncoders[encoder.Name()] = encoder
+}
+
+// GetObjectDumpEncoder returns the encoder registered under the given name,
+// falling back to YAML when the name is empty or unknown.
+func GetObjectDumpEncoder(name string) ObjectDumpEncoder {
+	dumpEncodersLock.RLock()
+	defer dumpEncodersLock.RUnlock()
+	if encoder, ok := dumpEncoders[name]; ok {
+		return encoder
+	}
+	return dumpEncoders["yaml"]
+}
+
+type yamlDumpEncoder struct{}
+
+func (yamlDumpEncoder) Name() string          { return "yaml" }
+func (yamlDumpEncoder) FileExtension() string { return "yaml" }
+func (yamlDumpEncoder) Encode(obj runtime.Object) ([]byte, error) {
+	return yaml.Marshal(obj)
+}
+
+type jsonDumpEncoder struct{}
+
+func (jsonDumpEncoder) Name() string          { return "json" }
+func (jsonDumpEncoder) FileExtension() string { return "json" }
+func (jsonDumpEncoder) Encode(obj runtime.Object) ([]byte, error) {
+	return json.MarshalIndent(obj, "", "  ")
+}
+
+func init() {
+	RegisterObjectDumpEncoder(yamlDumpEncoder{})
+	RegisterObjectDumpEncoder(jsonDumpEncoder{})
+}
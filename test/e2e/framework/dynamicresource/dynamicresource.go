@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dynamicresource drives a parameterized CRUD lifecycle against an
+// arbitrary group/version/resource through the dynamic client, so
+// conformance-style specs do not have to repeat the same create, patch, list
+// and delete boilerplate per typed client.
+package dynamicresource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kubernetes/test/e2e/framework"
+)
+
+// Options tunes which lifecycle steps RunLifecycle performs for resources
+// that do not support the full set.
+type Options struct {
+	// SkipDeleteCollection deletes the object by name instead of through
+	// deleteCollection, for resources that do not serve it (e.g. namespaces).
+	SkipDeleteCollection bool
+	// SkipStrategicMergePatch skips the strategic merge patch step, which
+	// custom resources do not support.
+	SkipStrategicMergePatch bool
+	// DeletionTimeout bounds the wait for the object to disappear after the
+	// delete step; zero means 2 minutes.
+	DeletionTimeout time.Duration
+	// PostCreate, when set, is invoked with the created object before any
+	// further steps, e.g. to register cleanup for it.
+	PostCreate func(obj *unstructured.Unstructured)
+}
+
+// Harness runs lifecycles against one resource. The zero value is not
+// usable; construct it with NewHarness.
+type Harness struct {
+	client    dynamic.Interface
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+// NewHarness returns a harness for the given resource. Pass an empty
+// namespace for cluster-scoped resources.
+func NewHarness(client dynamic.Interface, gvr schema.GroupVersionResource, namespace string) *Harness {
+	return &Harness{client: client, gvr: gvr, namespace: namespace}
+}
+
+func (h *Harness) resource() dynamic.ResourceInterface {
+	if h.namespace == "" {
+		return h.client.Resource(h.gvr)
+	}
+	return h.client.Resource(h.gvr).Namespace(h.namespace)
+}
+
+// RunLifecycle creates the given object and drives it through get, the three
+// patch types, a selector-scoped list and deletion, asserting every step.
+// The object should use generateName (or a unique name) so repeated runs do
+// not collide.
+func (h *Harness) RunLifecycle(obj *unstructured.Unstructured, opts Options) {
+	lifecycleValue := framework.RandomSuffix()
+	created, err := h.resource().Create(context.TODO(), obj, metav1.CreateOptions{})
+	framework.ExpectNoError(err, "failed to create %s", h.gvr.Resource)
+	name := created.GetName()
+	if opts.PostCreate != nil {
+		opts.PostCreate(created)
+	}
+
+	got, err := h.resource().Get(context.TODO(), name, metav1.GetOptions{})
+	framework.ExpectNoError(err, "failed to get %s %q", h.gvr.Resource, name)
+	framework.ExpectEqual(got.GetUID(), created.GetUID(), "get returned a different incarnation of %s %q", h.gvr.Resource, name)
+
+	// The merge patch establishes both the label the list step selects on and
+	// the annotations map the JSON patch below adds to.
+	mergePatch := []byte(fmt.Sprintf(
+		`{"metadata":{"labels":{"e2e-dynamic-lifecycle":%q},"annotations":{"e2e-merge-patch":"applied"}}}`, lifecycleValue))
+	patched, err := h.resource().Patch(context.TODO(), name, types.MergePatchType, mergePatch, metav1.PatchOptions{})
+	framework.ExpectNoError(err, "failed to merge patch %s %q", h.gvr.Resource, name)
+	framework.ExpectEqual(patched.GetLabels()["e2e-dynamic-lifecycle"], lifecycleValue, "merge patch did not apply the label to %s %q", h.gvr.Resource, name)
+
+	if !opts.SkipStrategicMergePatch {
+		patched, err = h.resource().Patch(context.TODO(), name, types.StrategicMergePatchType,
+			[]byte(`{"metadata":{"annotations":{"e2e-strategic-patch":"applied"}}}`), metav1.PatchOptions{})
+		framework.ExpectNoError(err, "failed to strategic merge patch %s %q", h.gvr.Resource, name)
+		framework.ExpectEqual(patched.GetAnnotations()["e2e-strategic-patch"], "applied", "strategic merge patch did not apply to %s %q", h.gvr.Resource, name)
+	}
+
+	patched, err = h.resource().Patch(context.TODO(), name, types.JSONPatchType,
+		[]byte(`[{"op":"add","path":"/metadata/annotations/e2e-json-patch","value":"applied"}]`), metav1.PatchOptions{})
+	framework.ExpectNoError(err, "failed to JSON patch %s %q", h.gvr.Resource, name)
+	framework.ExpectEqual(patched.GetAnnotations()["e2e-json-patch"], "applied", "JSON patch did not apply to %s %q", h.gvr.Resource, name)
+
+	selector := "e2e-dynamic-lifecycle=" + lifecycleValue
+	list, err := h.resource().List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	framework.ExpectNoError(err, "failed to list %s with selector %s", h.gvr.Resource, selector)
+	framework.ExpectEqual(len(list.Items), 1, "list with selector %s did not return exactly the patched %s", selector, h.gvr.Resource)
+	framework.ExpectEqual(list.Items[0].GetName(), name, "list with selector %s returned the wrong %s", selector, h.gvr.Resource)
+
+	if opts.SkipDeleteCollection {
+		err = h.resource().Delete(context.TODO(), name, metav1.DeleteOptions{})
+		framework.ExpectNoError(err, "failed to delete %s %q", h.gvr.Resource, name)
+	} else {
+		err = h.resource().DeleteCollection(context.TODO(), metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector})
+		framework.ExpectNoError(err, "failed to deleteCollection %s with selector %s", h.gvr.Resource, selector)
+	}
+
+	timeout := opts.DeletionTimeout
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+	framework.ExpectNoError(wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		remaining, err := h.resource().List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, err
+		}
+		return len(remaining.Items) == 0, nil
+	}), "%s %q was not removed after the delete step", h.gvr.Resource, name)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// Capture buffers every Event observed in one namespace between StartCapture
+// and Stop. Repeated occurrences of the same event (count increments and
+// EventSeries updates) are folded into one entry, so a Dump of the buffer
+// reads like `kubectl get events` did at the moment the spec ended.
+type Capture struct {
+	namespace string
+
+	mu     sync.Mutex
+	order  []types.UID
+	events map[types.UID]*v1.Event
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// StartCapture opens a watch on the Events of the given namespace and buffers
+// everything it delivers until Stop is called. The watch is re-established
+// if it expires; the capture also winds down on its own once the namespace
+// is gone.
+func StartCapture(c clientset.Interface, namespace string) *Capture {
+	capture := &Capture{
+		namespace: namespace,
+		events:    map[types.UID]*v1.Event{},
+		stopCh:    make(chan struct{}),
+	}
+	capture.wg.Add(1)
+	go func() {
+		defer capture.wg.Done()
+		for {
+			w, err := c.CoreV1().Events(namespace).Watch(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return
+				}
+				select {
+				case <-capture.stopCh:
+					return
+				case <-time.After(time.Second):
+					continue
+				}
+			}
+			if !capture.consume(w) {
+				return
+			}
+		}
+	}()
+	return capture
+}
+
+// consume buffers events from one watch until it closes; it reports whether
+// the watch should be re-established.
+func (c *Capture) consume(w watch.Interface) bool {
+	defer w.Stop()
+	for {
+		select {
+		case <-c.stopCh:
+			return false
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return true
+			}
+			obj, ok := event.Object.(*v1.Event)
+			if !ok {
+				continue
+			}
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				continue
+			}
+			c.mu.Lock()
+			if _, seen := c.events[obj.UID]; !seen {
+				c.order = append(c.order, obj.UID)
+			}
+			c.events[obj.UID] = obj.DeepCopy()
+			c.mu.Unlock()
+		}
+	}
+}
+
+// Stop terminates the capture. Safe to call more than once; the buffer stays
+// available for Dump afterwards.
+func (c *Capture) Stop() {
+	c.stopOnce.Do(func() { close(c.stopCh) })
+	c.wg.Wait()
+}
+
+// Namespace returns the namespace the capture is watching.
+func (c *Capture) Namespace() string {
+	return c.namespace
+}
+
+// Dump renders the buffered events in order of first observation, one line
+// per event with its occurrence count.
+func (c *Capture) Dump() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.order) == 0 {
+		return fmt.Sprintf("no events observed in namespace %s", c.namespace)
+	}
+	var lines []string
+	for _, uid := range c.order {
+		event := c.events[uid]
+		timestamp := event.LastTimestamp.Time
+		if timestamp.IsZero() {
+			timestamp = event.EventTime.Time
+		}
+		count := event.Count
+		if event.Series != nil {
+			count = event.Series.Count
+			if t := event.Series.LastObservedTime.Time; !t.IsZero() {
+				timestamp = t
+			}
+		}
+		line := fmt.Sprintf("%s %s %s %s/%s: %s",
+			timestamp.Format(time.RFC3339), event.Type, event.Reason,
+			event.InvolvedObject.Kind, event.InvolvedObject.Name, event.Message)
+		if count > 1 {
+			line += fmt.Sprintf(" (x%d)", count)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// Event reasons the control plane emits during the lifecycle of namespace
+// content. Specs should assert on these reasons rather than on event
+// messages, whose wording is not part of any compatibility guarantee.
+const (
+	// ReasonSuccessfulCreate is emitted by workload controllers when they
+	// create a pod.
+	ReasonSuccessfulCreate = "SuccessfulCreate"
+	// ReasonFailedCreate is emitted by workload controllers when a pod
+	// create is rejected, e.g. by quota or a terminating namespace.
+	ReasonFailedCreate = "FailedCreate"
+	// ReasonSuccessfulDelete is emitted by workload controllers when they
+	// delete a pod, e.g. while scaling down.
+	ReasonSuccessfulDelete = "SuccessfulDelete"
+	// ReasonFailedDelete is emitted by workload controllers when a pod
+	// delete is rejected, e.g. by an admission webhook.
+	ReasonFailedDelete = "FailedDelete"
+	// ReasonFailedScheduling is emitted by the scheduler for pods it cannot
+	// place.
+	ReasonFailedScheduling = "FailedScheduling"
+	// ReasonKilling is emitted by the kubelet when it stops a container,
+	// e.g. because its namespace is being torn down.
+	ReasonKilling = "Killing"
+)
+
+func eventsWithReason(c clientset.Interface, namespace, reason string) ([]string, error) {
+	events, err := c.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{FieldSelector: "reason=" + reason})
+	if err != nil {
+		return nil, fmt.Errorf("got error while getting events: %v", err)
+	}
+	messages := make([]string, 0, len(events.Items))
+	for _, event := range events.Items {
+		messages = append(messages, event.Message)
+	}
+	return messages, nil
+}
+
+// ExpectEventWithReason waits up to the given duration for an event with the
+// given reason to appear in the namespace.
+// Please note delivery of events is not guaranteed. Asserting on events can lead to flaky tests.
+func ExpectEventWithReason(c clientset.Interface, namespace, reason string, within time.Duration) error {
+	interval := 2 * time.Second
+	err := wait.PollImmediate(interval, within, func() (bool, error) {
+		messages, err := eventsWithReason(c, namespace, reason)
+		if err != nil {
+			return false, err
+		}
+		return len(messages) > 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("no event with reason %q observed in namespace %s within %v: %v", reason, namespace, within, err)
+	}
+	return nil
+}
+
+// ExpectNoEventWithReason verifies that no event with the given reason shows
+// up in the namespace for the given duration.
+func ExpectNoEventWithReason(c clientset.Interface, namespace, reason string, within time.Duration) error {
+	interval := 2 * time.Second
+	err := wait.PollImmediate(interval, within, func() (bool, error) {
+		messages, err := eventsWithReason(c, namespace, reason)
+		if err != nil {
+			return false, err
+		}
+		if len(messages) > 0 {
+			return false, fmt.Errorf("unexpected event with reason %q in namespace %s: %s", reason, namespace, messages[0])
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return nil
+	}
+	return err
+}
@@ -21,6 +21,8 @@ import (
 
 	"github.com/onsi/gomega"
 	"github.com/onsi/gomega/format"
+
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // ExpectEqual expects the specified two are the same, otherwise an exception raises
@@ -76,6 +78,21 @@ func ExpectNoErrorWithOffset(offset int, err error, explain ...interface{}) {
 	Fail(prefix+err.Error(), 1+offset)
 }
 
+// ExpectNoErrorWithObject checks if "err" is set like ExpectNoError does, and
+// additionally renders the given object (for example the namespace or pod that
+// was being waited on) into the failure log. This removes the need for manual
+// Logf calls dumping object state next to every wait helper. A nil object is
+// ignored.
+func ExpectNoErrorWithObject(err error, obj runtime.Object, explain ...interface{}) {
+	if err == nil {
+		return
+	}
+	if obj != nil {
+		Logf("Last observed state of object associated with the failure:\n%s", format.Object(obj, 1))
+	}
+	ExpectNoErrorWithOffset(1, err, explain...)
+}
+
 // ExpectConsistOf expects actual contains precisely the extra elements.  The ordering of the elements does not matter.
 func ExpectConsistOf(actual interface{}, extra interface{}, explain ...interface{}) {
 	gomega.ExpectWithOffset(1, actual).To(gomega.ConsistOf(extra), explain...)
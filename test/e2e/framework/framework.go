@@ -53,6 +53,7 @@ import (
 	"github.com/onsi/gomega"
 
 	// TODO: Remove the following imports (ref: https://github.com/kubernetes/kubernetes/issues/81245)
+	e2eevents "k8s.io/kubernetes/test/e2e/framework/events"
 	e2emetrics "k8s.io/kubernetes/test/e2e/framework/metrics"
 )
 
@@ -77,6 +78,14 @@ type Framework struct {
 
 	DynamicClient dynamic.Interface
 
+	// SecondaryClientSet talks to the optional second cluster configured via
+	// --secondary-kubeconfig; nil when no secondary cluster is configured.
+	// Namespaces created there through CreateSecondaryNamespace are tracked
+	// separately because they live in a different cluster.
+	SecondaryClientSet          clientset.Interface
+	secondaryClientConfig       *rest.Config
+	secondaryNamespacesToDelete []*v1.Namespace
+
 	ScalesGetter scaleclient.ScalesGetter
 
 	SkipNamespaceCreation            bool            // Whether to skip creating a namespace
@@ -98,6 +107,55 @@ type Framework struct {
 	// Flaky operation failures in an e2e test can be captured through this.
 	flakeReport *FlakeReport
 
+	// Retries performed by framework helpers during the spec are aggregated here.
+	retryReport *RetryReport
+
+	// namespaceTeardownChecks are run for each test namespace once its deletion
+	// has completed; failures are aggregated into the spec result.
+	namespaceTeardownChecks []NamespaceTeardownCheck
+
+	// namespaceTimings records lifecycle durations of namespaces managed by
+	// the framework in the current spec, keyed by namespace name.
+	namespaceTimings     map[string]*NamespaceTiming
+	namespaceTimingsLock sync.Mutex
+
+	// cleanupClientSet, when non-nil, is a rate-limited client used for
+	// namespace deletion in AfterEach instead of ClientSet.
+	cleanupClientSet clientset.Interface
+
+	// apiErrorSummary aggregates non-2xx API responses observed during the
+	// spec; it is written out as a JSON artifact in AfterEach.
+	apiErrorSummary *APIErrorSummary
+
+	// apiCallSummary, when API call budgeting is enabled, counts every API
+	// request issued during the spec; the budget is enforced in AfterEach.
+	apiCallSummary *APICallSummary
+
+	// eventCaptures buffer the event streams of the spec's namespaces so they
+	// can be attached to the report when the spec fails.
+	eventCaptures     []*e2eevents.Capture
+	eventCapturesLock sync.Mutex
+
+	// rbacFixtures names the cluster-scoped RBAC objects created for
+	// impersonated users, which must be deleted explicitly since they do
+	// not belong to any test namespace.
+	rbacFixtures     []string
+	rbacFixturesLock sync.Mutex
+
+	// identityClients caches the impersonating clientsets handed out by
+	// ClientFor for the duration of one spec.
+	identityClients     map[Identity]clientset.Interface
+	identityClientsLock sync.Mutex
+
+	// specCtx is cancelled when the current spec's cleanup finishes, so that
+	// API calls left hanging by the spec are aborted rather than leaked.
+	specCtx    context.Context
+	specCancel context.CancelFunc
+
+	// specStartTime bounds the audit slice collected for a failing spec; see
+	// audit_slice.go.
+	specStartTime time.Time
+
 	// afterEaches is a map of name to function to be called after each test.  These are not
 	// cleared.  The call order is randomized so that no dependencies can grow between
 	// the various afterEaches
@@ -123,6 +181,21 @@ type Framework struct {
 // AfterEachActionFunc is a function that can be called after each test
 type AfterEachActionFunc func(f *Framework, failed bool)
 
+// NamespaceTeardownCheck verifies that nothing related to the given, now
+// deleted, namespace was left behind (e.g. pods on nodes, cloud resources).
+// A non-nil error fails the spec.
+type NamespaceTeardownCheck func(f *Framework, namespace string) error
+
+// NamespaceTiming records how long lifecycle operations took for one
+// namespace managed by the framework. DeleteDuration covers the full
+// termination (delete call plus wait) and is zero for namespaces that were
+// not (yet) deleted through the framework.
+type NamespaceTiming struct {
+	Name           string
+	CreateDuration time.Duration
+	DeleteDuration time.Duration
+}
+
 // TestDataSummary is an interface for managing test data.
 type TestDataSummary interface {
 	SummaryKind() string
@@ -178,8 +251,30 @@ func NewFramework(baseName string, options Options, client clientset.Interface)
 		}
 	})
 
+	f.AddAfterEach("namespaceContentLeakDetector", func(f *Framework, failed bool) {
+		if failed || TestContext.DumpNamespaceContentAlways {
+			reportNamespaceContentLeaks(f)
+		}
+	})
+
+	f.AddAfterEach("eventStreamDump", func(f *Framework, failed bool) {
+		f.stopEventCaptures(failed)
+	})
+
+	f.AddAfterEach("rbacFixtureCleanup", func(f *Framework, failed bool) {
+		f.cleanupRBACFixtures()
+	})
+
+	f.AddAfterEach("auditSliceDump", func(f *Framework, failed bool) {
+		if failed && TestContext.AuditLogPath != "" {
+			f.dumpAuditSlice()
+		}
+	})
+
 	ginkgo.BeforeEach(f.BeforeEach)
 	ginkgo.AfterEach(f.AfterEach)
+	// Per-spec timing summaries are written once the spec report is final.
+	ginkgo.ReportAfterEach(writeSpecTimingSummary)
 
 	return f
 }
@@ -187,6 +282,13 @@ func NewFramework(baseName string, options Options, client clientset.Interface)
 // BeforeEach gets a client and makes a namespace.
 func (f *Framework) BeforeEach() {
 	f.beforeEachStarted = true
+	f.specStartTime = time.Now()
+
+	// The vendored ginkgo predates SpecContext, so the framework maintains a
+	// per-spec context itself; it is cancelled once AfterEach finishes so
+	// helpers still waiting on the API are unblocked instead of leaking
+	// goroutines into later specs.
+	f.specCtx, f.specCancel = context.WithCancel(context.Background())
 
 	if f.ClientSet == nil {
 		ginkgo.By("Creating a kubernetes client")
@@ -201,12 +303,41 @@ func (f *Framework) BeforeEach() {
 		if TestContext.KubeAPIContentType != "" {
 			config.ContentType = TestContext.KubeAPIContentType
 		}
+		// Capture every non-2xx response for this spec's API error summary.
+		config.Wrap(wrapTransportForAPIErrorCapture)
+		// Opt-in API call accounting: only instrument when a budget is set.
+		if TestContext.APICallBudget > 0 {
+			config.Wrap(wrapTransportForAPICallAccounting)
+		}
+		// Opt-in retries of idempotent reads on transient apiserver errors.
+		if TestContext.RetryTransientAPIErrors {
+			config.Wrap(wrapTransportForTransientRetries)
+		}
+		// Watchdog for calls issued without a deadline; see api_watchdog.go.
+		if TestContext.APICallHardCeiling > 0 {
+			config.Wrap(wrapTransportForAPICallWatchdog)
+		}
 		f.clientConfig = rest.CopyConfig(config)
 		f.ClientSet, err = clientset.NewForConfig(config)
 		ExpectNoError(err)
 		f.DynamicClient, err = dynamic.NewForConfig(config)
 		ExpectNoError(err)
 
+		// Optionally route cleanup traffic through a lower rate limit so that
+		// teardown from many parallel specs cannot starve still-running tests
+		// on a shared cluster.
+		f.cleanupClientSet = nil
+		if TestContext.CleanupQPS > 0 {
+			cleanupConfig := rest.CopyConfig(config)
+			cleanupConfig.QPS = float32(TestContext.CleanupQPS)
+			cleanupConfig.Burst = TestContext.CleanupBurst
+			if cleanupConfig.Burst <= 0 {
+				cleanupConfig.Burst = int(TestContext.CleanupQPS)
+			}
+			f.cleanupClientSet, err = clientset.NewForConfig(cleanupConfig)
+			ExpectNoError(err)
+		}
+
 		// create scales getter, set GroupVersion and NegotiatedSerializer to default values
 		// as they are required when creating a REST client.
 		if config.GroupVersion == nil {
@@ -228,6 +359,11 @@ func (f *Framework) BeforeEach() {
 		TestContext.CloudConfig.Provider.FrameworkBeforeEach(f)
 	}
 
+	if SecondaryClusterConfigured() && f.SecondaryClientSet == nil {
+		ginkgo.By("Creating a client for the secondary cluster")
+		f.setupSecondaryCluster()
+	}
+
 	if !f.SkipNamespaceCreation {
 		ginkgo.By(fmt.Sprintf("Building a namespace api object, basename %s", f.BaseName))
 		namespace, err := f.CreateNamespace(f.BaseName, map[string]string{
@@ -238,11 +374,8 @@ func (f *Framework) BeforeEach() {
 		f.Namespace = namespace
 
 		if TestContext.VerifyServiceAccount {
-			ginkgo.By("Waiting for a default service account to be provisioned in namespace")
-			err = WaitForDefaultServiceAccountInNamespace(f.ClientSet, namespace.Name)
-			ExpectNoError(err)
-			ginkgo.By("Waiting for kube-root-ca.crt to be provisioned in namespace")
-			err = WaitForKubeRootCAInNamespace(f.ClientSet, namespace.Name)
+			ginkgo.By("Waiting for a default service account and kube-root-ca.crt to be provisioned in namespace")
+			err = WaitForNamespaceInitialized(context.TODO(), f.ClientSet, namespace.Name)
 			ExpectNoError(err)
 		} else {
 			Logf("Skipping waiting for service account")
@@ -307,6 +440,20 @@ func (f *Framework) BeforeEach() {
 	}
 
 	f.flakeReport = NewFlakeReport()
+	f.retryReport = NewRetryReport()
+	f.namespaceTimings = map[string]*NamespaceTiming{}
+	f.apiErrorSummary = NewAPIErrorSummary()
+	setCurrentAPIErrorSummary(f.apiErrorSummary)
+	if TestContext.APICallBudget > 0 {
+		f.apiCallSummary = NewAPICallSummary()
+		setCurrentAPICallSummary(f.apiCallSummary)
+	}
+	setCurrentRetryReport(f.retryReport)
+	installSpecTiming()
+
+	// Downstream per-spec setup runs last, once the client and the spec's
+	// namespace are in place; see RegisterSpecHook for the ordering contract.
+	runSpecHooksBefore(f)
 }
 
 // printSummaries prints summaries of tests.
@@ -359,6 +506,23 @@ func (f *Framework) AddAfterEach(name string, fn AfterEachActionFunc) {
 	f.afterEaches[name] = fn
 }
 
+// cleanupClient returns the client used for teardown API calls: the
+// rate-limited cleanup client when one was configured, the regular client
+// otherwise.
+func (f *Framework) cleanupClient() clientset.Interface {
+	if f.cleanupClientSet != nil {
+		return f.cleanupClientSet
+	}
+	return f.ClientSet
+}
+
+// AddNamespaceTeardownCheck registers a verifier that is run for every test
+// namespace once its deletion has completed. Checks are scoped to the current
+// spec; register them from a BeforeEach (or the spec body) as needed.
+func (f *Framework) AddNamespaceTeardownCheck(check NamespaceTeardownCheck) {
+	f.namespaceTeardownChecks = append(f.namespaceTeardownChecks, check)
+}
+
 // AfterEach deletes the namespace, after reading its events.
 func (f *Framework) AfterEach() {
 	// If BeforeEach never started AfterEach should be skipped.
@@ -373,17 +537,43 @@ func (f *Framework) AfterEach() {
 		Failf("The framework ClientSet must not be nil at this point")
 	}
 
+	// Registered first so it runs after the namespace-deletion defer below:
+	// cancelling the spec context must not interrupt cleanup itself, only
+	// whatever the spec left behind.
+	defer func() {
+		if f.specCancel != nil {
+			f.specCancel()
+			f.specCtx, f.specCancel = nil, nil
+		}
+	}()
+
 	// DeleteNamespace at the very end in defer, to avoid any
 	// expectation failures preventing deleting the namespace.
 	defer func() {
 		nsDeletionErrors := map[string]error{}
+		var teardownCheckFailures []string
 		// Whether to delete namespace is determined by 3 factors: delete-namespace flag, delete-namespace-on-failure flag and the test result
 		// if delete-namespace set to false, namespace will always be preserved.
 		// if delete-namespace is true and delete-namespace-on-failure is false, namespace will be preserved if test failed.
-		if TestContext.DeleteNamespace && (TestContext.DeleteNamespaceOnFailure || !ginkgo.CurrentSpecReport().Failed()) {
+		if UsingExistingNamespaces() {
+			// Leased namespaces are returned to the pool with their content
+			// removed; they are never deleted, so the post-termination
+			// teardown checks cannot run in this mode.
+			for _, ns := range f.namespacesToDelete {
+				ginkgo.By(fmt.Sprintf("Releasing leased namespace %q back to the pool.", ns.Name))
+				if err := releaseExistingNamespace(f.ClientSet, f.DynamicClient, ns.Name); err != nil {
+					nsDeletionErrors[ns.Name] = err
+				}
+			}
+			if len(f.namespaceTeardownChecks) > 0 {
+				Logf("Skipping %d namespace teardown checks: leased namespaces are not deleted", len(f.namespaceTeardownChecks))
+			}
+		} else if TestContext.DeleteNamespace && (TestContext.DeleteNamespaceOnFailure || !ginkgo.CurrentSpecReport().Failed()) {
+			var deleted []string
+			cleanupClient := f.cleanupClient()
 			for _, ns := range f.namespacesToDelete {
 				ginkgo.By(fmt.Sprintf("Destroying namespace %q for this suite.", ns.Name))
-				if err := f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), ns.Name, metav1.DeleteOptions{}); err != nil {
+				if err := cleanupClient.CoreV1().Namespaces().Delete(context.TODO(), ns.Name, metav1.DeleteOptions{}); err != nil {
 					if !apierrors.IsNotFound(err) {
 						nsDeletionErrors[ns.Name] = err
 
@@ -393,6 +583,40 @@ func (f *Framework) AfterEach() {
 						}
 					} else {
 						Logf("Namespace %v was already deleted", ns.Name)
+						CheckpointNamespaceDeleted(ns.Name)
+					}
+				} else {
+					deleted = append(deleted, ns.Name)
+					CheckpointNamespaceDeleted(ns.Name)
+				}
+			}
+
+			// Registered teardown checks only make sense once the namespaces are
+			// actually gone, so wait for termination to finish first.
+			if len(f.namespaceTeardownChecks) > 0 && len(deleted) > 0 {
+				timeout := f.NamespaceDeletionTimeout
+				if timeout == 0 {
+					timeout = f.Timeouts.NamespaceDelete
+				}
+				if err := WaitForNamespacesDeleted(cleanupClient, deleted, timeout); err != nil {
+					// Replace the generic timeout with the actual cause where a
+					// classifier recognizes one (e.g. an unavailable APIService).
+					failure := fmt.Sprintf("failed waiting for namespaces %v to finish terminating before running teardown checks: %v", deleted, err)
+					for _, name := range deleted {
+						if classification := ClassifyStuckNamespace(cleanupClient, f.DynamicClient, name); classification != "" {
+							failure = fmt.Sprintf("%s; %s", failure, classification)
+						}
+					}
+					teardownCheckFailures = append(teardownCheckFailures, failure)
+					LogStuckNamespaceAnalysis(cleanupClient, f.DynamicClient, deleted...)
+				} else {
+					for _, name := range deleted {
+						for _, check := range f.namespaceTeardownChecks {
+							if err := check(f, name); err != nil {
+								teardownCheckFailures = append(teardownCheckFailures,
+									fmt.Sprintf("teardown check failed for namespace %q: %v", name, err))
+							}
+						}
 					}
 				}
 			}
@@ -404,22 +628,38 @@ func (f *Framework) AfterEach() {
 			}
 		}
 
+		// The secondary cluster has its own namespace lifecycle; its teardown
+		// failures are reported together with the primary cluster's.
+		for name, err := range f.deleteSecondaryNamespaces() {
+			nsDeletionErrors["secondary cluster namespace "+name] = err
+		}
+
 		// Paranoia-- prevent reuse!
 		f.Namespace = nil
 		f.clientConfig = nil
 		f.ClientSet = nil
+		f.cleanupClientSet = nil
 		f.namespacesToDelete = nil
-
-		// if we had errors deleting, report them now.
-		if len(nsDeletionErrors) != 0 {
-			messages := []string{}
-			for namespaceKey, namespaceErr := range nsDeletionErrors {
-				messages = append(messages, fmt.Sprintf("Couldn't delete ns: %q: %s (%#v)", namespaceKey, namespaceErr, namespaceErr))
-			}
+		f.namespaceTeardownChecks = nil
+		f.SecondaryClientSet = nil
+		f.secondaryClientConfig = nil
+		f.secondaryNamespacesToDelete = nil
+
+		// if we had errors deleting or verifying teardown, report them now.
+		messages := []string{}
+		for namespaceKey, namespaceErr := range nsDeletionErrors {
+			messages = append(messages, fmt.Sprintf("Couldn't delete ns: %q: %s (%#v)", namespaceKey, namespaceErr, namespaceErr))
+		}
+		messages = append(messages, teardownCheckFailures...)
+		if len(messages) != 0 {
 			Failf(strings.Join(messages, ","))
 		}
 	}()
 
+	// Downstream per-spec cleanup runs first, before any framework teardown;
+	// see RegisterSpecHook for the ordering contract.
+	runSpecHooksAfter(f, ginkgo.CurrentSpecReport().Failed())
+
 	// run all aftereach functions in random order to ensure no dependencies grow
 	for _, afterEachFn := range f.afterEaches {
 		afterEachFn(f, ginkgo.CurrentSpecReport().Failed())
@@ -464,6 +704,32 @@ func (f *Framework) AfterEach() {
 		f.flakeReport = nil
 	}
 
+	// Retain what this spec gathered for the enriched JSON report before the
+	// per-spec collectors are reset below.
+	f.recordSpecEnrichment()
+
+	// Persist the API error distribution for this spec and reset.
+	setCurrentAPIErrorSummary(nil)
+	if f.apiErrorSummary != nil {
+		f.apiErrorSummary.WriteArtifact(f.UniqueName)
+		f.apiErrorSummary = nil
+	}
+
+	// Enforce the API call budget, if one was configured, and reset.
+	setCurrentAPICallSummary(nil)
+	if f.apiCallSummary != nil {
+		summary := f.apiCallSummary
+		f.apiCallSummary = nil
+		summary.EnforceBudget(TestContext.APICallBudget, TestContext.APICallBudgetAction)
+	}
+
+	// Report retries performed by framework helpers during the spec and reset.
+	setCurrentRetryReport(nil)
+	if f.retryReport != nil && f.retryReport.GetRetryCount() > 0 {
+		f.TestSummaries = append(f.TestSummaries, f.retryReport)
+		f.retryReport = nil
+	}
+
 	printSummaries(f.TestSummaries, f.BaseName)
 
 	// Check whether all nodes are ready after the test.
@@ -474,21 +740,65 @@ func (f *Framework) AfterEach() {
 	}
 }
 
+// recordNamespaceTiming merges a measured lifecycle duration into the
+// per-spec timings.
+func (f *Framework) recordNamespaceTiming(name string, createDuration, deleteDuration time.Duration) {
+	f.namespaceTimingsLock.Lock()
+	defer f.namespaceTimingsLock.Unlock()
+	if f.namespaceTimings == nil {
+		f.namespaceTimings = map[string]*NamespaceTiming{}
+	}
+	timing, ok := f.namespaceTimings[name]
+	if !ok {
+		timing = &NamespaceTiming{Name: name}
+		f.namespaceTimings[name] = timing
+	}
+	if createDuration > 0 {
+		timing.CreateDuration = createDuration
+		recordSpecNamespaceCreated()
+	}
+	if deleteDuration > 0 {
+		timing.DeleteDuration = deleteDuration
+	}
+	recordSuiteNamespaceTiming(createDuration, deleteDuration)
+}
+
+// NamespaceTimings returns the lifecycle durations of all namespaces managed
+// by the framework in the current spec, so composite tests can assert
+// end-to-end budgets without reimplementing timing capture.
+func (f *Framework) NamespaceTimings() []NamespaceTiming {
+	f.namespaceTimingsLock.Lock()
+	defer f.namespaceTimingsLock.Unlock()
+	timings := make([]NamespaceTiming, 0, len(f.namespaceTimings))
+	for _, timing := range f.namespaceTimings {
+		timings = append(timings, *timing)
+	}
+	return timings
+}
+
 // DeleteNamespace can be used to delete a namespace. Additionally it can be used to
 // dump namespace information so as it can be used as an alternative of framework
 // deleting the namespace towards the end.
 func (f *Framework) DeleteNamespace(name string) {
 	defer func() {
+		start := time.Now()
 		err := f.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
 		if err != nil && !apierrors.IsNotFound(err) {
 			Logf("error deleting namespace %s: %v", name, err)
 			return
 		}
-		err = WaitForNamespacesDeleted(f.ClientSet, []string{name}, DefaultNamespaceDeletionTimeout)
+		err = WaitForNamespacesDeleted(f.ClientSet, []string{name}, f.Timeouts.NamespaceDelete)
 		if err != nil {
-			Logf("error deleting namespace %s: %v", name, err)
+			if classification := ClassifyStuckNamespace(f.ClientSet, f.DynamicClient, name); classification != "" {
+				Logf("error deleting namespace %s: %s", name, classification)
+			} else {
+				Logf("error deleting namespace %s: %v", name, err)
+			}
+			LogStuckNamespaceAnalysis(f.ClientSet, f.DynamicClient, name)
 			return
 		}
+		f.recordNamespaceTiming(name, 0, time.Since(start))
+		CheckpointNamespaceDeleted(name)
 		// remove deleted namespace from namespacesToDelete map
 		for i, ns := range f.namespacesToDelete {
 			if ns == nil {
@@ -506,11 +816,58 @@ func (f *Framework) DeleteNamespace(name string) {
 
 }
 
+// SpecContext returns a context that is cancelled once the current spec's
+// cleanup has finished. Helpers threaded with it stop waiting on the API
+// when the spec is over instead of leaking goroutines into later specs.
+// Outside a spec it returns a background context.
+func (f *Framework) SpecContext() context.Context {
+	if f.specCtx == nil {
+		return context.Background()
+	}
+	return f.specCtx
+}
+
 // CreateNamespace creates a namespace for e2e testing.
 func (f *Framework) CreateNamespace(baseName string, labels map[string]string) (*v1.Namespace, error) {
-	createTestingNS := TestContext.CreateTestingNS
-	if createTestingNS == nil {
-		createTestingNS = CreateTestingNS
+	return f.CreateNamespaceWithContext(f.SpecContext(), baseName, labels)
+}
+
+// CreateNamespaceWithContext is CreateNamespace with an explicit context
+// bounding the creation retries and the wait for the default service account.
+func (f *Framework) CreateNamespaceWithContext(ctx context.Context, baseName string, labels map[string]string) (*v1.Namespace, error) {
+	return f.createNamespace(ctx, baseName, labels, false)
+}
+
+// createNamespace is the shared creation path. With useGenerateName the
+// server picks the name via metadata.generateName, which removes name
+// conflicts between concurrent creators; the server-assigned name is on the
+// returned namespace.
+func (f *Framework) createNamespace(ctx context.Context, baseName string, labels map[string]string, useGenerateName bool) (*v1.Namespace, error) {
+	// In pre-provisioned namespace mode a lease from the pool replaces
+	// creation; the namespace is still registered for teardown, which then
+	// cleans its content and returns it instead of deleting it.
+	if UsingExistingNamespaces() {
+		start := time.Now()
+		ns, err := leaseExistingNamespace(ctx, f.ClientSet, labels)
+		f.AddNamespacesToDelete(ns)
+		if ns != nil {
+			f.recordNamespaceTiming(ns.Name, time.Since(start), 0)
+		}
+		return ns, err
+	}
+	// The namespace is registered for teardown the moment it exists, before
+	// the initialization wait: a wait failure (or an abort during the wait)
+	// must not orphan a namespace the apiserver already accepted.
+	registered := false
+	onCreated := func(ns *v1.Namespace) {
+		f.AddNamespacesToDelete(ns)
+		registered = true
+	}
+	create := func(baseName string, c clientset.Interface, labels map[string]string) (*v1.Namespace, error) {
+		return createTestingNS(ctx, baseName, c, labels, useGenerateName, onCreated)
+	}
+	if TestContext.CreateTestingNS != nil {
+		create = TestContext.CreateTestingNS
 	}
 
 	if labels == nil {
@@ -529,14 +886,142 @@ func (f *Framework) CreateNamespace(baseName string, labels map[string]string) (
 	}
 	labels[admissionapi.EnforceLevelLabel] = string(enforceLevel)
 
-	ns, err := createTestingNS(baseName, f.ClientSet, labels)
-	// check ns instead of err to see if it's nil as we may
-	// fail to create serviceAccount in it.
-	f.AddNamespacesToDelete(ns)
+	start := time.Now()
+	ns, err := create(baseName, f.ClientSet, labels)
+	RecordFrameworkWait("namespace-creation", time.Since(start))
+	// Custom CreateTestingNS implementations cannot register through the
+	// early hook; check ns instead of err as the namespace may exist even
+	// when the initialization wait failed.
+	if !registered {
+		f.AddNamespacesToDelete(ns)
+	}
+	if ns != nil {
+		f.recordNamespaceTiming(ns.Name, time.Since(start), 0)
+	}
 
 	return ns, err
 }
 
+// NamespaceOptions composes everything beyond plain labels that tests attach
+// to a namespace at creation: annotations, a per-namespace pod security
+// level, user finalizers, and ResourceQuota/LimitRange presets that must be
+// in place before any test traffic enters the namespace.
+type NamespaceOptions struct {
+	Labels      map[string]string
+	Annotations map[string]string
+	// Finalizers are metadata finalizers added to the namespace; the test is
+	// responsible for removing them again before or during teardown.
+	Finalizers []string
+	// PodSecurityEnforceLevel overrides the framework-wide enforce level for
+	// this namespace only.
+	PodSecurityEnforceLevel admissionapi.Level
+	ResourceQuotas          []*v1.ResourceQuota
+	LimitRanges             []*v1.LimitRange
+	// UseGenerateName lets the server pick the namespace name through
+	// metadata.generateName instead of the framework's own random-suffix
+	// convention, so concurrent creators cannot race on names.
+	UseGenerateName bool
+}
+
+// CreateNamespaceFromSpec creates a namespace for e2e testing with the full
+// set of options applied before it returns. If attaching any of the options
+// fails the namespace is deleted again, so tests never run against a
+// half-configured namespace.
+func (f *Framework) CreateNamespaceFromSpec(baseName string, opts NamespaceOptions) (*v1.Namespace, error) {
+	ctx := f.SpecContext()
+	ns, err := f.createNamespace(ctx, baseName, opts.Labels, opts.UseGenerateName)
+	if err != nil || ns == nil {
+		return ns, err
+	}
+	applyOptions := func() error {
+		if len(opts.Annotations) > 0 || len(opts.Finalizers) > 0 || opts.PodSecurityEnforceLevel != "" {
+			if ns.Annotations == nil {
+				ns.Annotations = map[string]string{}
+			}
+			for k, v := range opts.Annotations {
+				ns.Annotations[k] = v
+			}
+			for _, finalizer := range opts.Finalizers {
+				ns.Finalizers = append(ns.Finalizers, finalizer)
+			}
+			if opts.PodSecurityEnforceLevel != "" {
+				ns.Labels[admissionapi.EnforceLevelLabel] = string(opts.PodSecurityEnforceLevel)
+			}
+			updated, err := f.ClientSet.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to apply namespace options to %s: %v", ns.Name, err)
+			}
+			ns = updated
+		}
+		for _, quota := range opts.ResourceQuotas {
+			if _, err := f.ClientSet.CoreV1().ResourceQuotas(ns.Name).Create(ctx, quota, metav1.CreateOptions{}); err != nil {
+				return fmt.Errorf("failed to create resource quota %s in namespace %s: %v", quota.Name, ns.Name, err)
+			}
+		}
+		for _, limitRange := range opts.LimitRanges {
+			if _, err := f.ClientSet.CoreV1().LimitRanges(ns.Name).Create(ctx, limitRange, metav1.CreateOptions{}); err != nil {
+				return fmt.Errorf("failed to create limit range %s in namespace %s: %v", limitRange.Name, ns.Name, err)
+			}
+		}
+		return nil
+	}
+	if err := applyOptions(); err != nil {
+		// The namespace is already registered for AfterEach deletion, but
+		// delete eagerly so a failed creation does not leave it lingering for
+		// the rest of the spec. User finalizers were added by us, so strip
+		// them first.
+		if len(opts.Finalizers) > 0 {
+			ns.Finalizers = nil
+			if _, updateErr := f.ClientSet.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{}); updateErr != nil {
+				Logf("Failed to strip finalizers from half-configured namespace %s: %v", ns.Name, updateErr)
+			}
+		}
+		if deleteErr := f.ClientSet.CoreV1().Namespaces().Delete(ctx, ns.Name, metav1.DeleteOptions{}); deleteErr != nil {
+			Logf("Failed to delete half-configured namespace %s: %v", ns.Name, deleteErr)
+		}
+		return nil, err
+	}
+	return ns, nil
+}
+
+// CreateNamespaceWithQuota creates a test namespace that has the given
+// ResourceQuota in place before it returns, so no test traffic can slip in
+// ahead of quota admission. Callers that need the quota's usage figures
+// should additionally WaitForQuotaStatusSynced before asserting on them.
+func (f *Framework) CreateNamespaceWithQuota(baseName string, quota *v1.ResourceQuota) (*v1.Namespace, error) {
+	return f.CreateNamespaceFromSpec(baseName, NamespaceOptions{
+		ResourceQuotas: []*v1.ResourceQuota{quota},
+	})
+}
+
+// WaitForQuotaStatusSynced waits until the resource quota controller has
+// published status for the named quota, i.e. every hard limit in the spec is
+// mirrored in status with its usage computed. Quota admission decisions are
+// only dependable from that point on.
+func WaitForQuotaStatusSynced(c clientset.Interface, namespace, name string, timeout time.Duration) (*v1.ResourceQuota, error) {
+	var quota *v1.ResourceQuota
+	err := wait.PollImmediate(Poll, timeout, func() (bool, error) {
+		var err error
+		quota, err = c.CoreV1().ResourceQuotas(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for resource := range quota.Spec.Hard {
+			if _, ok := quota.Status.Hard[resource]; !ok {
+				return false, nil
+			}
+			if _, ok := quota.Status.Used[resource]; !ok {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return quota, fmt.Errorf("resource quota %s/%s never synced its status: %v", namespace, name, err)
+	}
+	return quota, nil
+}
+
 // RecordFlakeIfError records flakeness info if error happens.
 // NOTE: This function is not used at any places yet, but we are in progress for https://github.com/kubernetes/kubernetes/issues/66239 which requires this. Please don't remove this.
 func (f *Framework) RecordFlakeIfError(err error, optionalDescription ...interface{}) {
@@ -551,7 +1036,42 @@ func (f *Framework) AddNamespacesToDelete(namespaces ...*v1.Namespace) {
 			continue
 		}
 		f.namespacesToDelete = append(f.namespacesToDelete, ns)
+		CheckpointNamespaceCreated(ns.Name)
+		f.startEventCapture(ns.Name)
+	}
+}
+
+// maxEventCaptures bounds the number of per-namespace event watches a single
+// spec keeps open. Bulk soaks create far more namespaces than a per-namespace
+// event dump could usefully cover, and each capture costs a watch.
+const maxEventCaptures = 10
 
+// startEventCapture begins buffering the event stream of a namespace the
+// framework now manages, so it can be attached to the report on failure.
+func (f *Framework) startEventCapture(namespace string) {
+	if f.ClientSet == nil {
+		return
+	}
+	f.eventCapturesLock.Lock()
+	defer f.eventCapturesLock.Unlock()
+	if len(f.eventCaptures) >= maxEventCaptures {
+		return
+	}
+	f.eventCaptures = append(f.eventCaptures, e2eevents.StartCapture(f.ClientSet, namespace))
+}
+
+// stopEventCaptures winds down all event captures of the spec and, when dump
+// is set, attaches each namespace's buffered event stream to the report.
+func (f *Framework) stopEventCaptures(dump bool) {
+	f.eventCapturesLock.Lock()
+	captures := f.eventCaptures
+	f.eventCaptures = nil
+	f.eventCapturesLock.Unlock()
+	for _, capture := range captures {
+		capture.Stop()
+		if dump {
+			ginkgo.AddReportEntry(fmt.Sprintf("events %s", capture.Namespace()), capture.Dump())
+		}
 	}
 }
 
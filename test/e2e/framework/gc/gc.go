@@ -0,0 +1,198 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gc builds owner/dependent object chains for garbage-collection
+// tests. Tests that exercise cascade deletion all need the same scaffolding:
+// an owner, a handful of dependents of different kinds pointing at it, and a
+// wait for the garbage collector to act under a given propagation policy.
+package gc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/test/e2e/framework"
+)
+
+// ForegroundDeleteOptions returns delete options with foreground propagation.
+func ForegroundDeleteOptions() metav1.DeleteOptions {
+	policy := metav1.DeletePropagationForeground
+	return metav1.DeleteOptions{PropagationPolicy: &policy}
+}
+
+// BackgroundDeleteOptions returns delete options with background propagation.
+func BackgroundDeleteOptions() metav1.DeleteOptions {
+	policy := metav1.DeletePropagationBackground
+	return metav1.DeleteOptions{PropagationPolicy: &policy}
+}
+
+// OrphanDeleteOptions returns delete options that orphan dependents.
+func OrphanDeleteOptions() metav1.DeleteOptions {
+	policy := metav1.DeletePropagationOrphan
+	return metav1.DeleteOptions{PropagationPolicy: &policy}
+}
+
+// OwnerReferenceTo returns an owner reference to the given ConfigMap.
+func OwnerReferenceTo(owner *v1.ConfigMap) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       owner.Name,
+		UID:        owner.UID,
+	}
+}
+
+// DanglingOwnerReference returns an owner reference whose UID matches no
+// object, as is left behind when an owner is deleted with orphan propagation
+// and the dependent is later re-adopted, or when an owner reference names an
+// object from another namespace.
+func DanglingOwnerReference(kind, name string) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       kind,
+		Name:       name,
+		UID:        uuid.NewUUID(),
+	}
+}
+
+// Chain is an owner ConfigMap plus dependents of several kinds that reference
+// it. All objects live in one namespace.
+type Chain struct {
+	Client    clientset.Interface
+	Namespace string
+	Owner     *v1.ConfigMap
+	// DependentConfigMaps and DependentSecrets are the names of the
+	// dependents created for the chain.
+	DependentConfigMaps []string
+	DependentSecrets    []string
+}
+
+// CreateChain creates an owner ConfigMap named "<name>-owner" and
+// dependentsPerKind ConfigMaps and Secrets referencing it.
+func CreateChain(c clientset.Interface, namespace, name string, dependentsPerKind int) (*Chain, error) {
+	owner, err := c.CoreV1().ConfigMaps(namespace).Create(context.TODO(), &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-owner"},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create owner configmap: %v", err)
+	}
+	chain := &Chain{Client: c, Namespace: namespace, Owner: owner}
+	ownerRef := OwnerReferenceTo(owner)
+	for i := 0; i < dependentsPerKind; i++ {
+		cm, err := CreateDependentConfigMap(c, namespace, fmt.Sprintf("%s-dependent-cm-%d", name, i), ownerRef)
+		if err != nil {
+			return nil, err
+		}
+		chain.DependentConfigMaps = append(chain.DependentConfigMaps, cm.Name)
+		secret, err := CreateDependentSecret(c, namespace, fmt.Sprintf("%s-dependent-secret-%d", name, i), ownerRef)
+		if err != nil {
+			return nil, err
+		}
+		chain.DependentSecrets = append(chain.DependentSecrets, secret.Name)
+	}
+	return chain, nil
+}
+
+// CreateDependentConfigMap creates a ConfigMap carrying the given owner
+// references.
+func CreateDependentConfigMap(c clientset.Interface, namespace, name string, owners ...metav1.OwnerReference) (*v1.ConfigMap, error) {
+	cm, err := c.CoreV1().ConfigMaps(namespace).Create(context.TODO(), &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, OwnerReferences: owners},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dependent configmap %q: %v", name, err)
+	}
+	return cm, nil
+}
+
+// CreateDependentSecret creates a Secret carrying the given owner references.
+func CreateDependentSecret(c clientset.Interface, namespace, name string, owners ...metav1.OwnerReference) (*v1.Secret, error) {
+	secret, err := c.CoreV1().Secrets(namespace).Create(context.TODO(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, OwnerReferences: owners},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dependent secret %q: %v", name, err)
+	}
+	return secret, nil
+}
+
+// DeleteOwner deletes the chain's owner with the given options.
+func (ch *Chain) DeleteOwner(options metav1.DeleteOptions) error {
+	return ch.Client.CoreV1().ConfigMaps(ch.Namespace).Delete(context.TODO(), ch.Owner.Name, options)
+}
+
+// WaitForDependentsDeleted waits until the garbage collector has removed
+// every dependent of the chain.
+func (ch *Chain) WaitForDependentsDeleted(timeout time.Duration) error {
+	return wait.PollImmediate(framework.Poll, timeout, func() (bool, error) {
+		for _, name := range ch.DependentConfigMaps {
+			if _, err := ch.Client.CoreV1().ConfigMaps(ch.Namespace).Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+				return false, nil
+			} else if !apierrors.IsNotFound(err) {
+				return false, err
+			}
+		}
+		for _, name := range ch.DependentSecrets {
+			if _, err := ch.Client.CoreV1().Secrets(ch.Namespace).Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+				return false, nil
+			} else if !apierrors.IsNotFound(err) {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// WaitForDependentsOrphaned waits until every dependent of the chain still
+// exists but no longer references the (deleted) owner.
+func (ch *Chain) WaitForDependentsOrphaned(timeout time.Duration) error {
+	orphaned := func(refs []metav1.OwnerReference) bool {
+		for _, ref := range refs {
+			if ref.UID == ch.Owner.UID {
+				return false
+			}
+		}
+		return true
+	}
+	return wait.PollImmediate(framework.Poll, timeout, func() (bool, error) {
+		for _, name := range ch.DependentConfigMaps {
+			cm, err := ch.Client.CoreV1().ConfigMaps(ch.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return false, fmt.Errorf("dependent configmap %q should have been orphaned, not deleted: %v", name, err)
+			}
+			if !orphaned(cm.OwnerReferences) {
+				return false, nil
+			}
+		}
+		for _, name := range ch.DependentSecrets {
+			secret, err := ch.Client.CoreV1().Secrets(ch.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return false, fmt.Errorf("dependent secret %q should have been orphaned, not deleted: %v", name, err)
+			}
+			if !orphaned(secret.OwnerReferences) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// Identity names a canonical access level a spec can request a client for,
+// mirroring the user-facing aggregated cluster roles.
+type Identity string
+
+const (
+	// IdentityAdmin is the suite's own (cluster-admin) credentials.
+	IdentityAdmin Identity = "admin"
+	// IdentityEdit is a user bound to the aggregated edit cluster role.
+	IdentityEdit Identity = "edit"
+	// IdentityView is a user bound to the aggregated view cluster role.
+	IdentityView Identity = "view"
+)
+
+// UserFor returns the username ClientFor impersonates for the given identity,
+// for use with authorization helpers that need the subject's name. The admin
+// identity is not impersonated and has no dedicated user.
+func (f *Framework) UserFor(identity Identity) string {
+	if identity == IdentityAdmin {
+		return ""
+	}
+	return fmt.Sprintf("e2e-%s-%s", identity, f.UniqueName)
+}
+
+// ClientFor returns a clientset acting as the given identity. Admin is the
+// suite's own client. Edit and view are realized by impersonating a per-spec
+// user that is bound to the corresponding aggregated cluster role on first
+// use; the binding is removed with the spec's other RBAC fixtures. Callers
+// comparing allowed and denied outcomes should wait for the grant to become
+// effective with auth.WaitForAuthorizationUpdate before asserting.
+func (f *Framework) ClientFor(identity Identity) (clientset.Interface, error) {
+	switch identity {
+	case IdentityAdmin:
+		return f.ClientSet, nil
+	case IdentityEdit, IdentityView:
+	default:
+		return nil, fmt.Errorf("unknown identity %q", identity)
+	}
+	f.identityClientsLock.Lock()
+	defer f.identityClientsLock.Unlock()
+	if client, ok := f.identityClients[identity]; ok {
+		return client, nil
+	}
+	user := f.UserFor(identity)
+	if err := f.GrantClusterRoleToUser(string(identity), user); err != nil {
+		return nil, err
+	}
+	client, err := f.ClientSetForUser(user, nil)
+	if err != nil {
+		return nil, err
+	}
+	if f.identityClients == nil {
+		f.identityClients = map[Identity]clientset.Interface{}
+	}
+	f.identityClients[identity] = client
+	return client, nil
+}
+
+// resetIdentityClients drops the cached impersonating clients; their grants
+// are removed with the RBAC fixtures at the end of each spec, so a cached
+// client would silently lose its permissions if it leaked into the next one.
+func (f *Framework) resetIdentityClients() {
+	f.identityClientsLock.Lock()
+	defer f.identityClientsLock.Unlock()
+	f.identityClients = nil
+}
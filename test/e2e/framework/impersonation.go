@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ClientConfigForUser returns a copy of the framework's client config that
+// impersonates the given user and groups. The suite's own identity must be
+// allowed to impersonate, which holds for the admin credentials e2e runs use.
+func (f *Framework) ClientConfigForUser(user string, groups []string) *rest.Config {
+	config := f.ClientConfig()
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: user,
+		Groups:   groups,
+	}
+	return config
+}
+
+// ClientSetForUser returns a clientset whose requests are issued as the given
+// user and groups, so specs can assert what unprivileged subjects are denied
+// rather than only what admin is allowed.
+func (f *Framework) ClientSetForUser(user string, groups []string) (clientset.Interface, error) {
+	return clientset.NewForConfig(f.ClientConfigForUser(user, groups))
+}
+
+// DynamicClientForUser is ClientSetForUser for the dynamic client.
+func (f *Framework) DynamicClientForUser(user string, groups []string) (dynamic.Interface, error) {
+	return dynamic.NewForConfig(f.ClientConfigForUser(user, groups))
+}
+
+// DiscoveryClientForUser is ClientSetForUser for the discovery client.
+func (f *Framework) DiscoveryClientForUser(user string, groups []string) (*discovery.DiscoveryClient, error) {
+	return discovery.NewDiscoveryClientForConfig(f.ClientConfigForUser(user, groups))
+}
+
+// GrantClusterRoleToUser creates a ClusterRoleBinding granting the named
+// cluster role to the given (typically impersonated) user. Unlike
+// namespace-scoped bindings, which vanish with the test namespace, the
+// binding is cluster-scoped, so its removal is registered with the
+// framework's AfterEach.
+func (f *Framework) GrantClusterRoleToUser(clusterRole, user string) error {
+	name := fmt.Sprintf("%s--%s-%s", f.UniqueName, clusterRole, RandomSuffix())
+	_, err := f.ClientSet.RbacV1().ClusterRoleBindings().Create(context.TODO(), &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole,
+		},
+		Subjects: []rbacv1.Subject{
+			{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: user},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("binding clusterrole/%s to user %q: %w", clusterRole, user, err)
+	}
+	f.rbacFixturesLock.Lock()
+	f.rbacFixtures = append(f.rbacFixtures, name)
+	f.rbacFixturesLock.Unlock()
+	return nil
+}
+
+// cleanupRBACFixtures deletes the cluster-scoped RBAC objects created for
+// impersonated users during the spec.
+func (f *Framework) cleanupRBACFixtures() {
+	f.resetIdentityClients()
+	f.rbacFixturesLock.Lock()
+	fixtures := f.rbacFixtures
+	f.rbacFixtures = nil
+	f.rbacFixturesLock.Unlock()
+	for _, name := range fixtures {
+		if err := f.ClientSet.RbacV1().ClusterRoleBindings().Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+			Logf("Failed to delete cluster role binding %s: %v", name, err)
+		}
+	}
+}
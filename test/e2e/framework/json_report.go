@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// The enriched JSON report pairs every ginkgo spec result with the framework
+// data collected while the spec ran, so tooling that consumes the serial
+// namespace lane gets namespaces, timings and API error counts as structured
+// fields instead of parsing them out of stdout. It is written alongside the
+// JUnit report and selected by --json-report.
+
+// SpecEnrichment is the framework-side data attached to one spec result.
+type SpecEnrichment struct {
+	// Timing is the spec's framework wait and provisioning summary; see
+	// SpecTimingSummary.
+	Timing *SpecTimingSummary `json:"timing,omitempty"`
+	// NamespaceTimings holds the lifecycle durations of the namespaces the
+	// framework managed for the spec.
+	NamespaceTimings []NamespaceTiming `json:"namespaceTimings,omitempty"`
+	// RetriesByCategory counts retries performed by framework helpers,
+	// grouped by cause.
+	RetriesByCategory map[string]int `json:"retriesByCategory,omitempty"`
+	// APIErrors aggregates the non-2xx API responses the framework clients
+	// saw during the spec.
+	APIErrors []APIErrorRecord `json:"apiErrors,omitempty"`
+}
+
+// EnrichedSpecReport is one spec in the enriched JSON report.
+type EnrichedSpecReport struct {
+	Name           string  `json:"name"`
+	State          string  `json:"state"`
+	RunTimeSeconds float64 `json:"runTimeSeconds"`
+	FailureMessage string  `json:"failureMessage,omitempty"`
+	// Framework is absent for specs that never touched the framework.
+	Framework *SpecEnrichment `json:"framework,omitempty"`
+}
+
+// EnrichedJSONReport is the file layout of the --json-report output.
+type EnrichedJSONReport struct {
+	SuiteDescription string               `json:"suiteDescription"`
+	SuiteSucceeded   bool                 `json:"suiteSucceeded"`
+	RunTimeSeconds   float64              `json:"runTimeSeconds"`
+	Specs            []EnrichedSpecReport `json:"specs"`
+}
+
+var (
+	specEnrichmentsLock sync.Mutex
+	// specEnrichments retains per-spec framework data until the suite report
+	// is written, keyed by the spec's full text.
+	specEnrichments map[string]*SpecEnrichment
+)
+
+// enrichSpec merges framework data into the retained enrichment for the
+// named spec. It only collects when --json-report asked for the output.
+func enrichSpec(spec string, mutate func(*SpecEnrichment)) {
+	if TestContext.JSONReport == "" || spec == "" {
+		return
+	}
+	specEnrichmentsLock.Lock()
+	defer specEnrichmentsLock.Unlock()
+	if specEnrichments == nil {
+		specEnrichments = map[string]*SpecEnrichment{}
+	}
+	enrichment, ok := specEnrichments[spec]
+	if !ok {
+		enrichment = &SpecEnrichment{}
+		specEnrichments[spec] = enrichment
+	}
+	mutate(enrichment)
+}
+
+// recordSpecEnrichment captures everything the framework instance gathered
+// for the spec that just finished; it runs in AfterEach before the per-spec
+// collectors are reset.
+func (f *Framework) recordSpecEnrichment() {
+	enrichSpec(ginkgo.CurrentSpecReport().FullText(), func(enrichment *SpecEnrichment) {
+		if timings := f.NamespaceTimings(); len(timings) > 0 {
+			enrichment.NamespaceTimings = append(enrichment.NamespaceTimings, timings...)
+		}
+		if f.retryReport != nil {
+			f.retryReport.lock.RLock()
+			for _, retry := range f.retryReport.Retries {
+				if enrichment.RetriesByCategory == nil {
+					enrichment.RetriesByCategory = map[string]int{}
+				}
+				enrichment.RetriesByCategory[retry.Category]++
+			}
+			f.retryReport.lock.RUnlock()
+		}
+		if f.apiErrorSummary != nil {
+			enrichment.APIErrors = append(enrichment.APIErrors, f.apiErrorSummary.Records()...)
+		}
+	})
+}
+
+// WriteEnrichedJSONReport writes the ginkgo suite report merged with the
+// retained framework enrichments to the --json-report path. It is meant to be
+// called from a ginkgo ReportAfterSuite node and is a no-op without the flag.
+func WriteEnrichedJSONReport(report ginkgo.Report) {
+	if TestContext.JSONReport == "" {
+		return
+	}
+	path := TestContext.JSONReport
+	if !filepath.IsAbs(path) && TestContext.ReportDir != "" {
+		path = filepath.Join(TestContext.ReportDir, path)
+	}
+
+	specEnrichmentsLock.Lock()
+	enrichments := specEnrichments
+	specEnrichments = nil
+	specEnrichmentsLock.Unlock()
+
+	enriched := EnrichedJSONReport{
+		SuiteDescription: report.SuiteDescription,
+		SuiteSucceeded:   report.SuiteSucceeded,
+		RunTimeSeconds:   report.RunTime.Seconds(),
+	}
+	for _, spec := range report.SpecReports {
+		if spec.LeafNodeType != types.NodeTypeIt {
+			continue
+		}
+		entry := EnrichedSpecReport{
+			Name:           spec.FullText(),
+			State:          spec.State.String(),
+			RunTimeSeconds: spec.RunTime.Seconds(),
+			FailureMessage: spec.Failure.Message,
+			Framework:      enrichments[spec.FullText()],
+		}
+		enriched.Specs = append(enriched.Specs, entry)
+	}
+
+	data, err := json.MarshalIndent(enriched, "", "  ")
+	if err != nil {
+		Logf("Failed to encode the enriched JSON report: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		Logf("Failed to write the enriched JSON report to %q: %v", path, err)
+		return
+	}
+	Logf("Enriched JSON report written to %s", path)
+}
@@ -34,7 +34,9 @@ func nowStamp() string {
 }
 
 func log(level string, format string, args ...interface{}) {
-	fmt.Fprintf(ginkgo.GinkgoWriter, nowStamp()+": "+level+": "+format+"\n", args...)
+	msg := fmt.Sprintf(format, args...)
+	fmt.Fprintf(ginkgo.GinkgoWriter, "%s: %s: %s\n", nowStamp(), level, msg)
+	emitLogRecord(level, msg, nil)
 }
 
 // Logf logs the info.
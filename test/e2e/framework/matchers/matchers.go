@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package matchers provides gomega matchers for common Kubernetes object
+// state: phases, conditions, labels and deletion. They exist so specs can
+// write eventually-style assertions whose failures print the relevant part
+// of the object, instead of comparing raw fields and reporting only two bare
+// values.
+package matchers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BeInPhase succeeds when the object's Status.Phase equals the given phase.
+// The phase argument may be any string-kinded type, e.g.
+// v1.NamespaceTerminating.
+func BeInPhase(phase interface{}) types.GomegaMatcher {
+	return &phaseMatcher{expected: fmt.Sprintf("%v", phase)}
+}
+
+type phaseMatcher struct {
+	expected string
+	actual   string
+}
+
+func (m *phaseMatcher) Match(actual interface{}) (bool, error) {
+	phase, err := statusField(actual, "Phase")
+	if err != nil {
+		return false, err
+	}
+	m.actual = fmt.Sprintf("%v", phase)
+	return m.actual == m.expected, nil
+}
+
+func (m *phaseMatcher) FailureMessage(actual interface{}) string {
+	return format.Message(actual, fmt.Sprintf("to be in phase %q, but its phase is %q", m.expected, m.actual))
+}
+
+func (m *phaseMatcher) NegatedFailureMessage(actual interface{}) string {
+	return format.Message(actual, fmt.Sprintf("not to be in phase %q", m.expected))
+}
+
+// HaveCondition succeeds when the object's Status.Conditions contains a
+// condition of the given type with the given status. Both arguments may be
+// any string-kinded type, e.g. v1.NamespaceDeletionContentFailure and
+// v1.ConditionTrue.
+func HaveCondition(conditionType, status interface{}) types.GomegaMatcher {
+	return &conditionMatcher{
+		expectedType:   fmt.Sprintf("%v", conditionType),
+		expectedStatus: fmt.Sprintf("%v", status),
+	}
+}
+
+type conditionMatcher struct {
+	expectedType   string
+	expectedStatus string
+	found          string
+}
+
+func (m *conditionMatcher) Match(actual interface{}) (bool, error) {
+	conditions, err := statusField(actual, "Conditions")
+	if err != nil {
+		return false, err
+	}
+	if conditions.Kind() != reflect.Slice {
+		return false, fmt.Errorf("Status.Conditions of %T is not a slice", actual)
+	}
+	m.found = "<absent>"
+	for i := 0; i < conditions.Len(); i++ {
+		condition := conditions.Index(i)
+		conditionType := condition.FieldByName("Type")
+		conditionStatus := condition.FieldByName("Status")
+		if !conditionType.IsValid() || !conditionStatus.IsValid() {
+			return false, fmt.Errorf("conditions of %T have no Type/Status fields", actual)
+		}
+		if fmt.Sprintf("%v", conditionType) == m.expectedType {
+			m.found = fmt.Sprintf("%v", conditionStatus)
+			return m.found == m.expectedStatus, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *conditionMatcher) FailureMessage(actual interface{}) string {
+	return format.Message(actual, fmt.Sprintf("to have condition %q with status %q, but that condition is %s", m.expectedType, m.expectedStatus, m.found))
+}
+
+func (m *conditionMatcher) NegatedFailureMessage(actual interface{}) string {
+	return format.Message(actual, fmt.Sprintf("not to have condition %q with status %q", m.expectedType, m.expectedStatus))
+}
+
+// HaveLabel succeeds when the object carries the given label key with the
+// given value. The actual value must implement metav1.Object.
+func HaveLabel(key, value string) types.GomegaMatcher {
+	return &labelMatcher{key: key, value: value}
+}
+
+type labelMatcher struct {
+	key    string
+	value  string
+	actual map[string]string
+}
+
+func (m *labelMatcher) Match(actual interface{}) (bool, error) {
+	obj, ok := actual.(metav1.Object)
+	if !ok {
+		return false, fmt.Errorf("HaveLabel expects a metav1.Object, got %T", actual)
+	}
+	m.actual = obj.GetLabels()
+	got, present := m.actual[m.key]
+	return present && got == m.value, nil
+}
+
+func (m *labelMatcher) FailureMessage(actual interface{}) string {
+	return format.Message(actual, fmt.Sprintf("to have label %q=%q, but its labels are %v", m.key, m.value, m.actual))
+}
+
+func (m *labelMatcher) NegatedFailureMessage(actual interface{}) string {
+	return format.Message(actual, fmt.Sprintf("not to have label %q=%q", m.key, m.value))
+}
+
+// BeDeleted succeeds when the actual value shows the object is gone: either
+// the NotFound error returned by a Get, or a nil object. Use with the error
+// from a Get in an Eventually:
+//
+//	gomega.Eventually(func() error {
+//		_, err := c.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+//		return err
+//	}).Should(e2ematchers.BeDeleted())
+func BeDeleted() types.GomegaMatcher {
+	return &deletedMatcher{}
+}
+
+type deletedMatcher struct{}
+
+func (m *deletedMatcher) Match(actual interface{}) (bool, error) {
+	if actual == nil {
+		return true, nil
+	}
+	if err, ok := actual.(error); ok {
+		return apierrors.IsNotFound(err), nil
+	}
+	value := reflect.ValueOf(actual)
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return true, nil
+	}
+	if _, ok := actual.(metav1.Object); ok {
+		return false, nil
+	}
+	return false, fmt.Errorf("BeDeleted expects an error or an object, got %T", actual)
+}
+
+func (m *deletedMatcher) FailureMessage(actual interface{}) string {
+	if err, ok := actual.(error); ok {
+		return format.Message(actual, fmt.Sprintf("to be a NotFound error proving deletion, but got: %v", err))
+	}
+	return format.Message(actual, "to be deleted, but the object still exists")
+}
+
+func (m *deletedMatcher) NegatedFailureMessage(actual interface{}) string {
+	return format.Message(actual, "not to be deleted")
+}
+
+// statusField digs Status.<name> out of a typed object via reflection, so the
+// matchers work across every API type that follows the convention.
+func statusField(actual interface{}, name string) (reflect.Value, error) {
+	value := reflect.ValueOf(actual)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}, fmt.Errorf("expected an object, got a nil %T", actual)
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("expected an API object, got %T", actual)
+	}
+	status := value.FieldByName("Status")
+	if !status.IsValid() {
+		return reflect.Value{}, fmt.Errorf("%T has no Status field", actual)
+	}
+	field := status.FieldByName(name)
+	if !field.IsValid() {
+		return reflect.Value{}, fmt.Errorf("%T has no Status.%s field", actual, name)
+	}
+	return field, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package matchers
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestBeInPhase(t *testing.T) {
+	ns := &v1.Namespace{Status: v1.NamespaceStatus{Phase: v1.NamespaceTerminating}}
+	for _, tc := range []struct {
+		phase interface{}
+		want  bool
+	}{
+		{v1.NamespaceTerminating, true},
+		{v1.NamespaceActive, false},
+		{"Terminating", true},
+	} {
+		matched, err := BeInPhase(tc.phase).Match(ns)
+		if err != nil {
+			t.Fatalf("BeInPhase(%v): unexpected error: %v", tc.phase, err)
+		}
+		if matched != tc.want {
+			t.Errorf("BeInPhase(%v) = %v, want %v", tc.phase, matched, tc.want)
+		}
+	}
+	if _, err := BeInPhase(v1.NamespaceActive).Match(&v1.ConfigMap{}); err == nil {
+		t.Error("BeInPhase against an object without Status.Phase should error")
+	}
+}
+
+func TestHaveCondition(t *testing.T) {
+	ns := &v1.Namespace{Status: v1.NamespaceStatus{Conditions: []v1.NamespaceCondition{
+		{Type: v1.NamespaceDeletionContentFailure, Status: v1.ConditionTrue},
+	}}}
+	for _, tc := range []struct {
+		condType interface{}
+		status   interface{}
+		want     bool
+	}{
+		{v1.NamespaceDeletionContentFailure, v1.ConditionTrue, true},
+		{v1.NamespaceDeletionContentFailure, v1.ConditionFalse, false},
+		{v1.NamespaceDeletionDiscoveryFailure, v1.ConditionTrue, false},
+	} {
+		matched, err := HaveCondition(tc.condType, tc.status).Match(ns)
+		if err != nil {
+			t.Fatalf("HaveCondition(%v, %v): unexpected error: %v", tc.condType, tc.status, err)
+		}
+		if matched != tc.want {
+			t.Errorf("HaveCondition(%v, %v) = %v, want %v", tc.condType, tc.status, matched, tc.want)
+		}
+	}
+}
+
+func TestHaveLabel(t *testing.T) {
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "e2e"}}}
+	if matched, err := HaveLabel("team", "e2e").Match(ns); err != nil || !matched {
+		t.Errorf("HaveLabel on a present label = (%v, %v), want match", matched, err)
+	}
+	if matched, err := HaveLabel("team", "other").Match(ns); err != nil || matched {
+		t.Errorf("HaveLabel on a mismatched value = (%v, %v), want no match", matched, err)
+	}
+	if _, err := HaveLabel("team", "e2e").Match("not an object"); err == nil {
+		t.Error("HaveLabel against a non-object should error")
+	}
+}
+
+func TestBeDeleted(t *testing.T) {
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "gone")
+	if matched, err := BeDeleted().Match(notFound); err != nil || !matched {
+		t.Errorf("BeDeleted on a NotFound error = (%v, %v), want match", matched, err)
+	}
+	if matched, err := BeDeleted().Match(apierrors.NewConflict(schema.GroupResource{}, "x", nil)); err != nil || matched {
+		t.Errorf("BeDeleted on a conflict error = (%v, %v), want no match", matched, err)
+	}
+	if matched, err := BeDeleted().Match(&v1.Namespace{}); err != nil || matched {
+		t.Errorf("BeDeleted on a live object = (%v, %v), want no match", matched, err)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"sort"
+
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ClusterScopedDeleteCollectionGuard asserts that no DELETECOLLECTION call
+// hit a cluster-scoped resource between its start and Verify. Namespace
+// teardown must only issue namespaced delete calls; a deleteCollection that
+// escapes to cluster scope can wipe objects belonging to every tenant, which
+// is a class of bug this guard exists to catch again. The apiserver's
+// request counters make the check cheap and client-independent.
+type ClusterScopedDeleteCollectionGuard struct {
+	grabber *Grabber
+	before  map[string]float64
+}
+
+// StartClusterScopedDeleteCollectionGuard snapshots the apiserver's
+// cluster-scoped DELETECOLLECTION counters. It fails on clusters where the
+// apiserver metrics cannot be scraped; callers should then log and skip the
+// guard rather than the test.
+func StartClusterScopedDeleteCollectionGuard(c clientset.Interface, config *rest.Config) (*ClusterScopedDeleteCollectionGuard, error) {
+	grabber, err := NewMetricsGrabber(c, nil, config, false, false, false, true, false, false)
+	if err != nil {
+		return nil, err
+	}
+	before, err := grabClusterScopedDeleteCollections(grabber)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterScopedDeleteCollectionGuard{grabber: grabber, before: before}, nil
+}
+
+// Verify re-grabs the counters and returns an error naming every
+// cluster-scoped resource that received a DELETECOLLECTION since the guard
+// started. It can be called repeatedly; the baseline stays fixed.
+func (g *ClusterScopedDeleteCollectionGuard) Verify() error {
+	after, err := grabClusterScopedDeleteCollections(g.grabber)
+	if err != nil {
+		return fmt.Errorf("failed to re-grab apiserver metrics: %v", err)
+	}
+	var violations []string
+	for resource, count := range after {
+		if delta := count - g.before[resource]; delta > 0 {
+			violations = append(violations, fmt.Sprintf("%s (%d calls)", resource, int(delta)))
+		}
+	}
+	if len(violations) > 0 {
+		sort.Strings(violations)
+		return fmt.Errorf("cluster-scoped resources received deleteCollection calls: %v", violations)
+	}
+	return nil
+}
+
+// grabClusterScopedDeleteCollections sums the apiserver request counters for
+// DELETECOLLECTION calls with cluster scope, keyed by resource.
+func grabClusterScopedDeleteCollections(g *Grabber) (map[string]float64, error) {
+	metrics, err := g.GrabFromAPIServer()
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]float64{}
+	for _, sample := range metrics["apiserver_request_total"] {
+		if string(sample.Metric["verb"]) != "DELETECOLLECTION" {
+			continue
+		}
+		if string(sample.Metric["scope"]) != "cluster" {
+			continue
+		}
+		counts[string(sample.Metric["resource"])] += float64(sample.Value)
+	}
+	return counts, nil
+}
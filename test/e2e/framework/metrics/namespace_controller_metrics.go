@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// namespaceWorkqueueName is the name label the namespace controller registers
+// its workqueue metrics under.
+const namespaceWorkqueueName = "namespace"
+
+// NamespaceControllerMetrics summarizes the kube-controller-manager workqueue
+// metrics describing namespace controller health. The controller exposes no
+// dedicated deletion latency metric, so the cumulative work duration divided
+// by the processed item count is the closest available proxy for how long
+// namespace deletions are taking.
+type NamespaceControllerMetrics struct {
+	// QueueDepth is the number of namespaces waiting to be processed.
+	QueueDepth float64
+	// Adds is the cumulative number of items enqueued.
+	Adds float64
+	// Retries is the cumulative number of items requeued after a failure.
+	Retries float64
+	// WorkCount is the cumulative number of items processed.
+	WorkCount float64
+	// WorkDurationSeconds is the cumulative time spent processing items.
+	WorkDurationSeconds float64
+	// UnfinishedWorkSeconds is the accumulated in-flight processing time.
+	UnfinishedWorkSeconds float64
+	// LongestRunningProcessorSeconds is how long the oldest in-flight item
+	// has been processed for.
+	LongestRunningProcessorSeconds float64
+}
+
+// AverageWorkDurationSeconds returns the mean per-item processing time, or
+// zero when nothing has been processed yet.
+func (m NamespaceControllerMetrics) AverageWorkDurationSeconds() float64 {
+	if m.WorkCount == 0 {
+		return 0
+	}
+	return m.WorkDurationSeconds / m.WorkCount
+}
+
+// String renders the metrics in a single human-readable line suitable for
+// attaching to test artifacts.
+func (m NamespaceControllerMetrics) String() string {
+	return fmt.Sprintf("queue depth %.0f, adds %.0f, retries %.0f, processed %.0f, avg work duration %.3fs, unfinished work %.1fs, longest running processor %.1fs",
+		m.QueueDepth, m.Adds, m.Retries, m.WorkCount, m.AverageWorkDurationSeconds(), m.UnfinishedWorkSeconds, m.LongestRunningProcessorSeconds)
+}
+
+// GrabNamespaceControllerMetrics scrapes the controller manager and distills
+// the namespace controller's workqueue metrics. It fails when the workqueue
+// series are absent, which usually means the metrics grabber reached a
+// controller manager that has not started the namespace controller.
+func (g *Grabber) GrabNamespaceControllerMetrics() (NamespaceControllerMetrics, error) {
+	metrics, err := g.GrabFromControllerManager()
+	if err != nil {
+		return NamespaceControllerMetrics{}, err
+	}
+	result := NamespaceControllerMetrics{}
+	found := false
+	targets := map[string]*float64{
+		"workqueue_depth":                             &result.QueueDepth,
+		"workqueue_adds_total":                        &result.Adds,
+		"workqueue_retries_total":                     &result.Retries,
+		"workqueue_work_duration_seconds_count":       &result.WorkCount,
+		"workqueue_work_duration_seconds_sum":         &result.WorkDurationSeconds,
+		"workqueue_unfinished_work_seconds":           &result.UnfinishedWorkSeconds,
+		"workqueue_longest_running_processor_seconds": &result.LongestRunningProcessorSeconds,
+	}
+	for metricName, target := range targets {
+		for _, sample := range metrics[metricName] {
+			if string(sample.Metric["name"]) == namespaceWorkqueueName {
+				*target = float64(sample.Value)
+				found = true
+			}
+		}
+	}
+	if !found {
+		return NamespaceControllerMetrics{}, fmt.Errorf("no workqueue metrics for the namespace controller found in controller manager output")
+	}
+	return result, nil
+}
+
+// GrabNamespaceControllerMetrics is a convenience wrapper that builds a
+// grabber limited to the controller manager and scrapes the namespace
+// controller's workqueue metrics from it.
+func GrabNamespaceControllerMetrics(c clientset.Interface, config *rest.Config) (NamespaceControllerMetrics, error) {
+	grabber, err := NewMetricsGrabber(c, nil, config, false, false, true, false, false, false)
+	if err != nil {
+		return NamespaceControllerMetrics{}, err
+	}
+	return grabber.GrabNamespaceControllerMetrics()
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"sort"
+
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// StorageLeakCheck compares the apiserver's per-resource storage object-count
+// gauges before and after a test. LIST-based emptiness checks only see what
+// the apiserver serves; the storage gauges count what etcd actually holds, so
+// this is the check that catches keys orphaned below the API (e.g. by an
+// interrupted deletion) even though every LIST comes back empty.
+type StorageLeakCheck struct {
+	grabber *Grabber
+	before  map[string]float64
+}
+
+// storageLeakExemptResources grow and shrink with background cluster activity
+// regardless of what the test under measurement does, so their deltas carry
+// no leak signal.
+var storageLeakExemptResources = map[string]bool{
+	"events":                     true,
+	"leases.coordination.k8s.io": true,
+}
+
+// StartStorageLeakCheck snapshots the apiserver's storage object counts. It
+// fails on clusters where the apiserver metrics cannot be scraped; callers
+// should then log and skip the check rather than the test.
+func StartStorageLeakCheck(c clientset.Interface, config *rest.Config) (*StorageLeakCheck, error) {
+	grabber, err := NewMetricsGrabber(c, nil, config, false, false, false, true, false, false)
+	if err != nil {
+		return nil, err
+	}
+	before, err := grabStorageObjectCounts(grabber)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageLeakCheck{grabber: grabber, before: before}, nil
+}
+
+// Verify re-grabs the gauges and returns an error naming every resource whose
+// stored object count grew beyond its allowance since the check started.
+// allowedGrowth maps resource label values (e.g. "namespaces",
+// "leases.coordination.k8s.io") to the growth the caller can explain; absent
+// resources get an allowance of zero. It can be called repeatedly; the
+// baseline stays fixed.
+func (s *StorageLeakCheck) Verify(allowedGrowth map[string]float64) error {
+	after, err := grabStorageObjectCounts(s.grabber)
+	if err != nil {
+		return fmt.Errorf("failed to re-grab apiserver metrics: %v", err)
+	}
+	var leaks []string
+	for resource, count := range after {
+		if storageLeakExemptResources[resource] {
+			continue
+		}
+		before, known := s.before[resource]
+		if !known {
+			// A resource that first appeared during the test (e.g. a CRD it
+			// installed) starts from zero.
+			before = 0
+		}
+		if delta := count - before; delta > allowedGrowth[resource] {
+			leaks = append(leaks, fmt.Sprintf("%s (+%d stored objects, allowance %d)",
+				resource, int(delta), int(allowedGrowth[resource])))
+		}
+	}
+	if len(leaks) > 0 {
+		sort.Strings(leaks)
+		return fmt.Errorf("apiserver storage object counts grew beyond their allowance: %v", leaks)
+	}
+	return nil
+}
+
+// grabStorageObjectCounts reads the storage object-count gauge, keyed by the
+// resource label. The apiserver publishes -1 for resources whose count is
+// temporarily unavailable; those samples are dropped rather than treated as
+// empty.
+func grabStorageObjectCounts(g *Grabber) (map[string]float64, error) {
+	metrics, err := g.GrabFromAPIServer()
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]float64{}
+	for _, sample := range metrics["apiserver_storage_objects"] {
+		if sample.Value < 0 {
+			continue
+		}
+		counts[string(sample.Metric["resource"])] = float64(sample.Value)
+	}
+	return counts, nil
+}
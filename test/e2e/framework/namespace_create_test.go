@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// withNamespaceCreateTestContext shrinks the wait windows via the timeout
+// scale so that failure paths finish in test time, and restores the global
+// test context afterwards. Tests that need the creation poll loop to get
+// more than its immediate attempt must leave the scaled window wider than
+// the fixed Poll interval.
+func withNamespaceCreateTestContext(t *testing.T, verifyServiceAccount bool, timeoutScale float64) {
+	t.Helper()
+	oldVerify, oldScale := TestContext.VerifyServiceAccount, TestContext.TimeoutScale
+	TestContext.VerifyServiceAccount = verifyServiceAccount
+	TestContext.TimeoutScale = timeoutScale
+	t.Cleanup(func() {
+		TestContext.VerifyServiceAccount = oldVerify
+		TestContext.TimeoutScale = oldScale
+	})
+}
+
+func TestCreateTestingNSRegistersBeforeWait(t *testing.T) {
+	withNamespaceCreateTestContext(t, true, 0.005)
+	// The fake cluster never provisions the default service account, so the
+	// initialization wait must fail after the namespace exists.
+	client := fake.NewSimpleClientset()
+
+	var registeredName string
+	ns, err := createTestingNS(context.Background(), "halfway", client, nil, false, func(ns *v1.Namespace) {
+		registeredName = ns.Name
+	})
+	if err == nil {
+		t.Fatalf("expected the initialization wait to fail, got none")
+	}
+	if ns == nil {
+		t.Fatalf("the created namespace must be returned alongside the wait error")
+	}
+	if registeredName != ns.Name {
+		t.Errorf("onCreated saw namespace %q, want %q registered before the wait", registeredName, ns.Name)
+	}
+	if _, err := client.CoreV1().Namespaces().Get(context.Background(), ns.Name, metav1.GetOptions{}); err != nil {
+		t.Errorf("the half-created namespace is gone from the cluster: %v", err)
+	}
+}
+
+func TestCreateTestingNSSucceedsWithoutVerification(t *testing.T) {
+	withNamespaceCreateTestContext(t, false, 0.005)
+	client := fake.NewSimpleClientset()
+
+	called := 0
+	ns, err := createTestingNS(context.Background(), "plain", client, map[string]string{"team": "e2e"}, false, func(*v1.Namespace) {
+		called++
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called != 1 {
+		t.Errorf("onCreated ran %d times, want once", called)
+	}
+	if ns.Labels["team"] != "e2e" || ns.Labels["e2e-run"] == "" {
+		t.Errorf("created namespace carries labels %v, want the caller's labels plus e2e-run", ns.Labels)
+	}
+}
+
+func TestCreateTestingNSRetriesNameConflicts(t *testing.T) {
+	// Retries are spaced by the fixed Poll interval, so this window must
+	// stay wide enough for a second and third attempt.
+	withNamespaceCreateTestContext(t, false, 0.2)
+	client := fake.NewSimpleClientset()
+
+	conflicts := 2
+	client.PrependReactor("create", "namespaces", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if conflicts > 0 {
+			conflicts--
+			return true, nil, apierrors.NewAlreadyExists(schema.GroupResource{Resource: "namespaces"}, "taken")
+		}
+		return false, nil, nil
+	})
+
+	ns, err := createTestingNS(context.Background(), "conflict", client, nil, false, nil)
+	if err != nil {
+		t.Fatalf("creation did not survive transient name conflicts: %v", err)
+	}
+	if ns == nil || ns.Name == "" {
+		t.Fatalf("no namespace came back after the conflicts cleared")
+	}
+}
+
+func TestCreateTestingNSReturnsTypedError(t *testing.T) {
+	withNamespaceCreateTestContext(t, false, 0.005)
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "namespaces", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewInternalError(context.DeadlineExceeded)
+	})
+
+	_, err := createTestingNS(context.Background(), "doomed", client, nil, false, nil)
+	creationErr, ok := err.(*NamespaceCreationError)
+	if !ok {
+		t.Fatalf("got %T (%v), want *NamespaceCreationError", err, err)
+	}
+	if creationErr.Attempts == 0 || creationErr.Err == nil {
+		t.Errorf("typed error is missing attempt accounting: %+v", creationErr)
+	}
+}
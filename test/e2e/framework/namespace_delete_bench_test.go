@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func namespaceFixtures(count int) []runtime.Object {
+	objs := make([]runtime.Object, 0, count)
+	for i := 0; i < count; i++ {
+		objs = append(objs, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("bench-ns-%06d", i),
+			Labels: map[string]string{"bench": "true"},
+		}})
+	}
+	return objs
+}
+
+func TestDeleteNamespacesStreamingFilters(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "keep-me"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "drop-me-1"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "drop-me-2"}},
+	)
+	deleted, err := deleteNamespacesStreaming(context.Background(), client, metav1.DeleteOptions{}, metav1.ListOptions{},
+		func(name string) bool { return strings.HasPrefix(name, "drop-me") })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deleted) != 2 {
+		t.Fatalf("deleted %v, want the two drop-me namespaces", deleted)
+	}
+	if _, err := client.CoreV1().Namespaces().Get(context.Background(), "keep-me", metav1.GetOptions{}); err != nil {
+		t.Fatalf("the skipped namespace was deleted: %v", err)
+	}
+}
+
+func TestDeleteNamespacesBySelectorProtection(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", Labels: map[string]string{"run": "x"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "precious", Labels: map[string]string{"run": "x"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "scratch", Labels: map[string]string{"run": "x"}}},
+	)
+	deleted, err := deleteNamespacesBySelector(context.Background(), client, NamespaceSelection{
+		LabelSelector: "run=x",
+		Protected:     []string{"precious"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "scratch" {
+		t.Fatalf("deleted %v, want only the scratch namespace", deleted)
+	}
+	for _, name := range []string{"kube-system", "precious"} {
+		if _, err := client.CoreV1().Namespaces().Get(context.Background(), name, metav1.GetOptions{}); err != nil {
+			t.Errorf("protected namespace %s was deleted: %v", name, err)
+		}
+	}
+}
+
+// The benchmarks exercise the streaming path over many namespaces. The fake
+// clientset does not paginate, so they cannot show the per-page memory bound
+// against a real apiserver, but allocs/op still catches regressions that
+// re-grow state proportional to cluster size (for example re-accumulating
+// full objects instead of names).
+func benchmarkDeleteNamespacesStreaming(b *testing.B, count int) {
+	fixtures := namespaceFixtures(count)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		client := fake.NewSimpleClientset(fixtures...)
+		b.StartTimer()
+		deleted, err := deleteNamespacesStreaming(context.Background(), client, metav1.DeleteOptions{}, metav1.ListOptions{}, nil)
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+		if len(deleted) != count {
+			b.Fatalf("deleted %d namespaces, want %d", len(deleted), count)
+		}
+	}
+}
+
+func BenchmarkDeleteNamespacesStreaming1000(b *testing.B) {
+	benchmarkDeleteNamespacesStreaming(b, 1000)
+}
+
+func BenchmarkDeleteNamespacesStreaming10000(b *testing.B) {
+	benchmarkDeleteNamespacesStreaming(b, 10000)
+}
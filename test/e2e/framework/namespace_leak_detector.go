@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// NamespaceContentInventory maps a group/version/resource string to the names
+// of objects of that resource still present in a namespace. Objects carrying
+// finalizers are annotated with them, since those are what typically block
+// namespace termination.
+type NamespaceContentInventory map[string][]string
+
+// InventoryNamespaceContent walks every listable namespaced resource known to
+// discovery and returns what is left in the given namespace. Partial
+// discovery failures (e.g. an unavailable aggregated API) are tolerated so
+// that the inventory names the resources that could be inspected.
+func InventoryNamespaceContent(c clientset.Interface, dc dynamic.Interface, namespace string) (NamespaceContentInventory, error) {
+	resourceLists, err := c.Discovery().ServerPreferredNamespacedResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("failed to discover namespaced resources: %v", err)
+	}
+	inventory := NamespaceContentInventory{}
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || !listableResource(resource) {
+				continue
+			}
+			gvr := gv.WithResource(resource.Name)
+			objs, err := dc.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				// Forbidden or unavailable resources cannot block deletion
+				// visibly; skip them rather than failing the whole inventory.
+				continue
+			}
+			for _, item := range objs.Items {
+				entry := item.GetName()
+				if finalizers := item.GetFinalizers(); len(finalizers) > 0 {
+					entry = fmt.Sprintf("%s (finalizers: %s)", entry, strings.Join(finalizers, ", "))
+				}
+				inventory[gvr.String()] = append(inventory[gvr.String()], entry)
+			}
+		}
+	}
+	return inventory, nil
+}
+
+func listableResource(resource metav1.APIResource) bool {
+	for _, verb := range resource.Verbs {
+		if verb == "list" {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the inventory with one resource per line, sorted for stable
+// output.
+func (inventory NamespaceContentInventory) String() string {
+	if len(inventory) == 0 {
+		return "no namespaced objects remaining"
+	}
+	gvrs := make([]string, 0, len(inventory))
+	for gvr := range inventory {
+		gvrs = append(gvrs, gvr)
+	}
+	sort.Strings(gvrs)
+	var b strings.Builder
+	for _, gvr := range gvrs {
+		fmt.Fprintf(&b, "%s: %s\n", gvr, strings.Join(inventory[gvr], ", "))
+	}
+	return b.String()
+}
+
+// isAutoProvisionedNamespaceObject reports whether the named object is one
+// the control plane creates in every namespace (or background noise like
+// events), and therefore not evidence of leaked test content.
+func isAutoProvisionedNamespaceObject(gvr, name string) bool {
+	switch gvr {
+	case "/v1, Resource=serviceaccounts":
+		return name == "default"
+	case "/v1, Resource=configmaps":
+		return name == "kube-root-ca.crt"
+	case "/v1, Resource=secrets":
+		// Legacy service account token secrets, where still generated.
+		return strings.HasPrefix(name, "default-token-")
+	case "/v1, Resource=events", "events.k8s.io/v1, Resource=events":
+		return true
+	}
+	return false
+}
+
+// VerifyNamespaceEmpty walks every namespaced, listable resource via
+// discovery and returns an error naming anything left in the namespace
+// beyond the objects the control plane provisions automatically. Unlike the
+// per-resource spot checks the deletion specs started with, this catches
+// leaks of Secrets, ConfigMaps, EndpointSlices, custom resources and so on.
+func VerifyNamespaceEmpty(c clientset.Interface, dc dynamic.Interface, namespace string) error {
+	inventory, err := InventoryNamespaceContent(c, dc, namespace)
+	if err != nil {
+		return err
+	}
+	leaks := NamespaceContentInventory{}
+	for gvr, names := range inventory {
+		for _, name := range names {
+			if isAutoProvisionedNamespaceObject(gvr, name) {
+				continue
+			}
+			leaks[gvr] = append(leaks[gvr], name)
+		}
+	}
+	if len(leaks) > 0 {
+		return fmt.Errorf("namespace %s is not empty:\n%s", namespace, leaks.String())
+	}
+	return nil
+}
+
+// ExpectNoObjectsClusterWide lists the given resources across the whole
+// cluster (every namespace, plus cluster scope) by label and fails the spec
+// naming whatever it finds. Namespace tests call it with their run-ID label
+// after teardown, catching objects a misbehaving controller recreated in a
+// namespace the test never owned.
+func ExpectNoObjectsClusterWide(dc dynamic.Interface, gvrs []schema.GroupVersionResource, labelSelector string) {
+	var found []string
+	for _, gvr := range gvrs {
+		list, err := dc.Resource(gvr).List(context.TODO(), metav1.ListOptions{LabelSelector: labelSelector})
+		ExpectNoError(err, "failed to list %s labeled %q cluster-wide", gvr.Resource, labelSelector)
+		for _, item := range list.Items {
+			name := item.GetName()
+			if namespace := item.GetNamespace(); namespace != "" {
+				name = namespace + "/" + name
+			}
+			found = append(found, fmt.Sprintf("%s %s", gvr.Resource, name))
+		}
+	}
+	if len(found) > 0 {
+		sort.Strings(found)
+		Failf("objects labeled %q still exist somewhere in the cluster:\n%s", labelSelector, strings.Join(found, "\n"))
+	}
+}
+
+// reportNamespaceContentLeaks enumerates the remaining content of every
+// namespace the framework still owns and attaches the inventory to the ginkgo
+// report, so a stuck deletion names the blocking resource and finalizer
+// instead of just timing out.
+func reportNamespaceContentLeaks(f *Framework) {
+	if f.DynamicClient == nil {
+		return
+	}
+	for _, ns := range f.namespacesToDelete {
+		if ns == nil {
+			continue
+		}
+		inventory, err := InventoryNamespaceContent(f.ClientSet, f.DynamicClient, ns.Name)
+		if err != nil {
+			Logf("Failed to inventory content of namespace %s: %v", ns.Name, err)
+			continue
+		}
+		ginkgo.AddReportEntry(fmt.Sprintf("namespace-content %s", ns.Name), inventory.String())
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// The namespace lifecycle report aggregates create/delete latencies across
+// every spec of a run into one JSON file per ginkgo process in the report
+// directory, named namespace_lifecycle_<process>.json. Conformance tooling
+// picks the files up from the results tarball, giving the conformance WG
+// comparable lifecycle data across certified platforms. The report is
+// anonymized by construction: it carries only durations, never namespace or
+// spec names.
+//
+// Schema (v1):
+//
+//	{
+//	  "schemaVersion": "v1",
+//	  "createLatency": {"count": N, "p50Seconds": ..., "p90Seconds": ..., "p99Seconds": ..., "maxSeconds": ...},
+//	  "deleteLatency": {"count": N, "p50Seconds": ..., "p90Seconds": ..., "p99Seconds": ..., "maxSeconds": ...}
+//	}
+type NamespaceLifecycleReport struct {
+	SchemaVersion string              `json:"schemaVersion"`
+	CreateLatency LatencyDistribution `json:"createLatency"`
+	DeleteLatency LatencyDistribution `json:"deleteLatency"`
+}
+
+// LatencyDistribution summarizes a set of measured durations.
+type LatencyDistribution struct {
+	Count      int     `json:"count"`
+	P50Seconds float64 `json:"p50Seconds"`
+	P90Seconds float64 `json:"p90Seconds"`
+	P99Seconds float64 `json:"p99Seconds"`
+	MaxSeconds float64 `json:"maxSeconds"`
+}
+
+var (
+	suiteNamespaceTimingsLock sync.Mutex
+	suiteNamespaceCreates     []time.Duration
+	suiteNamespaceDeletes     []time.Duration
+)
+
+// recordSuiteNamespaceTiming feeds one namespace lifecycle measurement into
+// the run-wide aggregate.
+func recordSuiteNamespaceTiming(createDuration, deleteDuration time.Duration) {
+	suiteNamespaceTimingsLock.Lock()
+	defer suiteNamespaceTimingsLock.Unlock()
+	if createDuration > 0 {
+		suiteNamespaceCreates = append(suiteNamespaceCreates, createDuration)
+	}
+	if deleteDuration > 0 {
+		suiteNamespaceDeletes = append(suiteNamespaceDeletes, deleteDuration)
+	}
+}
+
+func distributionOf(latencies []time.Duration) LatencyDistribution {
+	if len(latencies) == 0 {
+		return LatencyDistribution{}
+	}
+	var max time.Duration
+	for _, latency := range latencies {
+		if latency > max {
+			max = latency
+		}
+	}
+	return LatencyDistribution{
+		Count:      len(latencies),
+		P50Seconds: LatencyPercentile(latencies, 50).Seconds(),
+		P90Seconds: LatencyPercentile(latencies, 90).Seconds(),
+		P99Seconds: LatencyPercentile(latencies, 99).Seconds(),
+		MaxSeconds: max.Seconds(),
+	}
+}
+
+// WriteNamespaceLifecycleReport writes this process's aggregated namespace
+// lifecycle latencies to the report directory. It is called from the suite
+// cleanup on every ginkgo process; without a report directory the report
+// goes to the log instead.
+func WriteNamespaceLifecycleReport() {
+	suiteNamespaceTimingsLock.Lock()
+	report := NamespaceLifecycleReport{
+		SchemaVersion: "v1",
+		CreateLatency: distributionOf(suiteNamespaceCreates),
+		DeleteLatency: distributionOf(suiteNamespaceDeletes),
+	}
+	suiteNamespaceTimingsLock.Unlock()
+	if report.CreateLatency.Count == 0 && report.DeleteLatency.Count == 0 {
+		return
+	}
+	content := PrettyPrintJSON(report)
+	if TestContext.ReportDir == "" {
+		Logf("Namespace lifecycle report: %s", content)
+		return
+	}
+	filePath := filepath.Join(TestContext.ReportDir, fmt.Sprintf("namespace_lifecycle_%d.json", ginkgo.GinkgoParallelProcess()))
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		Logf("Failed to write namespace lifecycle report %q: %v", filePath, err)
+	}
+}
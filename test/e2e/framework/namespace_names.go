@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+var (
+	// fixedNamespaceNames maps a fixed namespace name to the full text of the
+	// spec that claimed it, so that an accidental reuse by a second spec can be
+	// reported with both owners named.
+	fixedNamespaceNames     = map[string]string{}
+	fixedNamespaceNamesLock sync.Mutex
+)
+
+// ClaimFixedNamespaceName records that the current spec creates a namespace
+// with the given fixed (non-generated) name. If a different spec in this run
+// already claimed the same name the call fails fast, because two specs racing
+// over one namespace name produce confusing downstream failures that are hard
+// to trace back to the collision. Claiming the same name repeatedly from the
+// same spec is allowed.
+func ClaimFixedNamespaceName(name string) {
+	spec := ginkgo.CurrentSpecReport().FullText()
+	fixedNamespaceNamesLock.Lock()
+	defer fixedNamespaceNamesLock.Unlock()
+	if owner, claimed := fixedNamespaceNames[name]; claimed && owner != spec {
+		Failf("fixed namespace name %q is already used by spec %q; derive a unique name with CollisionFreeNamespaceName instead", name, owner)
+	}
+	fixedNamespaceNames[name] = spec
+}
+
+// CollisionFreeNamespaceName returns a namespace name that keeps the
+// human-readable base but is guaranteed not to collide with any fixed name
+// claimed so far in this run. The returned name is claimed for the current
+// spec as a side effect.
+func CollisionFreeNamespaceName(base string) string {
+	spec := ginkgo.CurrentSpecReport().FullText()
+	fixedNamespaceNamesLock.Lock()
+	defer fixedNamespaceNamesLock.Unlock()
+	for {
+		name := fmt.Sprintf("%s-%s", base, RandomSuffix())
+		if _, claimed := fixedNamespaceNames[name]; !claimed {
+			fixedNamespaceNames[name] = spec
+			return name
+		}
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// Pre-provisioned namespace mode: on clusters where the test user may not
+// create cluster-scoped objects, --existing-namespaces names a pool of
+// pre-created namespaces. The framework leases one per CreateNamespace call
+// instead of creating it, and at spec end deletes the namespace's content
+// rather than the namespace, returning it to the pool for the next spec.
+
+var (
+	existingNamespacePoolOnce sync.Once
+	existingNamespacePool     *namespacePool
+)
+
+type namespacePool struct {
+	lock      sync.Mutex
+	available []string
+	total     int
+}
+
+// UsingExistingNamespaces reports whether the run leases pre-provisioned
+// namespaces instead of creating its own.
+func UsingExistingNamespaces() bool {
+	return TestContext.ExistingNamespaces != ""
+}
+
+func getExistingNamespacePool() *namespacePool {
+	existingNamespacePoolOnce.Do(func() {
+		var names []string
+		for _, name := range strings.Split(TestContext.ExistingNamespaces, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		existingNamespacePool = &namespacePool{available: names, total: len(names)}
+	})
+	return existingNamespacePool
+}
+
+func (p *namespacePool) lease() (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.available) == 0 {
+		return "", fmt.Errorf("namespace pool exhausted: all %d pre-provisioned namespaces are leased; pass more via --existing-namespaces or lower parallelism", p.total)
+	}
+	name := p.available[0]
+	p.available = p.available[1:]
+	return name, nil
+}
+
+func (p *namespacePool) release(name string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.available = append(p.available, name)
+}
+
+// leaseExistingNamespace takes a namespace from the pool and verifies it is
+// usable. Labels are applied best effort: restricted clusters frequently
+// forbid namespace updates too, and the lease is still serviceable without
+// them.
+func leaseExistingNamespace(ctx context.Context, c clientset.Interface, labels map[string]string) (*v1.Namespace, error) {
+	pool := getExistingNamespacePool()
+	name, err := pool.lease()
+	if err != nil {
+		return nil, err
+	}
+	ns, err := c.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		pool.release(name)
+		return nil, fmt.Errorf("failed to lease pre-provisioned namespace %s: %v", name, err)
+	}
+	if ns.Status.Phase != v1.NamespaceActive {
+		pool.release(name)
+		return nil, fmt.Errorf("pre-provisioned namespace %s is %s, not Active", name, ns.Status.Phase)
+	}
+	if len(labels) > 0 {
+		patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": labels}})
+		if err == nil {
+			if patched, patchErr := c.CoreV1().Namespaces().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); patchErr != nil {
+				Logf("Cannot label leased namespace %s (continuing without labels): %v", name, patchErr)
+			} else {
+				ns = patched
+			}
+		}
+	}
+	Logf("Leased pre-provisioned namespace %s", name)
+	return ns, nil
+}
+
+// releaseExistingNamespace deletes the test content of a leased namespace and
+// returns it to the pool. A namespace whose content cannot be removed is held
+// back rather than handed dirty to the next spec.
+func releaseExistingNamespace(c clientset.Interface, dc dynamic.Interface, name string) error {
+	if err := cleanupNamespaceContent(c, dc, name); err != nil {
+		return fmt.Errorf("leased namespace %s not returned to the pool: %v", name, err)
+	}
+	getExistingNamespacePool().release(name)
+	Logf("Released pre-provisioned namespace %s back to the pool", name)
+	return nil
+}
+
+// cleanupNamespaceContent deletes every listable namespaced object in the
+// namespace except the auto-provisioned ones, then waits for the deletions to
+// take effect.
+func cleanupNamespaceContent(c clientset.Interface, dc dynamic.Interface, namespace string) error {
+	resourceLists, err := c.Discovery().ServerPreferredNamespacedResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return fmt.Errorf("failed to discover namespaced resources: %v", err)
+	}
+	var gvrs []schema.GroupVersionResource
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || !listableResource(resource) {
+				continue
+			}
+			gvrs = append(gvrs, gv.WithResource(resource.Name))
+		}
+	}
+	var failures []string
+	for _, gvr := range gvrs {
+		objs, err := dc.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			// Resources the pool user may not read cannot hold test content
+			// either; skip them like the leak inventory does.
+			continue
+		}
+		for _, item := range objs.Items {
+			if isAutoProvisionedNamespaceObject(gvr.String(), item.GetName()) {
+				continue
+			}
+			err := dc.Resource(gvr).Namespace(namespace).Delete(context.TODO(), item.GetName(), metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsMethodNotSupported(err) {
+				failures = append(failures, fmt.Sprintf("%s %s: %v", gvr.Resource, item.GetName(), err))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to delete content of namespace %s: %s", namespace, strings.Join(failures, "; "))
+	}
+	return wait.PollImmediate(2*time.Second, 2*time.Minute, func() (bool, error) {
+		if err := VerifyNamespaceEmpty(c, dc, namespace); err != nil {
+			Logf("Waiting for namespace %s cleanup: %v", namespace, err)
+			return false, nil
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// finalizerHypotheses maps finalizer substrings to the controller that is
+// expected to remove them. A stuck namespace whose remaining objects carry
+// one of these finalizers most likely waits on that controller.
+var finalizerHypotheses = map[string]string{
+	"kubernetes.io/pvc-protection":                "the PVC protection controller (is a pod still using the claim?)",
+	"kubernetes.io/pv-protection":                 "the PV protection controller",
+	"foregroundDeletion":                          "the garbage collector (foreground deletion of dependents)",
+	"orphan":                                      "the garbage collector (orphaning dependents)",
+	"service.kubernetes.io/load-balancer-cleanup": "the service controller in the cloud-controller-manager",
+	"snapshot.storage.kubernetes.io":              "the snapshot controller",
+	"kubernetes.io/pod-scheduling":                "the scheduler",
+	"batch.kubernetes.io/job-tracking":            "the job controller",
+	"apiextensions.kubernetes.io/customresource":  "the apiextensions-apiserver CRD finalizer controller",
+	"kubernetes.io/legacy-service-account-token":  "the service account token cleaner",
+}
+
+// managerHypotheses maps managedFields manager names to the component that
+// last touched an object. A finalizer the catalog above does not recognize is
+// probably owned by whichever manager set it.
+var managerHypotheses = map[string]string{
+	"kube-controller-manager": "kube-controller-manager",
+	"kubelet":                 "the kubelet (a pod may be stuck terminating on a node)",
+	"kube-scheduler":          "kube-scheduler",
+	"kube-apiserver":          "kube-apiserver",
+}
+
+// apiServicesGVR locates the aggregated API registrations for the dynamic
+// client; their typed client lives outside client-go.
+var apiServicesGVR = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+
+// namespaceDeletionFailureConditions are the condition types the namespace
+// controller sets when it cannot finish emptying a namespace. Discovery and
+// content failures are the two that an unavailable aggregated API produces.
+var namespaceDeletionFailureConditions = map[v1.NamespaceConditionType]bool{
+	"NamespaceDeletionDiscoveryFailure":           true,
+	"NamespaceDeletionContentFailure":             true,
+	"NamespaceDeletionGroupVersionParsingFailure": true,
+}
+
+// unavailableAPIServices returns the name and Available-condition message of
+// every registered APIService that is not currently available. Errors are
+// swallowed (returning nil) because this feeds best-effort diagnostics.
+func unavailableAPIServices(dc dynamic.Interface) map[string]string {
+	list, err := dc.Resource(apiServicesGVR).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	unavailable := map[string]string{}
+	for _, item := range list.Items {
+		conditions, found, err := unstructured.NestedSlice(item.Object, "status", "conditions")
+		if err != nil || !found {
+			continue
+		}
+		for _, condition := range conditions {
+			fields, ok := condition.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			conditionType, _, _ := unstructured.NestedString(fields, "type")
+			if conditionType != "Available" {
+				continue
+			}
+			status, _, _ := unstructured.NestedString(fields, "status")
+			if status != "True" {
+				reason, _, _ := unstructured.NestedString(fields, "reason")
+				message, _, _ := unstructured.NestedString(fields, "message")
+				unavailable[item.GetName()] = fmt.Sprintf("%s: %s", reason, message)
+			}
+		}
+	}
+	return unavailable
+}
+
+// classifyAPIServiceDeletionFailure checks whether the namespace's deletion
+// failure conditions point at an unavailable aggregated APIService, and if so
+// returns a targeted explanation naming it. An empty string means this
+// classification does not apply.
+func classifyAPIServiceDeletionFailure(ns *v1.Namespace, dc dynamic.Interface) string {
+	var failureMessages []string
+	for _, condition := range ns.Status.Conditions {
+		if condition.Status == v1.ConditionTrue && namespaceDeletionFailureConditions[condition.Type] {
+			failureMessages = append(failureMessages, condition.Message)
+		}
+	}
+	if len(failureMessages) == 0 {
+		return ""
+	}
+	unavailable := unavailableAPIServices(dc)
+	if len(unavailable) == 0 {
+		return ""
+	}
+	// APIService names have the form <version>.<group>; the namespace
+	// condition messages quote the failing group as <group>/<version>.
+	var blamed []string
+	for name, detail := range unavailable {
+		version, group, found := strings.Cut(name, ".")
+		if !found {
+			continue
+		}
+		for _, message := range failureMessages {
+			if strings.Contains(message, group+"/"+version) {
+				blamed = append(blamed, fmt.Sprintf("%s (%s)", name, detail))
+				break
+			}
+		}
+	}
+	if len(blamed) == 0 {
+		// The condition message did not quote a group/version we can match,
+		// but discovery is failing and aggregated APIs are down; name them
+		// all rather than falling back to a generic timeout.
+		for name, detail := range unavailable {
+			blamed = append(blamed, fmt.Sprintf("%s (%s)", name, detail))
+		}
+	}
+	sort.Strings(blamed)
+	return fmt.Sprintf("namespace %s cannot finish deleting because the namespace controller cannot complete discovery while these aggregated APIServices are unavailable: %s. Fix or delete the APIService (often a leftover metrics-server or webhook backend) and the namespace will drain on its own.",
+		ns.Name, strings.Join(blamed, "; "))
+}
+
+// ClassifyStuckNamespace returns a targeted explanation for a stuck namespace
+// when one of the known root-cause classifiers matches, or an empty string
+// when only the generic analysis applies. Deletion-timeout failure paths use
+// it to replace "timed out waiting for the condition" with the actual cause.
+func ClassifyStuckNamespace(c clientset.Interface, dc dynamic.Interface, namespace string) string {
+	if dc == nil {
+		return ""
+	}
+	ns, err := c.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	return classifyAPIServiceDeletionFailure(ns, dc)
+}
+
+// AnalyzeStuckNamespace inspects a Terminating namespace and produces a
+// human-readable hypothesis for what is blocking its deletion: the namespace
+// status conditions, every remaining object with its finalizers, and the
+// controllers those finalizers and managedFields managers point at.
+func AnalyzeStuckNamespace(c clientset.Interface, dc dynamic.Interface, namespace string) (string, error) {
+	ns, err := c.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get namespace %s: %v", namespace, err)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "namespace %s is in phase %s\n", namespace, ns.Status.Phase)
+	for _, condition := range ns.Status.Conditions {
+		if condition.Status == v1.ConditionTrue {
+			fmt.Fprintf(&b, "condition %s: %s: %s\n", condition.Type, condition.Reason, condition.Message)
+		}
+	}
+	if classification := classifyAPIServiceDeletionFailure(ns, dc); classification != "" {
+		fmt.Fprintf(&b, "classification: %s\n", classification)
+	}
+
+	resourceLists, err := c.Discovery().ServerPreferredNamespacedResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return "", fmt.Errorf("failed to discover namespaced resources: %v", err)
+	}
+	hypotheses := map[string]bool{}
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || !listableResource(resource) {
+				continue
+			}
+			gvr := gv.WithResource(resource.Name)
+			objs, err := dc.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				continue
+			}
+			for _, item := range objs.Items {
+				finalizers := item.GetFinalizers()
+				managers := managedFieldsManagers(item.GetManagedFields())
+				fmt.Fprintf(&b, "remaining %s %s (finalizers: %s; managers: %s)\n",
+					gvr.String(), item.GetName(), joinOrNone(finalizers), joinOrNone(managers))
+				for _, finalizer := range finalizers {
+					matched := false
+					for substring, hypothesis := range finalizerHypotheses {
+						if strings.Contains(finalizer, substring) {
+							hypotheses[fmt.Sprintf("finalizer %q on %s %s is removed by %s", finalizer, gvr.Resource, item.GetName(), hypothesis)] = true
+							matched = true
+						}
+					}
+					if matched {
+						continue
+					}
+					for _, manager := range managers {
+						if hypothesis, known := managerHypotheses[manager]; known {
+							hypotheses[fmt.Sprintf("finalizer %q on %s %s is unrecognized; the object was last managed by %s", finalizer, gvr.Resource, item.GetName(), hypothesis)] = true
+						}
+					}
+					if len(managers) == 0 {
+						hypotheses[fmt.Sprintf("finalizer %q on %s %s is unrecognized and the object has no managedFields; a third-party controller is the likely owner", finalizer, gvr.Resource, item.GetName())] = true
+					}
+				}
+			}
+		}
+	}
+	if len(hypotheses) == 0 {
+		fmt.Fprintf(&b, "hypothesis: no remaining object carries a finalizer; deletion is most likely blocked inside the namespace controller or an unavailable aggregated API (check the NamespaceDeletionDiscoveryFailure condition above)\n")
+	} else {
+		sorted := make([]string, 0, len(hypotheses))
+		for hypothesis := range hypotheses {
+			sorted = append(sorted, hypothesis)
+		}
+		sort.Strings(sorted)
+		for _, hypothesis := range sorted {
+			fmt.Fprintf(&b, "hypothesis: %s\n", hypothesis)
+		}
+	}
+	return b.String(), nil
+}
+
+// LogStuckNamespaceAnalysis runs AnalyzeStuckNamespace for each namespace and
+// logs the reports. It is a best-effort diagnostic for deletion-timeout
+// failure paths and never fails the test itself.
+func LogStuckNamespaceAnalysis(c clientset.Interface, dc dynamic.Interface, namespaces ...string) {
+	if dc == nil {
+		return
+	}
+	for _, namespace := range namespaces {
+		report, err := AnalyzeStuckNamespace(c, dc, namespace)
+		if err != nil {
+			Logf("Failed to analyze stuck namespace %s: %v", namespace, err)
+			continue
+		}
+		Logf("Root-cause analysis for stuck namespace %s:\n%s", namespace, report)
+	}
+}
+
+func managedFieldsManagers(entries []metav1.ManagedFieldsEntry) []string {
+	seen := map[string]bool{}
+	var managers []string
+	for _, entry := range entries {
+		if entry.Manager == "" || seen[entry.Manager] {
+			continue
+		}
+		seen[entry.Manager] = true
+		managers = append(managers, entry.Manager)
+	}
+	return managers
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package namespacecheck provides namespace lifecycle verification (create,
+// fill, delete, verify-empty and SLO checks) as an importable library. It
+// deliberately depends only on client-go so that downstream distributions can
+// embed the checks in their own harnesses without pulling in ginkgo or the
+// rest of the e2e framework.
+package namespacecheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultBaseName        = "nscheck"
+	defaultDeletionTimeout = 5 * time.Minute
+	pollInterval           = 2 * time.Second
+)
+
+// Options configures a lifecycle check run. The zero value is usable.
+type Options struct {
+	// BaseName is the prefix of the generated namespace name.
+	// Defaults to "nscheck".
+	BaseName string
+	// Labels are applied to the namespace created by the check.
+	Labels map[string]string
+	// DeletionTimeout bounds how long the namespace may take to terminate.
+	// Defaults to 5 minutes.
+	DeletionTimeout time.Duration
+}
+
+// Result captures what one lifecycle check observed.
+type Result struct {
+	// Namespace is the name of the namespace the check ran against.
+	Namespace string
+	// CreateDuration covers namespace creation up to the default service
+	// account being provisioned.
+	CreateDuration time.Duration
+	// DeleteDuration covers the delete call up to full termination.
+	DeleteDuration time.Duration
+}
+
+// VerifySLO returns an error if either measured duration exceeds its budget.
+// A zero budget disables the corresponding check.
+func (r *Result) VerifySLO(maxCreate, maxDelete time.Duration) error {
+	if maxCreate > 0 && r.CreateDuration > maxCreate {
+		return fmt.Errorf("namespace %s took %v to become usable, exceeding the budget of %v", r.Namespace, r.CreateDuration, maxCreate)
+	}
+	if maxDelete > 0 && r.DeleteDuration > maxDelete {
+		return fmt.Errorf("namespace %s took %v to terminate, exceeding the budget of %v", r.Namespace, r.DeleteDuration, maxDelete)
+	}
+	return nil
+}
+
+// RunLifecycleCheck creates a namespace, fills it with content, deletes it,
+// verifies nothing survived and returns the measured durations for SLO
+// checks. The namespace is cleaned up even when the check fails partway.
+func RunLifecycleCheck(c clientset.Interface, opts Options) (*Result, error) {
+	baseName := opts.BaseName
+	if baseName == "" {
+		baseName = defaultBaseName
+	}
+	deletionTimeout := opts.DeletionTimeout
+	if deletionTimeout == 0 {
+		deletionTimeout = defaultDeletionTimeout
+	}
+	name := fmt.Sprintf("%s-%s", baseName, rand.String(5))
+
+	start := time.Now()
+	namespace, err := c.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: opts.Labels},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create namespace %s: %v", name, err)
+	}
+	result := &Result{Namespace: namespace.Name}
+	defer func() {
+		// Best-effort cleanup for early returns; the happy path has already
+		// deleted the namespace and this turns into a no-op.
+		if err := c.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+			// Nothing sensible to do with the error here; the caller already
+			// has a more specific one.
+			_ = err
+		}
+	}()
+
+	if err := waitForDefaultServiceAccount(c, namespace.Name, deletionTimeout); err != nil {
+		return nil, err
+	}
+	result.CreateDuration = time.Since(start)
+
+	if err := fill(c, namespace.Name); err != nil {
+		return nil, err
+	}
+
+	start = time.Now()
+	if err := c.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to delete namespace %s: %v", namespace.Name, err)
+	}
+	if err := waitForTermination(c, namespace.Name, deletionTimeout); err != nil {
+		return nil, err
+	}
+	result.DeleteDuration = time.Since(start)
+
+	if err := verifyEmpty(c, namespace.Name); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func waitForDefaultServiceAccount(c clientset.Interface, namespace string, timeout time.Duration) error {
+	err := wait.PollImmediate(pollInterval, timeout, func() (bool, error) {
+		_, err := c.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), "default", metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	if err != nil {
+		return fmt.Errorf("default service account was never provisioned in namespace %s: %v", namespace, err)
+	}
+	return nil
+}
+
+// fill creates representative content covering a plain object and an object
+// with external side effects handled by controllers.
+func fill(c clientset.Interface, namespace string) error {
+	if _, err := c.CoreV1().ConfigMaps(namespace).Create(context.TODO(), &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "nscheck-content"},
+		Data:       map[string]string{"check": "namespacecheck"},
+	}, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create configmap in namespace %s: %v", namespace, err)
+	}
+	if _, err := c.CoreV1().Services(namespace).Create(context.TODO(), &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "nscheck-content"},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"check": "namespacecheck"},
+			Ports: []v1.ServicePort{{
+				Port:       80,
+				TargetPort: intstr.FromInt(80),
+			}},
+		},
+	}, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create service in namespace %s: %v", namespace, err)
+	}
+	return nil
+}
+
+func waitForTermination(c clientset.Interface, namespace string, timeout time.Duration) error {
+	err := wait.Poll(pollInterval, timeout, func() (bool, error) {
+		_, err := c.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("namespace %s did not finish terminating: %v", namespace, err)
+	}
+	return nil
+}
+
+// verifyEmpty confirms that no content from the deleted namespace is still
+// visible through the API.
+func verifyEmpty(c clientset.Interface, namespace string) error {
+	pods, err := c.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods in deleted namespace %s: %v", namespace, err)
+	}
+	if len(pods.Items) > 0 {
+		return fmt.Errorf("%d pods survived deletion of namespace %s", len(pods.Items), namespace)
+	}
+	services, err := c.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list services in deleted namespace %s: %v", namespace, err)
+	}
+	if len(services.Items) > 0 {
+		return fmt.Errorf("%d services survived deletion of namespace %s", len(services.Items), namespace)
+	}
+	configMaps, err := c.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list configmaps in deleted namespace %s: %v", namespace, err)
+	}
+	if len(configMaps.Items) > 0 {
+		return fmt.Errorf("%d configmaps survived deletion of namespace %s", len(configMaps.Items), namespace)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// defaultListPageSize bounds how many objects one paginated LIST request asks
+// for when the caller does not set a limit. It matches the chunk size kubectl
+// uses.
+const defaultListPageSize = 500
+
+// ListAllWithPagination lists every object of the given resource in chunks of
+// opts.Limit (or defaultListPageSize if unset), following continue tokens
+// until the list is exhausted. When a token expires because the backing
+// revision was compacted away mid-walk, the apiserver hands back an
+// inconsistent-continue token in the 410 response; the helper resumes from
+// it, so callers get a complete (if not single-snapshot-consistent) result
+// instead of an error. Use this instead of an unbounded List wherever the
+// result can be large, e.g. cluster-wide namespace or pod enumeration.
+func ListAllWithPagination(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	return listPaginated(ctx, client.Resource(gvr), gvr.String(), opts)
+}
+
+// ListAllWithPaginationInNamespace is ListAllWithPagination scoped to one
+// namespace.
+func ListAllWithPaginationInNamespace(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	return listPaginated(ctx, client.Resource(gvr).Namespace(namespace), fmt.Sprintf("%s in %s", gvr.String(), namespace), opts)
+}
+
+func listPaginated(ctx context.Context, c dynamic.ResourceInterface, what string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultListPageSize
+	}
+	var items []unstructured.Unstructured
+	for {
+		list, err := c.List(ctx, opts)
+		if apierrors.IsResourceExpired(err) && opts.Continue != "" {
+			status, ok := err.(apierrors.APIStatus)
+			if !ok || status.Status().ListMeta.Continue == "" {
+				return nil, fmt.Errorf("continue token for %s expired without an inconsistent-continue replacement: %v", what, err)
+			}
+			Logf("Continue token for %s expired, resuming with the inconsistent continue token from the 410 response", what)
+			opts.Continue = status.Status().ListMeta.Continue
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s (continue %q, limit %d): %v", what, opts.Continue, opts.Limit, err)
+		}
+		items = append(items, list.Items...)
+		if list.GetContinue() == "" {
+			return items, nil
+		}
+		opts.Continue = list.GetContinue()
+	}
+}
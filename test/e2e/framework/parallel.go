@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RunParallel invokes fn(i) for every i in [0, n) using at most maxConcurrency
+// concurrent goroutines; maxConcurrency <= 0 means unbounded. Unlike the raw
+// sync.WaitGroup + GinkgoRecover pattern it replaces, panics in fn are
+// recovered and surfaced as an error annotated with the goroutine index, and
+// the first error encountered is returned after all goroutines have finished.
+func RunParallel(n, maxConcurrency int, fn func(i int) error) error {
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		firstErr error
+	)
+	var sem chan struct{}
+	if maxConcurrency > 0 {
+		sem = make(chan struct{}, maxConcurrency)
+	}
+	setErr := func(err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+			defer func() {
+				if r := recover(); r != nil {
+					setErr(fmt.Errorf("goroutine %d panicked: %v", i, r))
+				}
+			}()
+			if err := fn(i); err != nil {
+				setErr(fmt.Errorf("goroutine %d: %v", i, err))
+			}
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
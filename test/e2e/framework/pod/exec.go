@@ -0,0 +1,231 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"golang.org/x/net/websocket"
+
+	e2elog "k8s.io/kubernetes/test/e2e/framework/log"
+	e2ewebsocket "k8s.io/kubernetes/test/e2e/framework/websocket"
+)
+
+// First-class exec support so tests do not have to shell out to kubectl,
+// which is absent from stripped-down CI images. The helpers speak the
+// WebSocket streaming protocol first, falling back to SPDY when the
+// apiserver rejects the websocket dial, so the newer protocol gets exercised
+// end to end wherever it is available.
+
+// Channel numbers of the v4.channel.k8s.io streaming protocol.
+const (
+	execStdoutChannel = 1
+	execStderrChannel = 2
+	execErrorChannel  = 3
+)
+
+// execOutputTailBytes is how much captured output is attached to an exec
+// failure; full output is still returned to the caller.
+const execOutputTailBytes = 1024
+
+// ExecOptions describes one command execution in a running container.
+type ExecOptions struct {
+	Command       []string
+	Namespace     string
+	PodName       string
+	ContainerName string
+	// Stdin, when non-nil, is streamed to the command. Commands with stdin
+	// always run over SPDY: the websocket client cannot half-close the
+	// stdin stream, so the remote command would never see EOF.
+	Stdin io.Reader
+	// If false, whitespace in std{err,out} will be removed.
+	PreserveWhitespace bool
+}
+
+// ExecWithOptions executes a command in the specified container and returns
+// stdout, stderr and error. The websocket streaming protocol is preferred;
+// when the websocket dial fails the command runs over SPDY instead. The
+// context cancels the execution, and failures carry the tail of the captured
+// output so the report shows what the command printed before dying.
+func ExecWithOptions(ctx context.Context, client clientset.Interface, config *restclient.Config, options ExecOptions) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	execErr := execViaWebSocket(ctx, client, config, options, &stdout, &stderr)
+	if _, fellBack := execErr.(*webSocketDialError); fellBack {
+		e2elog.Logf("WebSocket exec not available (%v); falling back to SPDY", execErr)
+		stdout.Reset()
+		stderr.Reset()
+		execErr = execViaSPDY(ctx, client, config, options, &stdout, &stderr)
+	}
+	if execErr != nil {
+		execErr = fmt.Errorf("exec %v in %s/%s failed: %v (stdout tail: %q, stderr tail: %q)",
+			options.Command, options.Namespace, options.PodName, execErr,
+			outputTail(&stdout), outputTail(&stderr))
+	}
+	if options.PreserveWhitespace {
+		return stdout.String(), stderr.String(), execErr
+	}
+	return strings.TrimSpace(stdout.String()), strings.TrimSpace(stderr.String()), execErr
+}
+
+// ExecShellInPod runs the command through a shell in the pod's given
+// container and fails the calling test on error.
+func ExecShellInPod(ctx context.Context, client clientset.Interface, config *restclient.Config, namespace, podName, containerName, cmd string) (string, string, error) {
+	return ExecWithOptions(ctx, client, config, ExecOptions{
+		Command:       []string{"/bin/sh", "-c", cmd},
+		Namespace:     namespace,
+		PodName:       podName,
+		ContainerName: containerName,
+	})
+}
+
+// webSocketDialError marks a failure to establish the websocket connection,
+// as opposed to a failure of the executed command. Only dial failures
+// trigger the SPDY fallback; rerunning a command that already ran and failed
+// would execute it twice.
+type webSocketDialError struct{ err error }
+
+func (e *webSocketDialError) Error() string { return e.err.Error() }
+
+// execViaWebSocket runs the command over the websocket streaming protocol.
+func execViaWebSocket(ctx context.Context, client clientset.Interface, config *restclient.Config, options ExecOptions, stdout, stderr *bytes.Buffer) error {
+	if options.Stdin != nil {
+		// See ExecOptions.Stdin.
+		return &webSocketDialError{err: fmt.Errorf("stdin requires SPDY")}
+	}
+	// WebSocket upgrades ride on GET; the options become query parameters.
+	req := client.CoreV1().RESTClient().Get().
+		Resource("pods").
+		Name(options.PodName).
+		Namespace(options.Namespace).
+		SubResource("exec")
+	req.VersionedParams(&v1.PodExecOptions{
+		Container: options.ContainerName,
+		Command:   options.Command,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+
+	ws, err := e2ewebsocket.OpenWebSocketForURL(req.URL(), config, []string{"v4.channel.k8s.io"})
+	if err != nil {
+		return &webSocketDialError{err: err}
+	}
+	defer ws.Close()
+
+	// The read loop below blocks in Receive; closing the connection is the
+	// only way to interrupt it when the context ends.
+	watchdogDone := make(chan struct{})
+	defer close(watchdogDone)
+	go func() {
+		select {
+		case <-ctx.Done():
+			ws.Close()
+		case <-watchdogDone:
+		}
+	}()
+
+	var status *metav1.Status
+	for {
+		var frame []byte
+		if err := websocket.Message.Receive(ws, &frame); err != nil {
+			if err == io.EOF {
+				break
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to read from the exec websocket: %v", err)
+		}
+		if len(frame) == 0 {
+			continue
+		}
+		switch frame[0] {
+		case execStdoutChannel:
+			stdout.Write(frame[1:])
+		case execStderrChannel:
+			stderr.Write(frame[1:])
+		case execErrorChannel:
+			// v4 carries the command result as a metav1.Status.
+			status = &metav1.Status{}
+			if err := json.Unmarshal(frame[1:], status); err != nil {
+				return fmt.Errorf("failed to decode the exec result %q: %v", string(frame[1:]), err)
+			}
+		}
+	}
+	if status != nil && status.Status != metav1.StatusSuccess {
+		return fmt.Errorf("command terminated: %s", status.Message)
+	}
+	return nil
+}
+
+// execViaSPDY runs the command over the SPDY streaming protocol.
+func execViaSPDY(ctx context.Context, client clientset.Interface, config *restclient.Config, options ExecOptions, stdout, stderr *bytes.Buffer) error {
+	req := client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(options.PodName).
+		Namespace(options.Namespace).
+		SubResource("exec")
+	req.VersionedParams(&v1.PodExecOptions{
+		Container: options.ContainerName,
+		Command:   options.Command,
+		Stdin:     options.Stdin != nil,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	// The vendored executor has no context support; stream in a goroutine so
+	// cancellation at least unblocks the caller.
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- exec.Stream(remotecommand.StreamOptions{
+			Stdin:  options.Stdin,
+			Stdout: stdout,
+			Stderr: stderr,
+		})
+	}()
+	select {
+	case err := <-streamErr:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// outputTail returns the last part of a captured stream for attaching to
+// error messages.
+func outputTail(buf *bytes.Buffer) string {
+	s := buf.String()
+	if len(s) > execOutputTailBytes {
+		s = "..." + s[len(s)-execOutputTailBytes:]
+	}
+	return s
+}
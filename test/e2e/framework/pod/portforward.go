@@ -0,0 +1,267 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"golang.org/x/net/websocket"
+
+	e2elog "k8s.io/kubernetes/test/e2e/framework/log"
+	e2ewebsocket "k8s.io/kubernetes/test/e2e/framework/websocket"
+)
+
+// First-class port-forwarding so tests do not have to shell out to kubectl
+// port-forward. Like exec, the websocket streaming protocol is preferred and
+// SPDY is the fallback.
+
+// Channel numbers of the port-forward streaming protocol.
+const (
+	portForwardDataChannel  = 0
+	portForwardErrorChannel = 1
+)
+
+// PortForwardSession forwards one pod port to a local listener until Stop is
+// called or the originating context ends.
+type PortForwardSession struct {
+	// LocalPort accepts connections on 127.0.0.1 and forwards them to the
+	// pod port.
+	LocalPort int
+
+	stopOnce sync.Once
+	stop     func()
+	// errOutput collects stream errors reported by the apiserver so they can
+	// be attached to failures.
+	errOutputLock sync.Mutex
+	errOutput     bytes.Buffer
+}
+
+// Stop tears the forwarding session down. It is idempotent.
+func (s *PortForwardSession) Stop() {
+	s.stopOnce.Do(s.stop)
+}
+
+// Address returns the local address the session listens on.
+func (s *PortForwardSession) Address() string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(s.LocalPort))
+}
+
+// ErrorOutput returns whatever the streaming connections reported on their
+// error channels so far.
+func (s *PortForwardSession) ErrorOutput() string {
+	s.errOutputLock.Lock()
+	defer s.errOutputLock.Unlock()
+	return s.errOutput.String()
+}
+
+func (s *PortForwardSession) recordStreamError(msg string) {
+	s.errOutputLock.Lock()
+	defer s.errOutputLock.Unlock()
+	fmt.Fprintln(&s.errOutput, msg)
+	e2elog.Logf("Port-forward stream error: %s", msg)
+}
+
+// PortForward starts forwarding a pod port to an ephemeral local port. The
+// websocket streaming protocol is used when the apiserver accepts it, SPDY
+// otherwise. The session ends when Stop is called or ctx is cancelled, and
+// failures include the output captured from the error channels.
+func PortForward(ctx context.Context, client clientset.Interface, config *restclient.Config, namespace, podName string, podPort int) (*PortForwardSession, error) {
+	// Probe the websocket path once; per-connection websockets are opened on
+	// demand afterwards.
+	probe, err := openPortForwardWebSocket(client, config, namespace, podName, podPort)
+	if err == nil {
+		probe.Close()
+		return portForwardViaWebSocket(ctx, client, config, namespace, podName, podPort)
+	}
+	e2elog.Logf("WebSocket port-forward not available (%v); falling back to SPDY", err)
+	return portForwardViaSPDY(ctx, client, config, namespace, podName, podPort)
+}
+
+// openPortForwardWebSocket dials the portforward subresource over websocket.
+func openPortForwardWebSocket(client clientset.Interface, config *restclient.Config, namespace, podName string, podPort int) (*websocket.Conn, error) {
+	req := client.CoreV1().RESTClient().Get().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("portforward").
+		Param("ports", strconv.Itoa(podPort))
+	return e2ewebsocket.OpenWebSocketForURL(req.URL(), config, []string{"v4.channel.k8s.io"})
+}
+
+// portForwardViaWebSocket serves a local listener where every accepted
+// connection gets its own websocket to the pod port.
+func portForwardViaWebSocket(ctx context.Context, client clientset.Interface, config *restclient.Config, namespace, podName string, podPort int) (*PortForwardSession, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a local listener: %v", err)
+	}
+	session := &PortForwardSession{
+		LocalPort: listener.Addr().(*net.TCPAddr).Port,
+		stop:      func() { listener.Close() },
+	}
+	go func() {
+		<-ctx.Done()
+		session.Stop()
+	}()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				// Stop closed the listener.
+				return
+			}
+			go session.bridgeConnOverWebSocket(ctx, conn, client, config, namespace, podName, podPort)
+		}
+	}()
+	return session, nil
+}
+
+// bridgeConnOverWebSocket pumps one local connection through a dedicated
+// websocket to the pod port.
+func (s *PortForwardSession) bridgeConnOverWebSocket(ctx context.Context, conn net.Conn, client clientset.Interface, config *restclient.Config, namespace, podName string, podPort int) {
+	defer conn.Close()
+	ws, err := openPortForwardWebSocket(client, config, namespace, podName, podPort)
+	if err != nil {
+		s.recordStreamError(fmt.Sprintf("failed to open a websocket for a new connection: %v", err))
+		return
+	}
+	defer ws.Close()
+	go func() {
+		<-ctx.Done()
+		ws.Close()
+		conn.Close()
+	}()
+
+	// Local to pod.
+	go func() {
+		buffer := make([]byte, 4096)
+		for {
+			n, err := conn.Read(buffer)
+			if n > 0 {
+				frame := make([]byte, n+1)
+				frame[0] = portForwardDataChannel
+				copy(frame[1:], buffer[:n])
+				if err := websocket.Message.Send(ws, frame); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				// Closing the websocket is the only way to signal EOF.
+				ws.Close()
+				return
+			}
+		}
+	}()
+
+	// Pod to local. The first frame on each channel carries the confirmed
+	// port number, not payload.
+	seenFirst := map[byte]bool{}
+	for {
+		var frame []byte
+		if err := websocket.Message.Receive(ws, &frame); err != nil {
+			if err != io.EOF && ctx.Err() == nil {
+				s.recordStreamError(fmt.Sprintf("websocket read failed: %v", err))
+			}
+			return
+		}
+		if len(frame) == 0 {
+			continue
+		}
+		channel, payload := frame[0], frame[1:]
+		if !seenFirst[channel] {
+			seenFirst[channel] = true
+			continue
+		}
+		switch channel {
+		case portForwardDataChannel:
+			if _, err := conn.Write(payload); err != nil {
+				return
+			}
+		case portForwardErrorChannel:
+			s.recordStreamError(string(payload))
+		}
+	}
+}
+
+// portForwardViaSPDY runs a client-go port forwarder over SPDY.
+func portForwardViaSPDY(ctx context.Context, client clientset.Interface, config *restclient.Config, namespace, podName string, podPort int) (*PortForwardSession, error) {
+	req := client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("portforward")
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the SPDY round tripper: %v", err)
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	session := &PortForwardSession{stop: func() { close(stopCh) }}
+	var errOut syncedWriter
+	errOut.write = session.recordStreamError
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"},
+		[]string{fmt.Sprintf("0:%d", podPort)}, stopCh, readyCh, io.Discard, &errOut)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the SPDY port forwarder: %v", err)
+	}
+	forwardErr := make(chan error, 1)
+	go func() { forwardErr <- forwarder.ForwardPorts() }()
+	go func() {
+		<-ctx.Done()
+		session.Stop()
+	}()
+	select {
+	case <-readyCh:
+	case err := <-forwardErr:
+		return nil, fmt.Errorf("SPDY port forwarding failed to start: %v (error output: %q)", err, session.ErrorOutput())
+	case <-ctx.Done():
+		session.Stop()
+		return nil, ctx.Err()
+	}
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		session.Stop()
+		return nil, fmt.Errorf("failed to read the forwarded ports: %v", err)
+	}
+	session.LocalPort = int(ports[0].Local)
+	return session, nil
+}
+
+// syncedWriter adapts the port forwarder's error writer to the session's
+// error capture.
+type syncedWriter struct {
+	write func(string)
+}
+
+func (w *syncedWriter) Write(p []byte) (int, error) {
+	w.write(string(bytes.TrimRight(p, "\n")))
+	return len(p), nil
+}
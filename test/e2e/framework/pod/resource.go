@@ -28,6 +28,7 @@ import (
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -403,6 +404,60 @@ func NewAgnhostPod(ns, podName string, volumes []v1.Volume, mounts []v1.VolumeMo
 	return pod
 }
 
+// NewMinimalPod returns the smallest workload the suite can run: a pause
+// container with tiny guaranteed resources and a restricted-compliant
+// security context, so it is admitted on clusters that enforce LimitRanges
+// or the restricted pod security level by default. Tests that only need
+// "some pod" in a namespace should prefer this builder.
+func NewMinimalPod(name string) *v1.Pod {
+	return NewPodWithQOS(name, v1.PodQOSGuaranteed)
+}
+
+// NewPodWithQOS returns a minimal pause pod shaped to land in the given QoS
+// class: Guaranteed (limits only, so the requests default to them), Burstable
+// (requests below limits) or BestEffort (no resources at all). BestEffort
+// pods are rejected by namespaces whose LimitRange mandates requests.
+func NewPodWithQOS(name string, qos v1.PodQOSClass) *v1.Pod {
+	var resources v1.ResourceRequirements
+	switch qos {
+	case v1.PodQOSGuaranteed:
+		resources = v1.ResourceRequirements{
+			Limits: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10m"),
+				v1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		}
+	case v1.PodQOSBurstable:
+		resources = v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10m"),
+				v1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+			Limits: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("20m"),
+				v1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		}
+	case v1.PodQOSBestEffort:
+		// No resources.
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:      "pause",
+					Image:     imageutils.GetPauseImageName(),
+					Resources: resources,
+				},
+			},
+		},
+	}
+	return MustMixinRestrictedPodSecurity(pod)
+}
+
 // NewAgnhostContainer returns the container Spec of an agnhost container.
 func NewAgnhostContainer(containerName string, mounts []v1.VolumeMount, ports []v1.ContainerPort, args ...string) v1.Container {
 	if len(args) == 0 {
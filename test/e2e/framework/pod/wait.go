@@ -243,16 +243,36 @@ func WaitForPodsRunningReady(c clientset.Interface, ns string, minPods, allowedN
 	return nil
 }
 
+// waitTimeRecorder, when set, receives the wall time spent in pod condition
+// waits. The framework package installs its per-spec timing collector here;
+// this package cannot call it directly without an import cycle.
+var waitTimeRecorder func(category string, elapsed time.Duration)
+
+// SetWaitTimeRecorder installs the callback that receives the time spent in
+// pod condition waits.
+func SetWaitTimeRecorder(recorder func(category string, elapsed time.Duration)) {
+	waitTimeRecorder = recorder
+}
+
 // WaitForPodCondition waits a pods to be matched to the given condition.
 func WaitForPodCondition(c clientset.Interface, ns, podName, conditionDesc string, timeout time.Duration, condition podCondition) error {
+	return WaitForPodConditionWithContext(context.TODO(), c, ns, podName, conditionDesc, timeout, condition)
+}
+
+// WaitForPodConditionWithContext is WaitForPodCondition with an explicit
+// context; cancelling it ends the poll and the in-flight Get.
+func WaitForPodConditionWithContext(ctx context.Context, c clientset.Interface, ns, podName, conditionDesc string, timeout time.Duration, condition podCondition) error {
 	e2elog.Logf("Waiting up to %v for pod %q in namespace %q to be %q", timeout, podName, ns, conditionDesc)
 	var (
 		lastPodError error
 		lastPod      *v1.Pod
 		start        = time.Now()
 	)
-	err := wait.PollImmediate(poll, timeout, func() (bool, error) {
-		pod, err := c.CoreV1().Pods(ns).Get(context.TODO(), podName, metav1.GetOptions{})
+	if waitTimeRecorder != nil {
+		defer func() { waitTimeRecorder("pod-condition", time.Since(start)) }()
+	}
+	err := wait.PollImmediateWithContext(ctx, poll, timeout, func(ctx context.Context) (bool, error) {
+		pod, err := c.CoreV1().Pods(ns).Get(ctx, podName, metav1.GetOptions{})
 		lastPodError = err
 		if err != nil {
 			return handleWaitingAPIError(err, true, "getting pod %s", podIdentifier(ns, podName))
@@ -398,7 +418,13 @@ func WaitForPodRunningInNamespaceSlow(c clientset.Interface, podName, namespace
 
 // WaitTimeoutForPodRunningInNamespace waits the given timeout duration for the specified pod to become running.
 func WaitTimeoutForPodRunningInNamespace(c clientset.Interface, podName, namespace string, timeout time.Duration) error {
-	return WaitForPodCondition(c, namespace, podName, "running", timeout, func(pod *v1.Pod) (bool, error) {
+	return WaitTimeoutForPodRunningInNamespaceWithContext(context.TODO(), c, podName, namespace, timeout)
+}
+
+// WaitTimeoutForPodRunningInNamespaceWithContext is
+// WaitTimeoutForPodRunningInNamespace bounded by an explicit context.
+func WaitTimeoutForPodRunningInNamespaceWithContext(ctx context.Context, c clientset.Interface, podName, namespace string, timeout time.Duration) error {
+	return WaitForPodConditionWithContext(ctx, c, namespace, podName, "running", timeout, func(pod *v1.Pod) (bool, error) {
 		switch pod.Status.Phase {
 		case v1.PodRunning:
 			return true, nil
@@ -412,10 +438,16 @@ func WaitTimeoutForPodRunningInNamespace(c clientset.Interface, podName, namespa
 // WaitForPodRunningInNamespace waits default amount of time (podStartTimeout) for the specified pod to become running.
 // Returns an error if timeout occurs first, or pod goes in to failed state.
 func WaitForPodRunningInNamespace(c clientset.Interface, pod *v1.Pod) error {
+	return WaitForPodRunningInNamespaceWithContext(context.TODO(), c, pod)
+}
+
+// WaitForPodRunningInNamespaceWithContext is WaitForPodRunningInNamespace
+// bounded by an explicit context.
+func WaitForPodRunningInNamespaceWithContext(ctx context.Context, c clientset.Interface, pod *v1.Pod) error {
 	if pod.Status.Phase == v1.PodRunning {
 		return nil
 	}
-	return WaitTimeoutForPodRunningInNamespace(c, pod.Name, pod.Namespace, podStartTimeout)
+	return WaitTimeoutForPodRunningInNamespaceWithContext(ctx, c, pod.Name, pod.Namespace, podStartTimeout)
 }
 
 // WaitTimeoutForPodNoLongerRunningInNamespace waits the given timeout duration for the specified pod to stop.
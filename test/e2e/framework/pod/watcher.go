@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	e2elog "k8s.io/kubernetes/test/e2e/framework/log"
+)
+
+// watcherPollInterval is how often waits re-check the watcher's local cache.
+// The cache is fed by the shared watch, so this polling costs no API calls.
+const watcherPollInterval = 200 * time.Millisecond
+
+// PodWatcher maintains one shared watch over the pods of a namespace and
+// answers waits from its local cache. Specs that fan out many pods should
+// use it instead of the per-pod polling helpers, which issue one GET per pod
+// per poll interval against the apiserver.
+type PodWatcher struct {
+	namespace string
+	informer  cache.SharedIndexInformer
+	stopCh    chan struct{}
+}
+
+// NewPodWatcher starts a shared watch over the pods of the namespace and
+// waits for its cache to sync. Callers must Stop the watcher when done; the
+// watch also ends when the given context is cancelled.
+func NewPodWatcher(ctx context.Context, c clientset.Interface, namespace string) (*PodWatcher, error) {
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return c.CoreV1().Pods(namespace).List(ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return c.CoreV1().Pods(namespace).Watch(ctx, options)
+			},
+		},
+		&v1.Pod{},
+		0,
+		cache.Indexers{},
+	)
+	w := &PodWatcher{namespace: namespace, informer: informer, stopCh: make(chan struct{})}
+	go func() {
+		select {
+		case <-ctx.Done():
+			w.Stop()
+		case <-w.stopCh:
+		}
+	}()
+	go informer.Run(w.stopCh)
+	if !cache.WaitForCacheSync(w.stopCh, informer.HasSynced) {
+		return nil, fmt.Errorf("pod watch for namespace %s never synced", namespace)
+	}
+	return w, nil
+}
+
+// Stop ends the shared watch. Safe to call more than once.
+func (w *PodWatcher) Stop() {
+	select {
+	case <-w.stopCh:
+	default:
+		close(w.stopCh)
+	}
+}
+
+// WaitForCondition waits until the named pod satisfies the condition, reading
+// only from the watch cache. The condition sees nil while the pod does not
+// exist yet.
+func (w *PodWatcher) WaitForCondition(podName string, conditionDesc string, timeout time.Duration, condition func(pod *v1.Pod) (bool, error)) error {
+	e2elog.Logf("Waiting up to %v for pod %q in namespace %q to be %q (via shared watch)", timeout, podName, w.namespace, conditionDesc)
+	start := time.Now()
+	if waitTimeRecorder != nil {
+		defer func() { waitTimeRecorder("pod-condition", time.Since(start)) }()
+	}
+	var lastPod *v1.Pod
+	err := wait.PollImmediate(watcherPollInterval, timeout, func() (bool, error) {
+		lastPod = w.get(podName)
+		return condition(lastPod)
+	})
+	if err != nil && lastPod != nil {
+		e2elog.Logf("Pod %q in namespace %q was last seen in phase %q", podName, w.namespace, lastPod.Status.Phase)
+	}
+	return err
+}
+
+// WaitForRunning waits until the named pod is running, failing early if it
+// completes instead.
+func (w *PodWatcher) WaitForRunning(podName string, timeout time.Duration) error {
+	return w.WaitForCondition(podName, "running", timeout, func(pod *v1.Pod) (bool, error) {
+		if pod == nil {
+			return false, nil
+		}
+		switch pod.Status.Phase {
+		case v1.PodRunning:
+			return true, nil
+		case v1.PodFailed, v1.PodSucceeded:
+			return false, errPodCompleted
+		}
+		return false, nil
+	})
+}
+
+// WaitForDeletion waits until the named pod is gone from the cache, meaning
+// the watch delivered its delete event.
+func (w *PodWatcher) WaitForDeletion(podName string, timeout time.Duration) error {
+	return w.WaitForCondition(podName, "deleted", timeout, func(pod *v1.Pod) (bool, error) {
+		return pod == nil, nil
+	})
+}
+
+func (w *PodWatcher) get(podName string) *v1.Pod {
+	obj, exists, err := w.informer.GetStore().GetByKey(w.namespace + "/" + podName)
+	if err != nil || !exists {
+		return nil
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return nil
+	}
+	return pod
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// The pollution detector snapshots a fixed set of cluster-scoped resources
+// around each Serial spec and reports objects that appeared or disappeared.
+// The namespace Serial lane is sensitive to background load from
+// cluster-scoped leftovers (webhooks intercepting every request, leaked CRD
+// controllers), and a diff pinned to the spec that created them is the only
+// way to attribute that pollution. It is opt-in via --cluster-pollution-check
+// because the extra LISTs are not free on large clusters.
+
+// pollutionWatchedResources are the cluster-scoped resources worth diffing:
+// each of them can change the behavior or the load of every later spec.
+var pollutionWatchedResources = []schema.GroupVersionResource{
+	{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"},
+	{Group: "", Version: "v1", Resource: "persistentvolumes"},
+	{Group: "scheduling.k8s.io", Version: "v1", Resource: "priorityclasses"},
+}
+
+var (
+	pollutionSnapshotLock sync.Mutex
+	// pollutionSnapshot maps each watched resource to the object names seen
+	// before the current Serial spec; nil between specs.
+	pollutionSnapshot map[schema.GroupVersionResource]map[string]bool
+)
+
+func init() {
+	RegisterSpecHook(SpecHook{
+		Name:       "cluster-pollution-detector",
+		BeforeSpec: snapshotClusterScopedResources,
+		AfterSpec:  diffClusterScopedResources,
+	})
+}
+
+// pollutionCheckApplies reports whether the detector should bracket the
+// current spec: the flag must be on and the spec must be Serial, because only
+// there is nothing else running that could own a concurrent change.
+func pollutionCheckApplies() bool {
+	if TestContext.ClusterPollutionCheck == "" {
+		return false
+	}
+	return strings.Contains(ginkgo.CurrentSpecReport().FullText(), "[Serial]")
+}
+
+func snapshotClusterScopedResources(f *Framework) {
+	if !pollutionCheckApplies() {
+		return
+	}
+	snapshot, err := listClusterScopedNames(f)
+	if err != nil {
+		Logf("Cluster pollution check disabled for this spec: %v", err)
+		return
+	}
+	pollutionSnapshotLock.Lock()
+	pollutionSnapshot = snapshot
+	pollutionSnapshotLock.Unlock()
+}
+
+func diffClusterScopedResources(f *Framework, failed bool) {
+	pollutionSnapshotLock.Lock()
+	before := pollutionSnapshot
+	pollutionSnapshot = nil
+	pollutionSnapshotLock.Unlock()
+	if before == nil || !pollutionCheckApplies() {
+		return
+	}
+	after, err := listClusterScopedNames(f)
+	if err != nil {
+		Logf("Cluster pollution check skipped: %v", err)
+		return
+	}
+	var diffs []string
+	for _, gvr := range pollutionWatchedResources {
+		for name := range after[gvr] {
+			if !before[gvr][name] {
+				diffs = append(diffs, fmt.Sprintf("%s %q appeared", gvr.Resource, name))
+			}
+		}
+		for name := range before[gvr] {
+			if !after[gvr][name] {
+				diffs = append(diffs, fmt.Sprintf("%s %q disappeared", gvr.Resource, name))
+			}
+		}
+	}
+	if len(diffs) == 0 {
+		return
+	}
+	sort.Strings(diffs)
+	message := fmt.Sprintf("cluster-scoped resources changed during this spec:\n%s", strings.Join(diffs, "\n"))
+	switch TestContext.ClusterPollutionCheck {
+	case "fail":
+		// A failed spec very likely skipped its own cleanup; failing it again
+		// for the resulting diff would only bury the real failure.
+		if failed {
+			Logf("WARNING: %s", message)
+			return
+		}
+		Failf("%s", message)
+	default:
+		Logf("WARNING: %s", message)
+	}
+}
+
+// listClusterScopedNames lists the names of every watched cluster-scoped
+// resource. Resources the cluster does not serve are skipped.
+func listClusterScopedNames(f *Framework) (map[schema.GroupVersionResource]map[string]bool, error) {
+	if f.DynamicClient == nil {
+		return nil, fmt.Errorf("no dynamic client")
+	}
+	snapshot := map[schema.GroupVersionResource]map[string]bool{}
+	for _, gvr := range pollutionWatchedResources {
+		list, err := f.DynamicClient.Resource(gvr).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %v", gvr.Resource, err)
+		}
+		names := make(map[string]bool, len(list.Items))
+		for _, item := range list.Items {
+			names[item.GetName()] = true
+		}
+		snapshot[gvr] = names
+	}
+	return snapshot, nil
+}
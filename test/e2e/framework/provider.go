@@ -108,6 +108,10 @@ type ProviderInterface interface {
 	EnsureLoadBalancerResourcesDeleted(ip, portRange string) error
 	LoadBalancerSrcRanges() []string
 	EnableAndDisableInternalLB() (enable, disable func(svc *v1.Service))
+
+	VerifyNamespaceExternalCleanup(namespace string, services []v1.Service) error
+
+	RotateEncryptionKeys() error
 }
 
 // NullProvider is the default implementation of the ProviderInterface
@@ -188,4 +192,52 @@ func (n NullProvider) EnableAndDisableInternalLB() (enable, disable func(svc *v1
 	return nop, nop
 }
 
+// RotateEncryptionKeys is a base implementation which rotates the
+// encryption-at-rest keys of the control plane. Providers that expose
+// encryption config control override this; everyone else reports that the
+// operation is unsupported so tests can skip.
+func (n NullProvider) RotateEncryptionKeys() error {
+	return fmt.Errorf("provider does not support encryption key rotation")
+}
+
+// VerifyNamespaceExternalCleanup is a base implementation which verifies that
+// external resources (load balancers, DNS entries) provisioned for services in
+// the given, now deleted, namespace have been cleaned up. The services slice
+// holds the services as they existed before the namespace was deleted.
+// Providers without external resources have nothing to verify.
+func (n NullProvider) VerifyNamespaceExternalCleanup(namespace string, services []v1.Service) error {
+	return nil
+}
+
 var _ ProviderInterface = NullProvider{}
+
+// ProviderCapabilities optionally declares what the provider's clusters can
+// run. Providers that do not implement it are assumed to have full clusters
+// with schedulable nodes and load balancer support; virtual control planes
+// (the vcluster provider) declare both absent so that dependent specs skip
+// instead of timing out.
+type ProviderCapabilities interface {
+	// HasNodes reports whether the cluster runs nodes that can start pods.
+	HasNodes() bool
+	// HasLoadBalancers reports whether Services of type LoadBalancer get
+	// external resources provisioned.
+	HasLoadBalancers() bool
+}
+
+// ProviderHasNodes reports whether the current provider's clusters can run
+// pods.
+func ProviderHasNodes() bool {
+	if caps, ok := TestContext.CloudConfig.Provider.(ProviderCapabilities); ok {
+		return caps.HasNodes()
+	}
+	return true
+}
+
+// ProviderHasLoadBalancers reports whether the current provider provisions
+// external load balancers.
+func ProviderHasLoadBalancers() bool {
+	if caps, ok := TestContext.CloudConfig.Provider.(ProviderCapabilities); ok {
+		return caps.HasLoadBalancers()
+	}
+	return true
+}
@@ -208,6 +208,29 @@ func (p *Provider) EnsureLoadBalancerResourcesDeleted(ip, portRange string) erro
 	})
 }
 
+// VerifyNamespaceExternalCleanup checks that forwarding rules provisioned for
+// LoadBalancer services that existed in the now deleted namespace are gone.
+func (p *Provider) VerifyNamespaceExternalCleanup(namespace string, services []v1.Service) error {
+	for i := range services {
+		svc := &services[i]
+		if svc.Spec.Type != v1.ServiceTypeLoadBalancer {
+			continue
+		}
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP == "" {
+				continue
+			}
+			for _, port := range svc.Spec.Ports {
+				portRange := fmt.Sprintf("%d-%d", port.Port, port.Port)
+				if err := p.EnsureLoadBalancerResourcesDeleted(ingress.IP, portRange); err != nil {
+					return fmt.Errorf("load balancer resources for service %s/%s not cleaned up: %v", namespace, svc.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func getGCEZoneForGroup(group string) (string, error) {
 	output, err := exec.Command("gcloud", "compute", "instance-groups", "managed", "list",
 		"--project="+framework.TestContext.CloudConfig.ProjectID, "--format=value(zone)", "--filter=name="+group).Output()
@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vcluster supports running the API-level suites against a nested or
+// virtual control plane reached purely through a kubeconfig. Such clusters
+// serve the full Kubernetes API but run no nodes and provision no cloud
+// resources, so the provider declares those capabilities absent and the
+// skippers keep pod- and load-balancer-dependent specs out of the run.
+package vcluster
+
+import (
+	"k8s.io/kubernetes/test/e2e/framework"
+)
+
+func init() {
+	framework.RegisterProvider("vcluster", func() (framework.ProviderInterface, error) {
+		return &Provider{}, nil
+	})
+}
+
+// Provider is a kubeconfig-only provider for virtual control planes. All
+// cloud operations fall through to the NullProvider no-ops.
+type Provider struct {
+	framework.NullProvider
+}
+
+// HasNodes reports that virtual control planes run no nodes, so pods never
+// start.
+func (p *Provider) HasNodes() bool {
+	return false
+}
+
+// HasLoadBalancers reports that virtual control planes provision no external
+// load balancers.
+func (p *Provider) HasLoadBalancers() bool {
+	return false
+}
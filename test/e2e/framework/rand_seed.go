@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Randomized names make some namespace-suite flakes depend on the exact name
+// ordering of a run. The framework therefore draws all its randomness from
+// one seeded source: the seed is recorded as an artifact, and passing it back
+// via --test-seed replays the same sequence of names.
+var (
+	seededRandLock sync.Mutex
+	seededRand     *rand.Rand
+	chosenTestSeed int64
+)
+
+// InitializeTestSeed picks the seed for the framework's randomness source,
+// preferring --test-seed when set, records it, and returns it so callers can
+// also seed the global math/rand with the same value. It is called once after
+// flag parsing.
+func InitializeTestSeed() int64 {
+	seededRandLock.Lock()
+	defer seededRandLock.Unlock()
+	seed := TestContext.TestSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	chosenTestSeed = seed
+	seededRand = rand.New(rand.NewSource(seed))
+	Logf("Using test seed %d (replay this run's randomized names with --test-seed=%d)", seed, seed)
+	if TestContext.ReportDir != "" {
+		data, err := json.MarshalIndent(map[string]int64{"seed": seed}, "", "  ")
+		if err == nil {
+			filePath := filepath.Join(TestContext.ReportDir, "test_seed.json")
+			if err := os.WriteFile(filePath, data, 0644); err != nil {
+				Logf("Failed to write test seed to %q: %v", filePath, err)
+			}
+		}
+	}
+	return seed
+}
+
+// RandIntn returns a non-negative pseudo-random number in [0,n) from the
+// framework's seeded randomness source.
+func RandIntn(n int) int {
+	seededRandLock.Lock()
+	defer seededRandLock.Unlock()
+	if seededRand == nil {
+		// Unit tests and tools that never call InitializeTestSeed fall back
+		// to a time-derived seed.
+		chosenTestSeed = time.Now().UnixNano()
+		seededRand = rand.New(rand.NewSource(chosenTestSeed))
+	}
+	return seededRand.Intn(n)
+}
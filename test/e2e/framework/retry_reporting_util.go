@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// RetryAttempt describes a single retry performed by a framework helper.
+type RetryAttempt struct {
+	// Category groups retries by their cause, e.g. "conflict", "webhook", "throttled".
+	Category string `json:"category"`
+	// Description explains what was being retried.
+	Description string `json:"description"`
+}
+
+// RetryReport aggregates all retry attempts made by framework helpers during a
+// spec. A passing spec that needed many retries is less healthy than its green
+// result suggests; the report makes that visible in the artifacts.
+type RetryReport struct {
+	lock       sync.RWMutex
+	Retries    []RetryAttempt `json:"retries"`
+	RetryCount int            `json:"retryCount"`
+}
+
+// NewRetryReport returns a new retry report.
+func NewRetryReport() *RetryReport {
+	return &RetryReport{
+		Retries: []RetryAttempt{},
+	}
+}
+
+// currentRetryReport is the report of the spec currently executing on this
+// ginkgo node. Helpers record into it via RecordRetry; it is nil outside of a
+// spec.
+var (
+	currentRetryReport     *RetryReport
+	currentRetryReportLock sync.Mutex
+)
+
+// setCurrentRetryReport installs (or clears, when nil) the retry report that
+// package-level helpers record into.
+func setCurrentRetryReport(r *RetryReport) {
+	currentRetryReportLock.Lock()
+	defer currentRetryReportLock.Unlock()
+	currentRetryReport = r
+}
+
+// RecordRetry records a retry attempt made by a framework helper with the
+// given category (e.g. "conflict", "webhook", "throttled") and a printf-style
+// description. It is a no-op outside of a running spec.
+func RecordRetry(category string, optionalDescription ...interface{}) {
+	currentRetryReportLock.Lock()
+	report := currentRetryReport
+	currentRetryReportLock.Unlock()
+	if report == nil {
+		return
+	}
+	report.record(category, buildDescription(optionalDescription...))
+}
+
+func (r *RetryReport) record(category, description string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.Retries = append(r.Retries, RetryAttempt{Category: category, Description: description})
+	r.RetryCount++
+}
+
+// GetRetryCount returns the number of recorded retries.
+func (r *RetryReport) GetRetryCount() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.RetryCount
+}
+
+// PrintHumanReadable returns a string rendering of the retry report.
+func (r *RetryReport) PrintHumanReadable() string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	buf := bytes.Buffer{}
+	buf.WriteString(fmt.Sprintf("RetryCount: %v\n", r.RetryCount))
+	buf.WriteString("Retries:\n")
+	for _, retry := range r.Retries {
+		buf.WriteString(fmt.Sprintf("[%v] %v\n", retry.Category, retry.Description))
+	}
+	return buf.String()
+}
+
+// PrintJSON returns the retry report in JSON format.
+func (r *RetryReport) PrintJSON() string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return PrettyPrintJSON(r)
+}
+
+// SummaryKind returns the kind of the retry report summary.
+func (r *RetryReport) SummaryKind() string {
+	return "RetryReport"
+}
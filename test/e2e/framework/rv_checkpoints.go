@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// RVCheckpoint is one recorded namespaces-list resourceVersion together with
+// the wall time it was taken at.
+type RVCheckpoint struct {
+	Time            time.Time `json:"time"`
+	ResourceVersion string    `json:"resourceVersion"`
+}
+
+// RVCheckpointRecorder periodically records the resourceVersion of the
+// namespaces resource so that, after a failure, watches can be replayed from
+// a point shortly before the interesting events (as long as the recorded
+// versions have not been compacted away).
+type RVCheckpointRecorder struct {
+	c        clientset.Interface
+	interval time.Duration
+
+	mu          sync.Mutex
+	checkpoints []RVCheckpoint
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// StartRVCheckpointRecorder starts recording checkpoints at the given
+// interval until Stop is called.
+func StartRVCheckpointRecorder(c clientset.Interface, interval time.Duration) *RVCheckpointRecorder {
+	r := &RVCheckpointRecorder{
+		c:        c,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		r.record()
+		for {
+			select {
+			case <-r.stopCh:
+				return
+			case <-ticker.C:
+				r.record()
+			}
+		}
+	}()
+	return r
+}
+
+func (r *RVCheckpointRecorder) record() {
+	nsList, err := r.c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{Limit: 1})
+	if err != nil {
+		Logf("Failed to record resourceVersion checkpoint: %v", err)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkpoints = append(r.checkpoints, RVCheckpoint{Time: time.Now(), ResourceVersion: nsList.ResourceVersion})
+}
+
+// Stop ends recording and returns all checkpoints taken, oldest first.
+func (r *RVCheckpointRecorder) Stop() []RVCheckpoint {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+	r.wg.Wait()
+	return r.Checkpoints()
+}
+
+// Checkpoints returns a copy of the checkpoints recorded so far.
+func (r *RVCheckpointRecorder) Checkpoints() []RVCheckpoint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	checkpoints := make([]RVCheckpoint, len(r.checkpoints))
+	copy(checkpoints, r.checkpoints)
+	return checkpoints
+}
+
+// WriteArtifact stores the recorded checkpoints as a JSON artifact named
+// after the given tag in the report directory, or logs them when no report
+// directory is configured.
+func (r *RVCheckpointRecorder) WriteArtifact(tag string) {
+	data, err := json.MarshalIndent(r.Checkpoints(), "", "  ")
+	if err != nil {
+		Logf("Failed to encode resourceVersion checkpoints: %v", err)
+		return
+	}
+	if TestContext.ReportDir == "" {
+		Logf("resourceVersion checkpoints for %q:\n%s", tag, string(data))
+		return
+	}
+	filePath, err := SpecArtifactPath(fmt.Sprintf("rv_checkpoints_%s.json", tag))
+	if err != nil {
+		Logf("Failed to place resourceVersion checkpoints: %v", err)
+		return
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		Logf("Failed to write resourceVersion checkpoints to %q: %v", filePath, err)
+	}
+}
+
+// ReplayNamespaceWatchFromCheckpoints replays namespace watch events starting
+// at the oldest checkpoint whose resourceVersion is still valid and logs the
+// reconstructed sequence, bounded by the given window. It is a best-effort
+// debugging aid: compacted checkpoints are skipped with a note.
+func ReplayNamespaceWatchFromCheckpoints(c clientset.Interface, checkpoints []RVCheckpoint, window time.Duration) {
+	for _, checkpoint := range checkpoints {
+		w, err := c.CoreV1().Namespaces().Watch(context.TODO(), metav1.ListOptions{ResourceVersion: checkpoint.ResourceVersion})
+		if err != nil {
+			Logf("Checkpoint rv=%s taken at %s is no longer replayable: %v", checkpoint.ResourceVersion, checkpoint.Time.Format(time.RFC3339), err)
+			continue
+		}
+		Logf("Replaying namespace events from checkpoint rv=%s taken at %s", checkpoint.ResourceVersion, checkpoint.Time.Format(time.RFC3339))
+		deadline := time.After(window)
+		count := 0
+	replay:
+		for {
+			select {
+			case event, ok := <-w.ResultChan():
+				if !ok {
+					break replay
+				}
+				if status, isStatus := event.Object.(*metav1.Status); isStatus {
+					// Typically "410 Gone" when the version was compacted
+					// between the Watch call and serving the first event.
+					Logf("Replay from rv=%s ended by the server: %s", checkpoint.ResourceVersion, status.Message)
+					break replay
+				}
+				ns, isNamespace := event.Object.(*v1.Namespace)
+				if !isNamespace {
+					continue
+				}
+				Logf("replay: %s namespace %s phase=%s rv=%s", event.Type, ns.Name, ns.Status.Phase, ns.ResourceVersion)
+				count++
+			case <-deadline:
+				break replay
+			}
+		}
+		w.Stop()
+		Logf("Replayed %d namespace events from checkpoint rv=%s", count, checkpoint.ResourceVersion)
+		return
+	}
+	Logf("No recorded resourceVersion checkpoint was still replayable")
+}
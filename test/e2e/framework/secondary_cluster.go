@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	admissionapi "k8s.io/pod-security-admission/api"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// This file holds the framework's optional second cluster. Tests for
+// controllers that span clusters (federation-style replication, cross-cluster
+// references) target it through f.SecondaryClientSet; everything else is
+// unaffected because the secondary cluster only exists when
+// --secondary-kubeconfig is set.
+
+// SecondaryClusterConfigured reports whether the suite was started with a
+// secondary cluster. Specs that need one should skip when it returns false.
+func SecondaryClusterConfigured() bool {
+	return TestContext.SecondaryKubeConfig != ""
+}
+
+// LoadSecondaryConfig returns a rest config for the secondary cluster. Unlike
+// LoadConfig it has no in-cluster or node-e2e fallbacks: the secondary
+// cluster is only ever described by --secondary-kubeconfig.
+func LoadSecondaryConfig() (*restclient.Config, error) {
+	if !SecondaryClusterConfigured() {
+		return nil, fmt.Errorf("no secondary cluster: --secondary-kubeconfig is not set")
+	}
+	c, err := clientcmd.LoadFromFile(TestContext.SecondaryKubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error loading secondary kubeconfig %s: %v", TestContext.SecondaryKubeConfig, err)
+	}
+	return clientcmd.NewDefaultClientConfig(*c, &clientcmd.ConfigOverrides{}).ClientConfig()
+}
+
+// setupSecondaryCluster creates the secondary clientset for the current spec.
+// Called from BeforeEach when a secondary kubeconfig was configured.
+func (f *Framework) setupSecondaryCluster() {
+	config, err := LoadSecondaryConfig()
+	ExpectNoError(err)
+	config.QPS = f.Options.ClientQPS
+	config.Burst = f.Options.ClientBurst
+	f.secondaryClientConfig = restclient.CopyConfig(config)
+	f.SecondaryClientSet, err = clientset.NewForConfig(config)
+	ExpectNoError(err)
+}
+
+// SecondaryClientConfig returns a copy of the rest config used for the
+// secondary cluster, analogous to ClientConfig for the primary one.
+func (f *Framework) SecondaryClientConfig() *restclient.Config {
+	if f.secondaryClientConfig == nil {
+		return nil
+	}
+	return restclient.CopyConfig(f.secondaryClientConfig)
+}
+
+// CreateSecondaryNamespace creates a test namespace in the secondary cluster
+// and registers it for deletion in AfterEach, mirroring the namespace
+// lifecycle that CreateNamespace provides on the primary cluster.
+func (f *Framework) CreateSecondaryNamespace(baseName string, labels map[string]string) (*v1.Namespace, error) {
+	if f.SecondaryClientSet == nil {
+		return nil, fmt.Errorf("no secondary cluster: --secondary-kubeconfig is not set")
+	}
+	labelsCopy := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		labelsCopy[k] = v
+	}
+	enforceLevel := admissionapi.LevelRestricted
+	if f.NamespacePodSecurityEnforceLevel != "" {
+		enforceLevel = f.NamespacePodSecurityEnforceLevel
+	}
+	labelsCopy[admissionapi.EnforceLevelLabel] = string(enforceLevel)
+
+	ns, err := CreateTestingNSWithContext(f.SpecContext(), baseName, f.SecondaryClientSet, labelsCopy)
+	if ns != nil {
+		f.secondaryNamespacesToDelete = append(f.secondaryNamespacesToDelete, ns)
+	}
+	return ns, err
+}
+
+// deleteSecondaryNamespaces tears down the namespaces created in the
+// secondary cluster during the spec and returns any deletion errors keyed by
+// namespace name. It honours the same delete-namespace flags as the primary
+// cluster's teardown.
+func (f *Framework) deleteSecondaryNamespaces() map[string]error {
+	if f.SecondaryClientSet == nil || len(f.secondaryNamespacesToDelete) == 0 {
+		return nil
+	}
+	if !TestContext.DeleteNamespace || (!TestContext.DeleteNamespaceOnFailure && ginkgo.CurrentSpecReport().Failed()) {
+		Logf("Skipping deletion of %d secondary cluster namespaces per the delete-namespace flags", len(f.secondaryNamespacesToDelete))
+		return nil
+	}
+	errs := map[string]error{}
+	var deleted []string
+	for _, ns := range f.secondaryNamespacesToDelete {
+		if err := f.SecondaryClientSet.CoreV1().Namespaces().Delete(context.TODO(), ns.Name, metav1.DeleteOptions{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs[ns.Name] = err
+			}
+			continue
+		}
+		deleted = append(deleted, ns.Name)
+	}
+	if len(deleted) > 0 {
+		timeout := f.NamespaceDeletionTimeout
+		if timeout == 0 {
+			timeout = f.Timeouts.NamespaceDelete
+		}
+		if err := WaitForNamespacesDeleted(f.SecondaryClientSet, deleted, timeout); err != nil {
+			errs["<wait>"] = fmt.Errorf("failed waiting for secondary cluster namespaces %v to finish terminating: %v", deleted, err)
+		}
+	}
+	return errs
+}
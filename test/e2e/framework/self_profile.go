@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+)
+
+var (
+	selfProfileLock      sync.Mutex
+	lastHeapInuseByLabel = map[string]uint64{}
+)
+
+// WriteHeapProfile snapshots the test binary's own heap into the report
+// directory when --self-profile is set, and logs the heap growth since the
+// previous snapshot with the same label. Bracketing a namespace churn with a
+// pair of snapshots catches memory growth in framework helpers (for example
+// unbounded result accumulation) that only shows on large clusters; the
+// written profiles can be diffed with `go tool pprof -base`.
+func WriteHeapProfile(label, tag string) {
+	if !TestContext.SelfProfile {
+		return
+	}
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	selfProfileLock.Lock()
+	previous, seen := lastHeapInuseByLabel[label]
+	lastHeapInuseByLabel[label] = stats.HeapInuse
+	selfProfileLock.Unlock()
+	if seen {
+		Logf("Self-profile %s (%s): heap in use %d bytes, %+d since the previous %s snapshot",
+			label, tag, stats.HeapInuse, int64(stats.HeapInuse)-int64(previous), label)
+	} else {
+		Logf("Self-profile %s (%s): heap in use %d bytes", label, tag, stats.HeapInuse)
+	}
+
+	if TestContext.ReportDir == "" {
+		return
+	}
+	filePath, err := SpecArtifactPath(fmt.Sprintf("heap_%s_%s.pprof", label, tag))
+	if err != nil {
+		Logf("Failed to place heap profile: %v", err)
+		return
+	}
+	file, err := os.Create(filePath)
+	if err != nil {
+		Logf("Failed to create heap profile %q: %v", filePath, err)
+		return
+	}
+	defer file.Close()
+	if err := pprof.Lookup("heap").WriteTo(file, 0); err != nil {
+		Logf("Failed to write heap profile %q: %v", filePath, err)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+// BulkNamespaceOpsResult holds the measured latencies of a bulk namespace
+// workload run with a particular client content type.
+type BulkNamespaceOpsResult struct {
+	// ContentType is the client serialization used for the run.
+	ContentType string
+	// Count is the number of namespaces created and deleted.
+	Count int
+	// CreateDuration is the total wall time spent issuing namespace creates.
+	CreateDuration time.Duration
+	// ListDuration is the total wall time spent listing all namespaces.
+	ListDuration time.Duration
+	// DeleteDuration is the total wall time spent issuing namespace deletes.
+	DeleteDuration time.Duration
+}
+
+// MeasureBulkNamespaceOperations runs a create/list/delete workload of count
+// namespaces against a client configured with the given content type
+// (e.g. runtime.ContentTypeJSON or runtime.ContentTypeProtobuf) and returns the
+// measured latencies. The created namespaces are deleted before returning, but
+// the caller should pick a prefix that cannot collide with test content.
+func MeasureBulkNamespaceOperations(config *restclient.Config, contentType string, count int, prefix string) (*BulkNamespaceOpsResult, error) {
+	config = restclient.CopyConfig(config)
+	config.ContentType = contentType
+	config.AcceptContentTypes = contentType
+	c, err := clientset.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("building client with content type %q: %v", contentType, err)
+	}
+
+	result := &BulkNamespaceOpsResult{ContentType: contentType, Count: count}
+	names := make([]string, 0, count)
+
+	start := time.Now()
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("%s-%d-%s", prefix, i, RandomSuffix())
+		_, err := c.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"e2e-run": string(RunID)},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("creating namespace %s: %v", name, err)
+		}
+		names = append(names, name)
+	}
+	result.CreateDuration = time.Since(start)
+
+	start = time.Now()
+	if _, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{}); err != nil {
+		return nil, fmt.Errorf("listing namespaces: %v", err)
+	}
+	result.ListDuration = time.Since(start)
+
+	start = time.Now()
+	for _, name := range names {
+		if err := c.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+			return nil, fmt.Errorf("deleting namespace %s: %v", name, err)
+		}
+	}
+	result.DeleteDuration = time.Since(start)
+
+	if err := WaitForNamespacesDeleted(c, names, DefaultNamespaceDeletionTimeout); err != nil {
+		return nil, fmt.Errorf("waiting for benchmark namespaces to vanish: %v", err)
+	}
+	return result, nil
+}
+
+// CompareNamespaceSerializationPerformance runs the bulk namespace workload
+// once with JSON and once with protobuf client serialization and logs the
+// latency deltas. It is a tool for justifying the framework's client
+// serialization defaults rather than a pass/fail assertion.
+func CompareNamespaceSerializationPerformance(config *restclient.Config, count int, prefix string) ([]*BulkNamespaceOpsResult, error) {
+	var results []*BulkNamespaceOpsResult
+	for _, contentType := range []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf} {
+		result, err := MeasureBulkNamespaceOperations(config, contentType, count, prefix)
+		if err != nil {
+			return results, err
+		}
+		Logf("Bulk namespace ops with %s (%d namespaces): create %v, list %v, delete %v",
+			result.ContentType, result.Count, result.CreateDuration, result.ListDuration, result.DeleteDuration)
+		results = append(results, result)
+	}
+	if len(results) == 2 {
+		Logf("Serialization deltas (json - protobuf): create %v, list %v, delete %v",
+			results[0].CreateDuration-results[1].CreateDuration,
+			results[0].ListDuration-results[1].ListDuration,
+			results[0].DeleteDuration-results[1].DeleteDuration)
+	}
+	return results, nil
+}
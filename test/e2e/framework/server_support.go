@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// serverFeatureMinVersion maps named server behaviors that newer specs lean
+// on to the minimum server minor version providing them. The table only
+// needs entries for behaviors this suite actually gates on; everything else
+// goes through a group/version probe instead.
+var serverFeatureMinVersion = map[string]*utilversion.Version{
+	// Server-side apply GA; the SSA-specific namespace specs assert on
+	// managedFields semantics that earlier servers do not provide.
+	"ServerSideApply": utilversion.MustParseSemantic("1.16.0"),
+	// Detailed NamespaceDeletionDiscoveryFailure/ContentFailure/
+	// ContentRemaining conditions on terminating namespaces.
+	"NamespaceConditions": utilversion.MustParseSemantic("1.19.0"),
+}
+
+var (
+	serverSupportLock  sync.Mutex
+	serverSupportCache = map[string]bool{}
+)
+
+// ServerSupports reports whether the cluster's apiserver provides the named
+// behavior, so specs written against current servers can degrade gracefully
+// on version-skewed clusters instead of failing. The argument is either a
+// feature name from the compatibility table ("ServerSideApply") or a served
+// group/version ("admissionregistration.k8s.io/v1alpha1"), which is probed
+// through discovery. Unknown features and probe errors report true: a skew
+// shim must not suppress real signal on the clusters it was not written for.
+func (f *Framework) ServerSupports(featureOrGV string) bool {
+	serverSupportLock.Lock()
+	supported, cached := serverSupportCache[featureOrGV]
+	serverSupportLock.Unlock()
+	if cached {
+		return supported
+	}
+	supported = f.serverSupports(featureOrGV)
+	serverSupportLock.Lock()
+	serverSupportCache[featureOrGV] = supported
+	serverSupportLock.Unlock()
+	return supported
+}
+
+func (f *Framework) serverSupports(featureOrGV string) bool {
+	if strings.Contains(featureOrGV, "/") {
+		_, err := f.ClientSet.Discovery().ServerResourcesForGroupVersion(featureOrGV)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		if err != nil {
+			Logf("Cannot probe API %s (%v); assuming it is supported", featureOrGV, err)
+		}
+		return true
+	}
+
+	minVersion, known := serverFeatureMinVersion[featureOrGV]
+	if !known {
+		Logf("No compatibility entry for server feature %q; assuming it is supported", featureOrGV)
+		return true
+	}
+	versionInfo, err := f.ClientSet.Discovery().ServerVersion()
+	if err != nil {
+		Logf("Cannot read server version to check feature %q (%v); assuming it is supported", featureOrGV, err)
+		return true
+	}
+	serverVersion, err := utilversion.ParseGeneric(versionInfo.GitVersion)
+	if err != nil {
+		Logf("Cannot parse server version %q to check feature %q (%v); assuming it is supported", versionInfo.GitVersion, featureOrGV, err)
+		return true
+	}
+	return serverVersion.AtLeast(minVersion)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/test/e2e/framework"
+	imageutils "k8s.io/kubernetes/test/utils/image"
+)
+
+// ServedService is a Service together with a running backend pod. Unlike a
+// bare Service object it can be probed, so tests can distinguish "the Service
+// exists in the API" from "the Service actually serves traffic".
+type ServedService struct {
+	Client    clientset.Interface
+	Namespace string
+	Name      string
+	// PodName is the single netexec backend pod behind the service.
+	PodName string
+}
+
+// CreateServedService creates a Service, a single agnhost netexec backend pod
+// behind it, and waits until the service has a ready endpoint. The returned
+// handle probes the service through the API server proxy, so it works from
+// outside the cluster network.
+func CreateServedService(c clientset.Interface, namespace, name string) (*ServedService, error) {
+	podName := name + "-backend"
+	selector := map[string]string{"served-service": name}
+	var grace int64 = 3
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   podName,
+			Labels: selector,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:  "netexec",
+					Image: imageutils.GetE2EImage(imageutils.Agnhost),
+					Args:  []string{"netexec", "--http-port=80"},
+					ReadinessProbe: &v1.Probe{
+						PeriodSeconds: 3,
+						ProbeHandler: v1.ProbeHandler{
+							HTTPGet: &v1.HTTPGetAction{
+								Port: intstr.FromInt(80),
+								Path: "/hostName",
+							},
+						},
+					},
+				},
+			},
+			TerminationGracePeriodSeconds: &grace,
+		},
+	}
+	if _, err := c.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create backend pod %q: %v", podName, err)
+	}
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1.ServiceSpec{
+			Selector: selector,
+			Ports: []v1.ServicePort{{
+				Port:       80,
+				TargetPort: intstr.FromInt(80),
+				Protocol:   v1.ProtocolTCP,
+			}},
+		},
+	}
+	if _, err := c.CoreV1().Services(namespace).Create(context.TODO(), service, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create service %q: %v", name, err)
+	}
+
+	if err := framework.WaitForServiceEndpointsNum(c, namespace, name, 1, framework.Poll, framework.ServiceStartTimeout); err != nil {
+		return nil, fmt.Errorf("service %q never got a ready endpoint: %v", name, err)
+	}
+	return &ServedService{Client: c, Namespace: namespace, Name: name, PodName: podName}, nil
+}
+
+// Probe issues one request to the service through the API server proxy and
+// returns an error if the backend did not answer.
+func (s *ServedService) Probe() error {
+	body, err := s.Client.CoreV1().RESTClient().Get().
+		Namespace(s.Namespace).
+		Resource("services").
+		Name(fmt.Sprintf("%s:80", s.Name)).
+		SubResource("proxy").
+		Suffix("hostName").
+		Do(context.TODO()).Raw()
+	if err != nil {
+		return fmt.Errorf("probe of service %s/%s failed: %v", s.Namespace, s.Name, err)
+	}
+	if len(body) == 0 {
+		return fmt.Errorf("probe of service %s/%s returned an empty response", s.Namespace, s.Name)
+	}
+	return nil
+}
+
+// WaitUntilServing polls Probe until the service answers or the timeout
+// expires. Endpoint readiness does not guarantee the proxy path works yet, so
+// callers should use this before treating the service as functioning.
+func (s *ServedService) WaitUntilServing(timeout time.Duration) error {
+	var lastErr error
+	err := wait.PollImmediate(framework.Poll, timeout, func() (bool, error) {
+		if lastErr = s.Probe(); lastErr != nil {
+			framework.Logf("Service %s/%s not serving yet: %v", s.Namespace, s.Name, lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("service %s/%s never started serving: %v (last probe error: %v)", s.Namespace, s.Name, err, lastErr)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	saProvisioningLatencies     []time.Duration
+	saProvisioningLatenciesLock sync.Mutex
+)
+
+// recordServiceAccountProvisioningLatency adds one measured provisioning
+// latency to the suite-wide statistics and enforces the flagged threshold, if
+// one was configured.
+func recordServiceAccountProvisioningLatency(namespace string, latency time.Duration) {
+	saProvisioningLatenciesLock.Lock()
+	saProvisioningLatencies = append(saProvisioningLatencies, latency)
+	saProvisioningLatenciesLock.Unlock()
+	if threshold := TestContext.ServiceAccountProvisioningThreshold; threshold > 0 && latency > threshold {
+		Failf("default service account in namespace %s took %v to be provisioned, exceeding the threshold of %v", namespace, latency, threshold)
+	}
+}
+
+// ServiceAccountProvisioningLatencies returns a copy of all default service
+// account provisioning latencies measured so far in this run.
+func ServiceAccountProvisioningLatencies() []time.Duration {
+	saProvisioningLatenciesLock.Lock()
+	defer saProvisioningLatenciesLock.Unlock()
+	latencies := make([]time.Duration, len(saProvisioningLatencies))
+	copy(latencies, saProvisioningLatencies)
+	return latencies
+}
+
+// LatencyPercentile returns the given percentile (1-100) of the latencies.
+// A zero duration is returned for an empty slice.
+func LatencyPercentile(latencies []time.Duration, percentile int) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := (percentile*len(sorted)+99)/100 - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
+
+// LogServiceAccountProvisioningStats summarizes all measured service account
+// provisioning latencies; intended to be called once at the end of the suite.
+func LogServiceAccountProvisioningStats() {
+	latencies := ServiceAccountProvisioningLatencies()
+	if len(latencies) == 0 {
+		return
+	}
+	Logf("Default service account provisioning latency over %d namespace creations: P50 %v, P90 %v, P99 %v, max %v",
+		len(latencies),
+		LatencyPercentile(latencies, 50),
+		LatencyPercentile(latencies, 90),
+		LatencyPercentile(latencies, 99),
+		LatencyPercentile(latencies, 100))
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// SIG packages historically each hand-rolled an identical SIGDescribe helper
+// around ginkgo.Describe. Building the helper here instead keeps the
+// labelling in one place and records which SIGs a binary compiled in, so
+// focused suite binaries (a namespace-lifecycle-only presubmit image, for
+// example) can assemble a subset of SIG packages with their own flag
+// registration and still report what they contain.
+
+var (
+	sigsLock       sync.Mutex
+	registeredSIGs = map[string]bool{}
+)
+
+// SIGDescribe returns the ginkgo.Describe wrapper for one SIG: every
+// container created through it is prefixed with the SIG label. SIG packages
+// declare their helper as
+//
+//	var SIGDescribe = framework.SIGDescribe("sig-foo")
+//
+// and use it exactly like before. The sig is registered on the first call,
+// i.e. at package init when specs are built, so RegisteredSIGs reflects what
+// the running binary actually compiled in.
+func SIGDescribe(sig string) func(text string, body func()) bool {
+	return func(text string, body func()) bool {
+		sigsLock.Lock()
+		registeredSIGs[sig] = true
+		sigsLock.Unlock()
+		return ginkgo.Describe("["+sig+"] "+text, body)
+	}
+}
+
+// RegisteredSIGs returns the sorted names of all SIGs that contributed specs
+// to this binary.
+func RegisteredSIGs() []string {
+	sigsLock.Lock()
+	defer sigsLock.Unlock()
+	sigs := make([]string, 0, len(registeredSIGs))
+	for sig := range registeredSIGs {
+		sigs = append(sigs, sig)
+	}
+	sort.Strings(sigs)
+	return sigs
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"k8s.io/kubernetes/test/e2e/framework"
+)
+
+// Capability is one requirement a spec declares against the live cluster.
+// Unlike a [Feature:...] tag, each capability is verified at runtime, so a
+// cluster missing it skips the spec with a precise reason instead of failing
+// it somewhere in the middle.
+type Capability struct {
+	// GroupVersion requires the API group/version to be served, e.g.
+	// "admissionregistration.k8s.io/v1".
+	GroupVersion string
+	// Resource additionally requires this resource to exist within
+	// GroupVersion.
+	Resource string
+	// FeatureGate requires the named feature gate to be enabled. It is
+	// verified through the apiserver's kubernetes_feature_enabled metric
+	// where the server exposes it; servers too old to report feature gates
+	// let the spec run, since absence of the metric proves nothing.
+	FeatureGate string
+	// NodeOS requires at least one node labeled with this operating system
+	// (the kubernetes.io/os label, e.g. "linux", "windows").
+	NodeOS string
+	// NodeArch requires at least one node labeled with this architecture
+	// (the kubernetes.io/arch label, e.g. "amd64").
+	NodeArch string
+}
+
+// RequireCapability verifies every given capability against the cluster and
+// skips the spec naming the first one that is missing. Verification errors
+// other than a clean "not present" let the spec run: a flaky discovery call
+// must not hide real signal behind a skip.
+func RequireCapability(ctx context.Context, c clientset.Interface, caps ...Capability) {
+	for _, capability := range caps {
+		if reason := missingCapability(ctx, c, capability); reason != "" {
+			skipInternalf(1, "cluster is missing a required capability: %s", reason)
+		}
+	}
+}
+
+// missingCapability returns a human-readable reason when the capability is
+// verifiably absent, and "" otherwise.
+func missingCapability(ctx context.Context, c clientset.Interface, capability Capability) string {
+	if capability.GroupVersion != "" {
+		resources, err := c.Discovery().ServerResourcesForGroupVersion(capability.GroupVersion)
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("API %s is not served", capability.GroupVersion)
+		}
+		if err != nil {
+			framework.Logf("Cannot verify API %s (%v); letting the spec run", capability.GroupVersion, err)
+		} else if capability.Resource != "" && !hasResource(resources, capability.Resource) {
+			return fmt.Sprintf("API %s does not serve resource %q", capability.GroupVersion, capability.Resource)
+		}
+	}
+	if capability.FeatureGate != "" {
+		if enabled, known := featureGateEnabled(ctx, c, capability.FeatureGate); known && !enabled {
+			return fmt.Sprintf("feature gate %s is disabled", capability.FeatureGate)
+		}
+	}
+	if capability.NodeOS != "" {
+		if reason := missingNodeLabel(ctx, c, "kubernetes.io/os", capability.NodeOS); reason != "" {
+			return reason
+		}
+	}
+	if capability.NodeArch != "" {
+		if reason := missingNodeLabel(ctx, c, "kubernetes.io/arch", capability.NodeArch); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+func hasResource(resources *metav1.APIResourceList, name string) bool {
+	for _, resource := range resources.APIResources {
+		if resource.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// featureGateEnabled introspects the apiserver's kubernetes_feature_enabled
+// metric. The second return value reports whether the gate's state could be
+// determined at all; servers without the metric return (false, false).
+func featureGateEnabled(ctx context.Context, c clientset.Interface, gate string) (enabled, known bool) {
+	body, err := c.CoreV1().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		framework.Logf("Cannot read apiserver metrics to verify feature gate %s (%v); letting the spec run", gate, err)
+		return false, false
+	}
+	needle := fmt.Sprintf(`kubernetes_feature_enabled{name=%q`, gate)
+	for _, line := range strings.Split(string(body), "\n") {
+		if !strings.HasPrefix(line, needle) {
+			continue
+		}
+		return strings.HasSuffix(strings.TrimSpace(line), " 1"), true
+	}
+	framework.Logf("Apiserver does not report feature gate %s; letting the spec run", gate)
+	return false, false
+}
+
+func missingNodeLabel(ctx context.Context, c clientset.Interface, label, value string) string {
+	nodes, err := c.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", label, value),
+	})
+	if err != nil {
+		framework.Logf("Cannot verify nodes with %s=%s (%v); letting the spec run", label, value, err)
+		return ""
+	}
+	if len(nodes.Items) == 0 {
+		return fmt.Sprintf("no node with %s=%s", label, value)
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipper
+
+import (
+	"k8s.io/kubernetes/test/e2e/framework"
+)
+
+// SkipUnlessNamespaceLifecycle skips specs that must create or delete real
+// namespaces when the suite runs against a pool of pre-provisioned
+// namespaces (--existing-namespaces). In that mode f.CreateNamespace leases
+// from the pool and nothing is ever deleted, so specs asserting on namespace
+// creation or termination itself cannot produce a meaningful result.
+func SkipUnlessNamespaceLifecycle() {
+	if framework.UsingExistingNamespaces() {
+		skipInternalf(1, "spec requires namespace create/delete, but the suite leases pre-provisioned namespaces (--existing-namespaces)")
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skipper
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"k8s.io/kubernetes/test/e2e/framework"
+)
+
+// ResourceBudget declares the cluster capacity a spec is about to consume, so
+// heavyweight [Serial] specs can be skipped up front on clusters that cannot
+// support them instead of failing at their deadline.
+type ResourceBudget struct {
+	// Namespaces is how many namespaces the spec churns through at once.
+	Namespaces int
+	// QPS is the sustained request rate the spec generates against the
+	// apiserver.
+	QPS float64
+}
+
+// Per-node capacity allowances. These are deliberately generous heuristics
+// calibrated against the default CI clusters the serial lane runs on, where
+// the 100-namespace soak passes comfortably on five nodes; their only job is
+// to catch clusters that are off by an order of magnitude, such as a
+// single-node dev cluster.
+const (
+	budgetNamespacesPerNode = 25
+	budgetQPSPerNode        = 50
+)
+
+// SkipUnlessClusterCanSupport skips the spec when its declared budget exceeds
+// what the cluster can be expected to absorb. The estimate keys off the node
+// count, which tracks control plane sizing on the providers the serial lane
+// runs on; when the node count cannot be determined, the spec is allowed to
+// run rather than skipped on guesswork.
+func SkipUnlessClusterCanSupport(c clientset.Interface, budget ResourceBudget) {
+	nodeCount := framework.TestContext.CloudConfig.NumNodes
+	if nodes, err := c.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{}); err == nil && len(nodes.Items) > 0 {
+		nodeCount = len(nodes.Items)
+	}
+	if nodeCount <= 0 {
+		framework.Logf("Cannot estimate cluster capacity (unknown node count); letting the spec run")
+		return
+	}
+	if max := nodeCount * budgetNamespacesPerNode; budget.Namespaces > max {
+		skipInternalf(1, "Spec declares a budget of %d namespaces, but a %d-node cluster is only expected to absorb %d",
+			budget.Namespaces, nodeCount, max)
+	}
+	if max := float64(nodeCount) * budgetQPSPerNode; budget.QPS > max {
+		skipInternalf(1, "Spec declares a budget of %.0f QPS, but a %d-node cluster is only expected to absorb %.0f",
+			budget.QPS, nodeCount, max)
+	}
+}
@@ -222,6 +222,22 @@ func SkipUnlessProviderIs(supportedProviders ...string) {
 	}
 }
 
+// SkipUnlessProviderHasNodes skips specs that need to run pods when the
+// current provider declares node-less clusters (virtual control planes).
+func SkipUnlessProviderHasNodes() {
+	if !framework.ProviderHasNodes() {
+		skipInternalf(1, "provider %q serves a control plane without nodes; this spec needs to run pods", framework.TestContext.Provider)
+	}
+}
+
+// SkipUnlessProviderHasLoadBalancers skips specs that need external load
+// balancers when the current provider declares them unavailable.
+func SkipUnlessProviderHasLoadBalancers() {
+	if !framework.ProviderHasLoadBalancers() {
+		skipInternalf(1, "provider %q provisions no external load balancers", framework.TestContext.Provider)
+	}
+}
+
 // SkipUnlessMultizone skips if the cluster does not have multizone.
 func SkipUnlessMultizone(c clientset.Interface) {
 	zones, err := e2enode.GetClusterZones(c)
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// Verdict modes for SLO-based specs, selected with --slo-verdict. CI jobs
+// that only want trend data run "score"; jobs gating merges run "fail".
+const (
+	// SLOVerdictFail fails the spec on a violated objective (the default).
+	SLOVerdictFail = "fail"
+	// SLOVerdictSoft reports a violated objective in the spec artifacts but
+	// lets the spec pass, for jobs measuring clusters they do not gate.
+	SLOVerdictSoft = "soft"
+	// SLOVerdictScore never judges at all; it only writes the measured value,
+	// the objective and their ratio as a JSON artifact.
+	SLOVerdictScore = "score"
+)
+
+// SLOResult is one measured objective from a spec: how much of something was
+// observed against how much the objective allows.
+type SLOResult struct {
+	// Name identifies the objective in logs and artifact file names.
+	Name string `json:"name"`
+	// Measured is the observed value, in Unit.
+	Measured float64 `json:"measured"`
+	// Objective is the largest Measured value that still meets the objective.
+	Objective float64 `json:"objective"`
+	Unit      string  `json:"unit"`
+	// Score is Measured normalized against Objective: at most 1.0 meets the
+	// objective. Filled in by EnforceSLO.
+	Score float64 `json:"score"`
+	// Description is the human-readable failure message used when the
+	// verdict mode judges the result.
+	Description string `json:"description"`
+}
+
+// violated reports whether the measurement misses the objective.
+func (r SLOResult) violated() bool {
+	return r.Measured > r.Objective
+}
+
+// EnforceSLO applies the run's verdict mode to a measured objective. In the
+// default "fail" mode a violation fails the spec; "soft" records the
+// violation as a report entry but lets the spec pass; "score" writes the
+// result as a JSON artifact without judging it. Selecting the mode per CI
+// job replaces the downstream fork-and-edit of SLO thresholds.
+func EnforceSLO(result SLOResult) {
+	if result.Objective > 0 {
+		result.Score = result.Measured / result.Objective
+	}
+	Logf("SLO %s: measured %v %s against an objective of %v (score %.3f)",
+		result.Name, result.Measured, result.Unit, result.Objective, result.Score)
+
+	mode := TestContext.SLOVerdict
+	if mode == "" {
+		mode = SLOVerdictFail
+	}
+	switch mode {
+	case SLOVerdictFail:
+		if result.violated() {
+			Failf("SLO %s violated: %s", result.Name, result.Description)
+		}
+	case SLOVerdictSoft:
+		if result.violated() {
+			Logf("WARNING: SLO %s violated (soft verdict): %s", result.Name, result.Description)
+			ginkgo.AddReportEntry("slo-soft-failure-"+result.Name, result.Description)
+		}
+	case SLOVerdictScore:
+		writeSLOScoreArtifact(result)
+	default:
+		Failf("unknown --slo-verdict mode %q", mode)
+	}
+}
+
+// writeSLOScoreArtifact persists the scored result to the report directory,
+// falling back to the log when no directory is configured.
+func writeSLOScoreArtifact(result SLOResult) {
+	content := PrettyPrintJSON(result)
+	if TestContext.ReportDir == "" {
+		Logf("SLO score for %s: %s", result.Name, content)
+		return
+	}
+	filePath, err := SpecArtifactPath(fmt.Sprintf("slo_%s.json", result.Name))
+	if err != nil {
+		Logf("Failed to place SLO score artifact: %v", err)
+		return
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		Logf("Failed to write SLO score artifact %q: %v", filePath, err)
+	}
+}
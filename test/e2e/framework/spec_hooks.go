@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SpecHook attaches downstream per-spec setup and cleanup to every spec that
+// uses the framework. Distributions embedding this e2e package use it for
+// concerns like tenant provisioning that must bracket each spec, without
+// patching the tests themselves.
+//
+// Ordering guarantees:
+//   - BeforeSpec functions run at the end of the framework's BeforeEach, in
+//     registration order, so the client and the spec's namespace already
+//     exist when they are called.
+//   - AfterSpec functions run at the start of the framework's AfterEach, in
+//     reverse registration order, before the framework's own cleanup (which
+//     includes namespace deletion) begins.
+//
+// Unlike Framework.AddAfterEach, whose execution order is intentionally
+// random, hooks are ordered so that one hook can build on another's setup.
+type SpecHook struct {
+	// Name identifies the hook in panics and logs; it must be non-empty and
+	// unique across all registered hooks.
+	Name string
+	// BeforeSpec may be nil for cleanup-only hooks.
+	BeforeSpec func(f *Framework)
+	// AfterSpec may be nil for setup-only hooks. failed reports whether the
+	// spec (including its BeforeEach) has failed.
+	AfterSpec func(f *Framework, failed bool)
+}
+
+var (
+	specHooksLock sync.Mutex
+	specHooks     []SpecHook
+)
+
+// RegisterSpecHook adds a hook to every spec run through the framework. It is
+// expected to be called before the suite starts, typically from an init
+// function of the embedding distribution. Registering a nameless hook or the
+// same name twice is a coding error and panics.
+func RegisterSpecHook(hook SpecHook) {
+	specHooksLock.Lock()
+	defer specHooksLock.Unlock()
+	if hook.Name == "" {
+		panic("spec hooks must be named")
+	}
+	for _, existing := range specHooks {
+		if existing.Name == hook.Name {
+			panic(fmt.Sprintf("spec hook %q is already registered", hook.Name))
+		}
+	}
+	specHooks = append(specHooks, hook)
+}
+
+// runSpecHooksBefore invokes the BeforeSpec functions of all registered hooks
+// in registration order.
+func runSpecHooksBefore(f *Framework) {
+	for _, hook := range registeredSpecHooks() {
+		if hook.BeforeSpec != nil {
+			hook.BeforeSpec(f)
+		}
+	}
+}
+
+// runSpecHooksAfter invokes the AfterSpec functions of all registered hooks
+// in reverse registration order.
+func runSpecHooksAfter(f *Framework, failed bool) {
+	hooks := registeredSpecHooks()
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if hooks[i].AfterSpec != nil {
+			hooks[i].AfterSpec(f, failed)
+		}
+	}
+}
+
+// registeredSpecHooks returns a snapshot of the hook registry so that hooks
+// registered mid-spec (a coding error, but harmless) cannot corrupt an
+// in-flight iteration.
+func registeredSpecHooks() []SpecHook {
+	specHooksLock.Lock()
+	defer specHooksLock.Unlock()
+	hooks := make([]SpecHook, len(specHooks))
+	copy(hooks, specHooks)
+	return hooks
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"reflect"
+	"testing"
+)
+
+// resetSpecHooks empties the hook registry for the duration of a test.
+func resetSpecHooks(t *testing.T) {
+	t.Helper()
+	specHooksLock.Lock()
+	saved := specHooks
+	specHooks = nil
+	specHooksLock.Unlock()
+	t.Cleanup(func() {
+		specHooksLock.Lock()
+		specHooks = saved
+		specHooksLock.Unlock()
+	})
+}
+
+func TestSpecHookOrdering(t *testing.T) {
+	resetSpecHooks(t)
+
+	var calls []string
+	for _, name := range []string{"first", "second", "third"} {
+		name := name
+		RegisterSpecHook(SpecHook{
+			Name:       name,
+			BeforeSpec: func(f *Framework) { calls = append(calls, "before-"+name) },
+			AfterSpec:  func(f *Framework, failed bool) { calls = append(calls, "after-"+name) },
+		})
+	}
+	// A cleanup-only hook must not break the before pass, nor a setup-only
+	// hook the after pass.
+	RegisterSpecHook(SpecHook{
+		Name:      "cleanup-only",
+		AfterSpec: func(f *Framework, failed bool) { calls = append(calls, "after-cleanup-only") },
+	})
+
+	f := &Framework{}
+	runSpecHooksBefore(f)
+	runSpecHooksAfter(f, false)
+
+	want := []string{
+		"before-first", "before-second", "before-third",
+		"after-cleanup-only", "after-third", "after-second", "after-first",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("hook calls = %v, want %v", calls, want)
+	}
+}
+
+func TestSpecHookFailedFlag(t *testing.T) {
+	resetSpecHooks(t)
+
+	var got []bool
+	RegisterSpecHook(SpecHook{
+		Name:      "failure-observer",
+		AfterSpec: func(f *Framework, failed bool) { got = append(got, failed) },
+	})
+
+	f := &Framework{}
+	runSpecHooksAfter(f, false)
+	runSpecHooksAfter(f, true)
+	if want := []bool{false, true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("observed failed flags %v, want %v", got, want)
+	}
+}
+
+func TestRegisterSpecHookValidation(t *testing.T) {
+	resetSpecHooks(t)
+
+	expectPanic := func(what string, register func()) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("registering %s did not panic", what)
+			}
+		}()
+		register()
+	}
+
+	RegisterSpecHook(SpecHook{Name: "taken"})
+	expectPanic("a duplicate name", func() { RegisterSpecHook(SpecHook{Name: "taken"}) })
+	expectPanic("a nameless hook", func() { RegisterSpecHook(SpecHook{}) })
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+
+	e2epod "k8s.io/kubernetes/test/e2e/framework/pod"
+)
+
+func init() {
+	// e2epod cannot import this package; it reports its wait times through a
+	// callback instead.
+	e2epod.SetWaitTimeRecorder(RecordFrameworkWait)
+}
+
+// SpecTimingSummary records where one spec spent its time inside framework
+// helpers and how much cluster state it built up. SIG Testing uses the
+// per-spec files to find which specs dominate suite wall time; the
+// wait categories separate specs that are slow themselves from specs that
+// mostly sit in namespace or pod provisioning.
+type SpecTimingSummary struct {
+	Spec           string  `json:"spec"`
+	RunTimeSeconds float64 `json:"runTimeSeconds"`
+	// WaitSeconds is wall time spent inside framework waits, by category
+	// ("namespace-creation", "namespace-initialization",
+	// "namespace-deletion", "pod-condition", ...).
+	WaitSeconds      map[string]float64 `json:"waitSeconds"`
+	TotalWaitSeconds float64            `json:"totalWaitSeconds"`
+	// NamespacesCreated counts namespaces the framework created (or leased)
+	// for the spec.
+	NamespacesCreated int `json:"namespacesCreated"`
+	// ObjectsCreated counts successful POST requests issued through the
+	// framework clients, approximating the objects the spec created.
+	ObjectsCreated int `json:"objectsCreated"`
+}
+
+var (
+	currentSpecTimingLock sync.Mutex
+	currentSpecTiming     *SpecTimingSummary
+)
+
+// installSpecTiming starts a fresh timing summary for the spec about to run,
+// unless another framework in the same spec already installed one.
+func installSpecTiming() {
+	currentSpecTimingLock.Lock()
+	defer currentSpecTimingLock.Unlock()
+	if currentSpecTiming == nil {
+		currentSpecTiming = &SpecTimingSummary{WaitSeconds: map[string]float64{}}
+	}
+}
+
+// RecordFrameworkWait attributes wall time spent in a framework wait helper
+// to the current spec's timing summary under the given category. It is a
+// no-op outside of a running spec.
+func RecordFrameworkWait(category string, elapsed time.Duration) {
+	currentSpecTimingLock.Lock()
+	defer currentSpecTimingLock.Unlock()
+	if currentSpecTiming == nil {
+		return
+	}
+	currentSpecTiming.WaitSeconds[category] += elapsed.Seconds()
+	currentSpecTiming.TotalWaitSeconds += elapsed.Seconds()
+}
+
+func recordSpecNamespaceCreated() {
+	currentSpecTimingLock.Lock()
+	defer currentSpecTimingLock.Unlock()
+	if currentSpecTiming != nil {
+		currentSpecTiming.NamespacesCreated++
+	}
+}
+
+func recordSpecObjectCreated() {
+	currentSpecTimingLock.Lock()
+	defer currentSpecTimingLock.Unlock()
+	if currentSpecTiming != nil {
+		currentSpecTiming.ObjectsCreated++
+	}
+}
+
+// writeSpecTimingSummary is registered as a ginkgo ReportAfterEach node; it
+// writes the finished spec's timing summary to
+// <report-dir>/spec_timings/<spec>.json and resets the collection for the
+// next spec. Without a report directory the summary only appears in the log.
+func writeSpecTimingSummary(report ginkgo.SpecReport) {
+	currentSpecTimingLock.Lock()
+	summary := currentSpecTiming
+	currentSpecTiming = nil
+	currentSpecTimingLock.Unlock()
+	if summary == nil {
+		return
+	}
+	summary.Spec = report.FullText()
+	summary.RunTimeSeconds = report.RunTime.Seconds()
+	enrichSpec(summary.Spec, func(enrichment *SpecEnrichment) {
+		enrichment.Timing = summary
+	})
+
+	content := PrettyPrintJSON(summary)
+	if TestContext.ReportDir == "" {
+		Logf("Spec timing summary: %s", content)
+		return
+	}
+	dir := filepath.Join(TestContext.ReportDir, "spec_timings")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		Logf("Failed to create spec timing directory %q: %v", dir, err)
+		return
+	}
+	filePath := filepath.Join(dir, sanitizeSpecLogName(summary.Spec)+".json")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		Logf("Failed to write spec timing summary %q: %v", filePath, err)
+	}
+}
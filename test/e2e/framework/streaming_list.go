@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// Newer apiservers can serve the initial state of a list as a watch stream
+// (the "streaming list" / WatchList protocol) and negotiate CBOR payloads,
+// both of which take pressure off the hot list paths the churn tests hammer.
+// The vendored client predates both, so the helpers here speak the protocol
+// at the wire level, detect support by observing the server's behavior, and
+// fall back to a regular chunked LIST where the capability is absent.
+
+// initialEventsEndAnnotation marks the bookmark event that ends the
+// initial-events phase of a streaming list.
+const initialEventsEndAnnotation = "k8s.io/initial-events-end"
+
+// ListNamespacesViaWatchList lists namespaces through the streaming list
+// protocol: a watch with sendInitialEvents that replays the current state as
+// ADDED events and fences it with an annotated bookmark. The returned bool
+// reports whether the streaming path actually served the result; servers
+// that do not implement the protocol ignore the extra parameters and never
+// send the fencing bookmark, in which case the helper falls back to a
+// chunked LIST after the timeout.
+func ListNamespacesViaWatchList(c clientset.Interface, labelSelector string, timeout time.Duration) ([]v1.Namespace, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req := c.CoreV1().RESTClient().Get().
+		AbsPath("/api/v1/namespaces").
+		Param("watch", "true").
+		Param("sendInitialEvents", "true").
+		Param("resourceVersionMatch", "NotOlderThan").
+		Param("allowWatchBookmarks", "true")
+	if labelSelector != "" {
+		req = req.Param("labelSelector", labelSelector)
+	}
+	watcher, err := req.Watch(ctx)
+	if err != nil {
+		// Strict servers may reject the unknown parameters outright; that is
+		// just another way of saying the capability is absent.
+		if apierrors.IsBadRequest(err) || apierrors.IsInvalid(err) {
+			Logf("Streaming list rejected by the apiserver (%v), falling back to a chunked LIST", err)
+			return listNamespacesFallback(c, labelSelector)
+		}
+		return nil, false, fmt.Errorf("failed to open the streaming list watch: %v", err)
+	}
+	defer watcher.Stop()
+
+	byName := map[string]v1.Namespace{}
+	for {
+		select {
+		case <-ctx.Done():
+			Logf("Streaming list saw no initial-events-end bookmark within %v, falling back to a chunked LIST", timeout)
+			return listNamespacesFallback(c, labelSelector)
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				Logf("Streaming list watch closed before the initial-events-end bookmark, falling back to a chunked LIST")
+				return listNamespacesFallback(c, labelSelector)
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				ns, ok := event.Object.(*v1.Namespace)
+				if !ok {
+					return nil, false, fmt.Errorf("streaming list delivered a %T instead of a namespace", event.Object)
+				}
+				byName[ns.Name] = *ns
+			case watch.Deleted:
+				ns, ok := event.Object.(*v1.Namespace)
+				if !ok {
+					return nil, false, fmt.Errorf("streaming list delivered a %T instead of a namespace", event.Object)
+				}
+				delete(byName, ns.Name)
+			case watch.Bookmark:
+				ns, ok := event.Object.(*v1.Namespace)
+				if !ok {
+					continue
+				}
+				if ns.Annotations[initialEventsEndAnnotation] == "true" {
+					return sortedNamespaces(byName), true, nil
+				}
+			case watch.Error:
+				return nil, false, fmt.Errorf("streaming list watch failed: %v", apierrors.FromObject(event.Object))
+			}
+		}
+	}
+}
+
+// listNamespacesFallback is the non-streaming path: a chunked LIST through
+// the typed client.
+func listNamespacesFallback(c clientset.Interface, labelSelector string) ([]v1.Namespace, bool, error) {
+	var items []v1.Namespace
+	opts := metav1.ListOptions{LabelSelector: labelSelector, Limit: defaultListPageSize}
+	for {
+		list, err := c.CoreV1().Namespaces().List(context.TODO(), opts)
+		if err != nil {
+			return nil, false, fmt.Errorf("fallback namespace list failed: %v", err)
+		}
+		items = append(items, list.Items...)
+		if list.Continue == "" {
+			return items, false, nil
+		}
+		opts.Continue = list.Continue
+	}
+}
+
+func sortedNamespaces(byName map[string]v1.Namespace) []v1.Namespace {
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := make([]v1.Namespace, 0, len(names))
+	for _, name := range names {
+		items = append(items, byName[name])
+	}
+	return items
+}
+
+// ServerAdvertisesCBOR probes whether the apiserver negotiates CBOR payloads
+// by requesting a one-item namespace list with a CBOR Accept header. Servers
+// without the encoding either answer 406 or fall back to JSON (whose payload
+// starts with '{'); only a non-JSON 200 response counts as support. The
+// framework cannot decode CBOR until the serializer is vendored, so this is
+// strictly a capability probe for logging and skipping.
+func ServerAdvertisesCBOR(c clientset.Interface) bool {
+	body, err := c.CoreV1().RESTClient().Get().
+		AbsPath("/api/v1/namespaces").
+		Param("limit", "1").
+		SetHeader("Accept", "application/cbor, application/json").
+		DoRaw(context.TODO())
+	if err != nil {
+		return false
+	}
+	return len(body) > 0 && body[0] != '{'
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// LogRecord is the machine-readable form of one framework log line, emitted
+// when --structured-log-format=json is set. Spec carries the full ginkgo spec
+// name so records from thousands of CI runs can be grouped without parsing the
+// free-form message; Fields carries whatever key/value pairs the call site
+// attached.
+type LogRecord struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Spec    string                 `json:"spec,omitempty"`
+	Message string                 `json:"msg"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+var (
+	structuredLogMu      sync.Mutex
+	structuredLogFile    *os.File
+	structuredLogEncoder *json.Encoder
+	structuredLogSpec    string
+)
+
+// LogWithFields logs a message together with explicit key/value pairs. The
+// human-readable line goes through the usual Logf path; the fields are only
+// preserved in the structured output, so call sites do not need to repeat
+// values in both forms.
+func LogWithFields(msg string, fields map[string]interface{}) {
+	fmt.Fprintf(ginkgo.GinkgoWriter, "%s: INFO: %s %v\n", nowStamp(), msg, fields)
+	emitLogRecord("INFO", msg, fields)
+}
+
+// emitLogRecord appends a JSON record for the current spec when structured
+// logging is enabled. It is called from the plain log path with nil fields, so
+// every Logf/Failf line is represented in the structured stream as well.
+func emitLogRecord(level, message string, fields map[string]interface{}) {
+	if TestContext.StructuredLogFormat != "json" {
+		return
+	}
+	record := LogRecord{
+		Time:    time.Now(),
+		Level:   level,
+		Spec:    ginkgo.CurrentSpecReport().FullText(),
+		Message: message,
+		Fields:  fields,
+	}
+	structuredLogMu.Lock()
+	defer structuredLogMu.Unlock()
+	encoder, err := specLogEncoderLocked(record.Spec)
+	if err != nil {
+		fmt.Fprintf(ginkgo.GinkgoWriter, "failed to open structured log file: %v\n", err)
+		return
+	}
+	if err := encoder.Encode(record); err != nil {
+		fmt.Fprintf(ginkgo.GinkgoWriter, "failed to encode structured log record: %v\n", err)
+	}
+}
+
+// specLogEncoderLocked returns the encoder for the given spec, rotating the
+// per-spec file in the report directory when the spec changes. Without a
+// report directory the JSON records go to the ginkgo writer so they still end
+// up in the captured output.
+func specLogEncoderLocked(spec string) (*json.Encoder, error) {
+	if TestContext.ReportDir == "" {
+		return json.NewEncoder(ginkgo.GinkgoWriter), nil
+	}
+	if structuredLogEncoder != nil && spec == structuredLogSpec {
+		return structuredLogEncoder, nil
+	}
+	if structuredLogFile != nil {
+		structuredLogFile.Close()
+		structuredLogFile = nil
+		structuredLogEncoder = nil
+	}
+	logDir := filepath.Join(TestContext.ReportDir, "logs")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return nil, err
+	}
+	name := sanitizeSpecLogName(spec)
+	if name == "" {
+		name = "suite"
+	}
+	file, err := os.OpenFile(filepath.Join(logDir, name+".json"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	structuredLogFile = file
+	structuredLogEncoder = json.NewEncoder(file)
+	structuredLogSpec = spec
+	return structuredLogEncoder, nil
+}
+
+var specLogNameFilterRE = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// sanitizeSpecLogName turns a full spec name into a safe file name, capped so
+// deeply nested describes do not exceed file system limits.
+func sanitizeSpecLogName(spec string) string {
+	name := specLogNameFilterRE.ReplaceAllString(spec, "_")
+	if len(name) > 200 {
+		name = name[:200]
+	}
+	return name
+}
@@ -80,10 +80,17 @@ type TestContextType struct {
 	KubeConfig         string
 	KubeContext        string
 	KubeAPIContentType string
-	KubeletRootDir     string
-	CertDir            string
-	Host               string
-	BearerToken        string `datapolicy:"token"`
+	// SecondaryKubeConfig optionally points at a second cluster for
+	// cross-cluster tests; see Framework.SecondaryClientSet.
+	SecondaryKubeConfig string
+	// AuditLogPath is where the apiserver writes its audit log on the
+	// master, if audit logging is enabled; empty disables per-spec audit
+	// slice collection (see audit_slice.go).
+	AuditLogPath   string
+	KubeletRootDir string
+	CertDir        string
+	Host           string
+	BearerToken    string `datapolicy:"token"`
 	// TODO: Deprecating this over time... instead just use gobindata_util.go , see #23987.
 	RepoRoot string
 	// ListImages will list off all images that are used then quit
@@ -98,11 +105,15 @@ type TestContextType struct {
 	// Tooling is the tooling in use (e.g. kops, gke).  Provider is the cloud provider and might not uniquely identify the tooling.
 	Tooling string
 
-	CloudConfig    CloudConfig
-	KubectlPath    string
-	OutputDir      string
-	ReportDir      string
-	ReportPrefix   string
+	CloudConfig  CloudConfig
+	KubectlPath  string
+	OutputDir    string
+	ReportDir    string
+	ReportPrefix string
+	// JSONReport, when set, selects a ginkgo JSON suite report enriched with
+	// framework data (namespaces, timings, API error counts), written next to
+	// the JUnit report; see json_report.go.
+	JSONReport     string
 	Prefix         string
 	MinStartupPods int
 	// Timeout for waiting for system pods to be running
@@ -149,6 +160,87 @@ type TestContextType struct {
 	CreateTestingNS CreateTestingNSFn
 	// If set to true test will dump data about the namespace in which test was running.
 	DumpLogsOnFailure bool
+	// If set to true every spec attaches an inventory of the objects left in
+	// its namespaces to the ginkgo report, not only failing ones.
+	DumpNamespaceContentAlways bool
+	// APICallBudget, when greater than zero, counts every API request issued
+	// by the framework clients during a spec and reacts when the count
+	// exceeds the budget. APICallBudgetAction selects the reaction: "warn"
+	// logs the offenders, "fail" fails the spec.
+	APICallBudget       int
+	APICallBudgetAction string
+	// RetryTransientAPIErrors, when true, makes the framework clients retry
+	// idempotent read requests that fail with transport errors or transient
+	// HTTP statuses (429/5xx), with jittered backoff, instead of failing the
+	// spec on a single apiserver blip during rolling upgrades or leader
+	// elections.
+	RetryTransientAPIErrors bool
+	// APICallHardCeiling is the watchdog limit for API calls issued without
+	// their own deadline. A call blocked longer than this gets its goroutine
+	// stacks dumped and is aborted with an error. Zero disables the watchdog.
+	APICallHardCeiling time.Duration
+	// ExistingNamespaces, when non-empty, is a comma-separated pool of
+	// pre-created namespaces the framework leases instead of creating its
+	// own, for clusters where the test user may not create cluster-scoped
+	// objects. Leased namespaces get their content deleted at spec end
+	// rather than being deleted themselves.
+	ExistingNamespaces string
+	// SLOVerdict selects how SLO-based specs judge a violated objective:
+	// "fail" (the default) fails the spec, "soft" reports it but passes, and
+	// "score" only writes the measured value and its score to the artifacts.
+	SLOVerdict string
+	// TestSeed seeds the framework's randomness source (randomized names and
+	// content). Zero derives a seed from the clock; the chosen seed is always
+	// recorded so a run can be replayed exactly.
+	TestSeed int64
+	// CleanupQPS, when non-zero, routes framework cleanup traffic (namespace
+	// deletion in AfterEach) through a client limited to this QPS so teardown
+	// cannot starve test traffic on shared clusters. CleanupBurst is the
+	// matching burst; it defaults to CleanupQPS when unset.
+	CleanupQPS   float64
+	CleanupBurst int
+	// NamespaceStress tunes the namespace lifecycle soak tests without code
+	// changes; zero (or negative, for AllowedRemainder) values keep the
+	// per-test defaults.
+	NamespaceStress NamespaceStressConfig
+	// NamespaceCanaryInterval, when non-zero, runs a background canary for
+	// the whole suite that creates and deletes a tiny namespace at this
+	// interval and records the latencies; see canary.go.
+	NamespaceCanaryInterval time.Duration
+	// APIAvailabilityProbeInterval, when non-zero, probes the apiserver at
+	// this interval for the duration of Serial and Disruptive specs and
+	// attaches a timeline of availability gaps to the report; see
+	// availability_monitor.go.
+	APIAvailabilityProbeInterval time.Duration
+	// PerSpecArtifacts, when true, organizes the diagnostic artifacts under
+	// ReportDir into one directory per spec and attempt instead of a flat
+	// listing; see artifacts.go.
+	PerSpecArtifacts bool
+	// TimeoutScale multiplies every framework deadline (TimeoutContext and
+	// the namespace/pod wait helpers), so slow clouds stretch waits uniformly
+	// instead of patching individual timeouts. 1.0 or 0 means no scaling.
+	TimeoutScale float64
+	// ClusterPollutionCheck selects whether cluster-scoped resources are
+	// diffed around every Serial spec: "" (off), "warn" logs the diff, "fail"
+	// fails the offending spec; see pollution_detector.go.
+	ClusterPollutionCheck string
+	// CleanupFromCheckpoint makes the suite delete everything recorded in the
+	// previous run's checkpoint file (see checkpoint.go) before it starts, so
+	// leftovers from an interrupted run cannot cascade into this one.
+	CleanupFromCheckpoint bool
+	// LeakedNamespaceMaxAge, when non-zero, lets the suite janitor delete
+	// e2e-run labeled namespaces left behind by earlier runs once they are
+	// older than this. Zero keeps the janitor off, so concurrent runs against
+	// a shared cluster cannot reap each other's fresh namespaces.
+	LeakedNamespaceMaxAge time.Duration
+	// ServiceAccountProvisioningThreshold, when non-zero, turns the measured
+	// default-service-account provisioning latency into an enforced assertion:
+	// any single provisioning (and the P99 in dedicated tests) exceeding the
+	// threshold fails the spec.
+	ServiceAccountProvisioningThreshold time.Duration
+	// DumpEncoding selects the object encoding used for dump artifacts ("yaml", "json",
+	// or any encoding registered via RegisterObjectDumpEncoder).
+	DumpEncoding string
 	// Disables dumping cluster log from master and nodes after all tests.
 	DisableLogDump bool
 	// Path to the GCS artifacts directory to dump logs from nodes. Logexporter gets enabled if this is non-empty.
@@ -170,6 +262,22 @@ type TestContextType struct {
 
 	// ProgressReportURL is the URL which progress updates will be posted to as tests complete. If empty, no updates are sent.
 	ProgressReportURL string
+	// ProgressListenAddress is the address the e2e process serves its own
+	// /progress and /metrics endpoints on, so long runs can be observed by
+	// polling instead of log parsing. If empty, no endpoint is served.
+	ProgressListenAddress string
+
+	// SelfProfile enables heap profiling of the e2e binary itself around
+	// resource-heavy phases, written to the report directory, so memory
+	// growth in framework helpers can be caught and diffed across runs.
+	SelfProfile bool
+
+	// StructuredLogFormat selects an additional machine-readable form for
+	// framework log output. "json" mirrors every Logf/Failf line into JSON
+	// records, written to per-spec files under <report-dir>/logs when a
+	// report directory is configured. Empty (the default) keeps plain text
+	// only.
+	StructuredLogFormat string
 
 	// SriovdpConfigMapFile is the path to the ConfigMap to configure the SRIOV device plugin on this host.
 	SriovdpConfigMapFile string
@@ -191,6 +299,21 @@ type TestContextType struct {
 	RequireDevices bool
 }
 
+// NamespaceStressConfig overrides the parameters of the namespace lifecycle
+// soak tests so CI jobs can run larger (1000+) or smaller soaks without code
+// changes.
+type NamespaceStressConfig struct {
+	// TotalNamespaces is the number of namespaces to churn; zero keeps the per-test default.
+	TotalNamespaces int
+	// AllowedRemainder is the number of namespaces allowed to survive the
+	// deletion deadline; negative keeps the per-test default.
+	AllowedRemainder int
+	// Timeout bounds how long deletion may take; zero keeps the per-test default.
+	Timeout time.Duration
+	// CreationParallelism caps concurrent namespace creations; zero means unbounded.
+	CreationParallelism int
+}
+
 // NodeKillerConfig describes configuration of NodeKiller -- a utility to
 // simulate node failures.
 type NodeKillerConfig struct {
@@ -287,6 +410,29 @@ func RegisterCommonFlags(flags *flag.FlagSet) {
 	flags.BoolVar(&TestContext.IncludeClusterAutoscalerMetrics, "include-cluster-autoscaler", false, "If set to true, framework will include Cluster Autoscaler when gathering metrics.")
 	flags.StringVar(&TestContext.OutputPrintType, "output-print-type", "json", "Format in which summaries should be printed: 'hr' for human readable, 'json' for JSON ones.")
 	flags.BoolVar(&TestContext.DumpLogsOnFailure, "dump-logs-on-failure", true, "If set to true test will dump data about the namespace in which test was running.")
+	flags.StringVar(&TestContext.DumpEncoding, "dump-encoding", "yaml", "Object encoding used for dump artifacts. Supported values are 'yaml', 'json' and any encoding registered by the test binary.")
+	flags.BoolVar(&TestContext.DumpNamespaceContentAlways, "dump-namespace-content-always", false, "If set to true every spec attaches an inventory of the objects left in its namespaces to the ginkgo report, not only failing ones.")
+	flags.IntVar(&TestContext.APICallBudget, "api-call-budget", 0, "If greater than zero, count every API request issued during a spec and react when the count exceeds this budget. 0 (the default) disables accounting.")
+	flags.StringVar(&TestContext.APICallBudgetAction, "api-call-budget-action", "warn", "What to do when a spec exceeds the API call budget: 'warn' logs the top offenders, 'fail' fails the spec.")
+	flags.BoolVar(&TestContext.RetryTransientAPIErrors, "retry-transient-api-errors", false, "If true, framework clients retry idempotent read requests on transient apiserver errors (connection failures, 429, 5xx) with jittered backoff, recording each retry as 'transient-infra'.")
+	flags.DurationVar(&TestContext.APICallHardCeiling, "api-call-hard-ceiling", 10*time.Minute, "Hard limit for API calls issued without their own deadline; a call blocked longer gets goroutine stacks dumped and is aborted. 0 disables the watchdog.")
+	flags.StringVar(&TestContext.ExistingNamespaces, "existing-namespaces", "", "Comma-separated names of pre-created namespaces to lease instead of creating namespaces, for clusters that forbid creating cluster-scoped objects. Specs that require real namespace create/delete are skipped.")
+	flags.StringVar(&TestContext.SLOVerdict, "slo-verdict", "fail", "How SLO-based specs judge a violated objective: 'fail' fails the spec, 'soft' reports the violation but passes, 'score' only writes the measurement and score to the artifacts.")
+	flags.Int64Var(&TestContext.TestSeed, "test-seed", 0, "Seed for the framework's randomness source. 0 (the default) derives a seed from the clock. The chosen seed is logged and written to the report directory; pass it back to replay a run's randomized names exactly.")
+	flags.Float64Var(&TestContext.CleanupQPS, "cleanup-qps", 0, "If non-zero, run framework cleanup (namespace deletion in AfterEach) through a client limited to this QPS.")
+	flags.IntVar(&TestContext.CleanupBurst, "cleanup-burst", 0, "Burst for the cleanup client; defaults to --cleanup-qps when unset.")
+	flags.DurationVar(&TestContext.ServiceAccountProvisioningThreshold, "service-account-provisioning-threshold", 0, "If set, fail specs whose default service account takes longer than this to be provisioned. 0 (the default) only gathers statistics.")
+	flags.DurationVar(&TestContext.LeakedNamespaceMaxAge, "leaked-namespace-max-age", 0, "If set, delete e2e-run labeled namespaces left behind by earlier runs that are older than this before the suite starts. 0 (the default) disables the janitor.")
+	flags.IntVar(&TestContext.NamespaceStress.TotalNamespaces, "namespace-stress-total", 0, "If greater than zero, overrides how many namespaces the namespace lifecycle soak tests churn.")
+	flags.IntVar(&TestContext.NamespaceStress.AllowedRemainder, "namespace-stress-allowed-remainder", -1, "If zero or greater, overrides how many namespaces may survive the deletion deadline in the namespace lifecycle soak tests.")
+	flags.DurationVar(&TestContext.NamespaceStress.Timeout, "namespace-stress-timeout", 0, "If set, overrides how long namespace deletion may take in the namespace lifecycle soak tests.")
+	flags.IntVar(&TestContext.NamespaceStress.CreationParallelism, "namespace-stress-parallelism", 0, "If greater than zero, caps how many namespaces the namespace lifecycle soak tests create concurrently.")
+	flags.Float64Var(&TestContext.TimeoutScale, "timeout-scale", 1.0, "Multiply every framework deadline by this factor, for slow clouds where the defaults are too tight. Poll intervals are unaffected.")
+	flags.StringVar(&TestContext.ClusterPollutionCheck, "cluster-pollution-check", "", "If set to 'warn' or 'fail', snapshot cluster-scoped resources (CRDs, cluster roles, webhooks, PVs, priority classes) around every Serial spec and report objects that appeared or disappeared, to attribute pollution between specs. Empty (the default) disables the check.")
+	flags.BoolVar(&TestContext.CleanupFromCheckpoint, "cleanup-from-checkpoint", false, "If true, delete the namespaces and cluster-scoped objects recorded in the report dir's suite checkpoint by a previous, interrupted run before the suite starts. Requires --report-dir.")
+	flags.DurationVar(&TestContext.NamespaceCanaryInterval, "namespace-canary-interval", 0, "If set, run a background canary that creates and deletes a tiny namespace at this interval for the whole suite and records the latencies, to tell cluster degradation apart from test-induced degradation. 0 (the default) disables the canary.")
+	flags.DurationVar(&TestContext.APIAvailabilityProbeInterval, "api-availability-probe-interval", 0, "If set, probe the apiserver (healthz, readyz and a cheap GET) at this interval while Serial and Disruptive specs run, and attach a timeline of availability gaps to the report. 0 (the default) disables the prober.")
+	flags.BoolVar(&TestContext.PerSpecArtifacts, "per-spec-artifacts", false, "If true, write per-spec diagnostic artifacts (timelines, dumps, metrics snapshots) under <report-dir>/<spec>/attempt-N/ instead of flat into the report dir, so CI can collect each spec's outputs as one unit. Requires --report-dir.")
 	flags.BoolVar(&TestContext.DisableLogDump, "disable-log-dump", false, "If set to true, logs from master and nodes won't be gathered after test run.")
 	flags.StringVar(&TestContext.LogexporterGCSPath, "logexporter-gcs-path", "", "Path to the GCS artifacts directory to dump logs from nodes. Logexporter gets enabled if this is non-empty.")
 	flags.BoolVar(&TestContext.DeleteNamespace, "delete-namespace", true, "If true tests will delete namespace after completion. It is only designed to make debugging easier, DO NOT turn it off by default.")
@@ -296,6 +442,7 @@ func RegisterCommonFlags(flags *flag.FlagSet) {
 	flags.StringVar(&TestContext.Host, "host", "", fmt.Sprintf("The host, or apiserver, to connect to. Will default to %s if this argument and --kubeconfig are not set.", defaultHost))
 	flags.StringVar(&TestContext.ReportPrefix, "report-prefix", "", "Optional prefix for JUnit XML reports. Default is empty, which doesn't prepend anything to the default name.")
 	flags.StringVar(&TestContext.ReportDir, "report-dir", "", "Path to the directory where the JUnit XML reports and other tests results should be saved. Default is empty, which doesn't generate these reports. If ginkgo's -junit-report parameter is used, that parameter instead of -report-dir determines the location of a single JUnit report.")
+	flags.StringVar(&TestContext.JSONReport, "json-report", "", "If set, additionally write a ginkgo JSON suite report enriched with framework data (namespaces, timings, API error counts) to this file. Relative paths are resolved inside --report-dir.")
 	flags.StringVar(&TestContext.ContainerRuntimeEndpoint, "container-runtime-endpoint", "unix:///var/run/containerd/containerd.sock", "The container runtime endpoint of cluster VM instances.")
 	flags.StringVar(&TestContext.ContainerRuntimeProcessName, "container-runtime-process-name", "dockerd", "The name of the container runtime process.")
 	flags.StringVar(&TestContext.ContainerRuntimePidFile, "container-runtime-pid-file", "/var/run/docker.pid", "The pid file of the container runtime.")
@@ -314,6 +461,9 @@ func RegisterCommonFlags(flags *flag.FlagSet) {
 	flags.StringVar(&TestContext.KubectlPath, "kubectl-path", "kubectl", "The kubectl binary to use. For development, you might use 'cluster/kubectl.sh' here.")
 
 	flags.StringVar(&TestContext.ProgressReportURL, "progress-report-url", "", "The URL to POST progress updates to as the suite runs to assist in aiding integrations. If empty, no messages sent.")
+	flags.StringVar(&TestContext.ProgressListenAddress, "progress-listen-address", "", "The address (e.g. 127.0.0.1:8099) on which the e2e process serves its /progress and /metrics endpoints. If empty, no endpoint is served.")
+	flags.BoolVar(&TestContext.SelfProfile, "self-profile", false, "If true, write pprof heap snapshots of the e2e binary itself around resource-heavy phases to --report-dir, for diffing memory growth in framework helpers across runs.")
+	flags.StringVar(&TestContext.StructuredLogFormat, "structured-log-format", "", "If set to 'json', mirror framework log output into machine-readable JSON records, written per spec to <report-dir>/logs when --report-dir is set. Empty (the default) keeps plain text only.")
 	flags.StringVar(&TestContext.SpecSummaryOutput, "spec-dump", "", "The file to dump all ginkgo.SpecSummary to after tests run. If empty, no objects are saved/printed.")
 	flags.StringVar(&TestContext.DockerConfigFile, "docker-config-file", "", "A file that contains credentials which can be used to pull images from certain private registries, needed for a test.")
 
@@ -350,6 +500,8 @@ func RegisterClusterFlags(flags *flag.FlagSet) {
 	flags.BoolVar(&TestContext.VerifyServiceAccount, "e2e-verify-service-account", true, "If true tests will verify the service account before running.")
 	flags.StringVar(&TestContext.KubeConfig, clientcmd.RecommendedConfigPathFlag, os.Getenv(clientcmd.RecommendedConfigPathEnvVar), "Path to kubeconfig containing embedded authinfo.")
 	flags.StringVar(&TestContext.KubeContext, clientcmd.FlagContext, "", "kubeconfig context to use/override. If unset, will use value from 'current-context'")
+	flags.StringVar(&TestContext.SecondaryKubeConfig, "secondary-kubeconfig", "", "Path to a kubeconfig for a second cluster. When set, the framework additionally provides a clientset and namespace lifecycle for that cluster so tests can cover behavior spanning two clusters.")
+	flags.StringVar(&TestContext.AuditLogPath, "audit-log-path", "", "Path of the apiserver audit log on the master, e.g. /var/log/kube-apiserver-audit.log. When set, failing specs get the audit events that touched their namespaces written next to the report. Requires SSH access to the master.")
 	flags.StringVar(&TestContext.KubeAPIContentType, "kube-api-content-type", "application/vnd.kubernetes.protobuf", "ContentType used to communicate with apiserver")
 
 	flags.StringVar(&TestContext.KubeletRootDir, "kubelet-root-dir", "/var/lib/kubelet", "The data directory of kubelet. Some tests (for example, CSI storage tests) deploy DaemonSets which need to know this value and cannot query it. Such tests only work in clusters where the data directory is the same on all nodes.")
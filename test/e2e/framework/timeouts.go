@@ -36,6 +36,9 @@ const (
 	snapshotCreateTimeout            = 5 * time.Minute
 	snapshotDeleteTimeout            = 5 * time.Minute
 	snapshotControllerMetricsTimeout = 5 * time.Minute
+	namespaceCreateTimeout           = 30 * time.Second
+	namespaceDeleteTimeout           = DefaultNamespaceDeletionTimeout
+	serviceAccountProvisionTimeout   = ServiceAccountProvisionTimeout
 )
 
 // TimeoutContext contains timeout settings for several actions.
@@ -89,11 +92,35 @@ type TimeoutContext struct {
 
 	// SnapshotControllerMetrics is how long to wait for snapshot controller metrics.
 	SnapshotControllerMetrics time.Duration
+
+	// NamespaceCreate is how long one namespace creation (including its
+	// conflict retries) may take.
+	NamespaceCreate time.Duration
+
+	// NamespaceDelete is how long a deleted namespace has to finish
+	// terminating.
+	NamespaceDelete time.Duration
+
+	// ServiceAccountProvision is how long a fresh namespace has to get its
+	// default service account and root CA configmap.
+	ServiceAccountProvision time.Duration
+}
+
+// ScaleTimeout multiplies a timeout by the --timeout-scale factor, so waits
+// with hard-coded budgets stretch uniformly on slow clouds. Poll intervals
+// are deliberately not scaled; only deadlines are.
+func ScaleTimeout(timeout time.Duration) time.Duration {
+	scale := TestContext.TimeoutScale
+	if scale <= 0 {
+		return timeout
+	}
+	return time.Duration(float64(timeout) * scale)
 }
 
-// NewTimeoutContextWithDefaults returns a TimeoutContext with default values.
+// NewTimeoutContextWithDefaults returns a TimeoutContext with default values,
+// scaled by --timeout-scale.
 func NewTimeoutContextWithDefaults() *TimeoutContext {
-	return &TimeoutContext{
+	return (&TimeoutContext{
 		PodStart:                  podStartTimeout,
 		PodStartShort:             podStartShortTimeout,
 		PodStartSlow:              podStartSlowTimeout,
@@ -110,5 +137,26 @@ func NewTimeoutContextWithDefaults() *TimeoutContext {
 		SnapshotCreate:            snapshotCreateTimeout,
 		SnapshotDelete:            snapshotDeleteTimeout,
 		SnapshotControllerMetrics: snapshotControllerMetricsTimeout,
+		NamespaceCreate:           namespaceCreateTimeout,
+		NamespaceDelete:           namespaceDeleteTimeout,
+		ServiceAccountProvision:   serviceAccountProvisionTimeout,
+	}).scaled()
+}
+
+// scaled returns the context with every deadline multiplied by the
+// --timeout-scale factor.
+func (tc *TimeoutContext) scaled() *TimeoutContext {
+	scaled := *tc
+	for _, timeout := range []*time.Duration{
+		&scaled.PodStart, &scaled.PodStartShort, &scaled.PodStartSlow,
+		&scaled.PodDelete,
+		&scaled.ClaimProvision, &scaled.ClaimProvisionShort, &scaled.DataSourceProvision,
+		&scaled.ClaimBound,
+		&scaled.PVReclaim, &scaled.PVBound, &scaled.PVCreate, &scaled.PVDelete, &scaled.PVDeleteSlow,
+		&scaled.SnapshotCreate, &scaled.SnapshotDelete, &scaled.SnapshotControllerMetrics,
+		&scaled.NamespaceCreate, &scaled.NamespaceDelete, &scaled.ServiceAccountProvision,
+	} {
+		*timeout = ScaleTimeout(*timeout)
 	}
+	return &scaled
 }
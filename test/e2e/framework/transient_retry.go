@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// transientRetryAttempts bounds the total tries per request, so a real
+	// outage still surfaces as an error instead of hanging the spec.
+	transientRetryAttempts = 3
+	// transientRetryBaseDelay is the backoff before the first retry; it
+	// doubles per attempt and gets up to 50% jitter so retries from parallel
+	// specs do not align into a thundering herd.
+	transientRetryBaseDelay = time.Second
+)
+
+// transientAPIRetryCount counts retries across the whole run, so suite-level
+// reporting can tell an infra-blip-heavy run from a clean one even after the
+// per-spec retry reports are gone.
+var transientAPIRetryCount int64
+
+// TransientAPIRetryCount returns the number of API requests this process has
+// retried because of transient apiserver unavailability.
+func TransientAPIRetryCount() int64 {
+	return atomic.LoadInt64(&transientAPIRetryCount)
+}
+
+// transientHTTPStatus reports whether a status code indicates a blip that a
+// healthy apiserver replica would not have produced: throttling or the 5xx
+// codes seen around rolling upgrades and leader elections.
+func transientHTTPStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryableVerb limits retries to verbs that are safe to repeat blindly.
+// Writes are left to the callers, which know whether their mutation is
+// idempotent.
+func retryableVerb(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+type transientRetryTransport struct {
+	delegate http.RoundTripper
+}
+
+func (t *transientRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retryableVerb(req.Method) || req.Body != nil {
+		return t.delegate.RoundTrip(req)
+	}
+	// Watches hold the connection open for their full timeout; rerunning one
+	// from scratch inside the transport would silently replay stale events.
+	if req.URL.Query().Get("watch") == "true" {
+		return t.delegate.RoundTrip(req)
+	}
+
+	var resp *http.Response
+	var err error
+	delay := transientRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		resp, err = t.delegate.RoundTrip(req)
+		if err == nil && !transientHTTPStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if attempt == transientRetryAttempts {
+			return resp, err
+		}
+		if err != nil {
+			RecordRetry("transient-infra", "%s %s failed: %v", req.Method, req.URL.Path, err)
+		} else {
+			RecordRetry("transient-infra", "%s %s returned %d", req.Method, req.URL.Path, resp.StatusCode)
+			// The response will not be returned; release the connection.
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		atomic.AddInt64(&transientAPIRetryCount, 1)
+
+		select {
+		case <-req.Context().Done():
+			return resp, req.Context().Err()
+		case <-time.After(wait.Jitter(delay, 0.5)):
+		}
+		delay *= 2
+	}
+}
+
+// wrapTransportForTransientRetries is installed into the framework client
+// config when --retry-transient-api-errors is set. It retries idempotent
+// read requests that hit transport errors or transient HTTP statuses, with
+// jittered exponential backoff, recording every retry under the
+// "transient-infra" category so flake dashboards can separate infra blips
+// from real regressions.
+func wrapTransportForTransientRetries(rt http.RoundTripper) http.RoundTripper {
+	return &transientRetryTransport{delegate: rt}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// VerifyNamespaceUIDFreshness walks the listable content of a recreated
+// namespace and returns an error if any object carries one of the stale UIDs
+// recorded before the recreation, either as its own UID or through an owner
+// reference. A name-reused namespace must be a clean slate: anything still
+// pointing at a pre-recreation UID is a stale artifact.
+func VerifyNamespaceUIDFreshness(c clientset.Interface, dc dynamic.Interface, namespace string, staleUIDs []types.UID) error {
+	stale := map[types.UID]bool{}
+	for _, uid := range staleUIDs {
+		stale[uid] = true
+	}
+	resourceLists, err := c.Discovery().ServerPreferredNamespacedResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return fmt.Errorf("failed to discover namespaced resources: %v", err)
+	}
+	var violations []string
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || !listableResource(resource) {
+				continue
+			}
+			gvr := gv.WithResource(resource.Name)
+			objs, err := dc.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				// Same tolerance as the content inventory: unavailable or
+				// forbidden resources cannot carry visible stale state.
+				continue
+			}
+			for _, item := range objs.Items {
+				if stale[item.GetUID()] {
+					violations = append(violations, fmt.Sprintf("%s %s still has pre-recreation UID %s", gvr.String(), item.GetName(), item.GetUID()))
+				}
+				for _, ref := range item.GetOwnerReferences() {
+					if stale[ref.UID] {
+						violations = append(violations, fmt.Sprintf("%s %s has an owner reference to stale %s %s (UID %s)", gvr.String(), item.GetName(), ref.Kind, ref.Name, ref.UID))
+					}
+				}
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("namespace %s carries stale UIDs from before its recreation:\n%s", namespace, strings.Join(violations, "\n"))
+	}
+	return nil
+}
@@ -22,7 +22,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math/rand"
 	"net"
 	"net/url"
 	"os"
@@ -46,6 +45,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -221,54 +221,283 @@ func NodeOSArchIs(supportedNodeOsArchs ...string) bool {
 // Filter is by simple strings.Contains; first skip filter, then delete filter.
 // Returns the list of deleted namespaces or an error.
 func DeleteNamespaces(c clientset.Interface, deleteFilter, skipFilter []string) ([]string, error) {
+	return DeleteNamespacesWithContext(context.TODO(), c, deleteFilter, skipFilter)
+}
+
+// DeleteNamespacesWithContext is DeleteNamespaces with an explicit context;
+// a cancelled context aborts both the listing and the in-flight deletes.
+func DeleteNamespacesWithContext(ctx context.Context, c clientset.Interface, deleteFilter, skipFilter []string) ([]string, error) {
+	return DeleteNamespacesWithOptions(ctx, c, metav1.DeleteOptions{}, deleteFilter, skipFilter)
+}
+
+// DeleteNamespacesWithOptions is DeleteNamespacesWithContext with explicit
+// DeleteOptions, so bulk teardown can exercise propagation policies or UID
+// preconditions. Note that preconditions apply to every matched namespace, so
+// they are only useful with filters selecting a single one.
+func DeleteNamespacesWithOptions(ctx context.Context, c clientset.Interface, opts metav1.DeleteOptions, deleteFilter, skipFilter []string) ([]string, error) {
 	ginkgo.By("Deleting namespaces")
-	nsList, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
-	ExpectNoError(err, "Failed to get namespace list")
-	var deleted []string
-	var wg sync.WaitGroup
-OUTER:
-	for _, item := range nsList.Items {
+	match := func(name string) bool {
 		for _, pattern := range skipFilter {
-			if strings.Contains(item.Name, pattern) {
-				continue OUTER
+			if strings.Contains(name, pattern) {
+				return false
+			}
+		}
+		if deleteFilter == nil {
+			return true
+		}
+		for _, pattern := range deleteFilter {
+			if strings.Contains(name, pattern) {
+				return true
 			}
 		}
-		if deleteFilter != nil {
-			var shouldDelete bool
-			for _, pattern := range deleteFilter {
-				if strings.Contains(item.Name, pattern) {
-					shouldDelete = true
-					break
+		return false
+	}
+	return deleteNamespacesStreaming(ctx, c, opts, metav1.ListOptions{}, match)
+}
+
+const (
+	// namespaceDeletePageSize bounds how many namespaces one LIST page may
+	// hold, so bulk deletion keeps flat memory on clusters with tens of
+	// thousands of namespaces instead of materializing them all at once.
+	namespaceDeletePageSize = 500
+	// namespaceDeleteWorkers bounds the concurrent DELETE calls issued by
+	// bulk deletion, replacing the earlier goroutine-per-namespace fan-out.
+	namespaceDeleteWorkers = 16
+)
+
+// deleteNamespacesStreaming pages through namespaces with the given list
+// options and feeds matching names to a bounded pool of delete workers as
+// each page arrives. Neither the held object list nor the goroutine count
+// grows with cluster size. A nil match deletes everything the selector
+// returns. NotFound on delete is tolerated: someone else finishing the job
+// is success.
+func deleteNamespacesStreaming(ctx context.Context, c clientset.Interface, deleteOpts metav1.DeleteOptions, listOpts metav1.ListOptions, match func(string) bool) ([]string, error) {
+	names := make(chan string)
+	var errLock sync.Mutex
+	var deleteErrs []error
+	var wg sync.WaitGroup
+	for i := 0; i < namespaceDeleteWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				if err := c.CoreV1().Namespaces().Delete(ctx, name, deleteOpts); err != nil && !apierrors.IsNotFound(err) {
+					errLock.Lock()
+					deleteErrs = append(deleteErrs, fmt.Errorf("failed to delete namespace %s: %v", name, err))
+					errLock.Unlock()
+					continue
 				}
+				Logf("namespace : %v api call to delete is complete ", name)
 			}
-			if !shouldDelete {
-				continue OUTER
+		}()
+	}
+
+	var deleted []string
+	listOpts.Limit = namespaceDeletePageSize
+	for {
+		page, err := c.CoreV1().Namespaces().List(ctx, listOpts)
+		if err != nil {
+			close(names)
+			wg.Wait()
+			return deleted, fmt.Errorf("failed to list namespaces: %v", err)
+		}
+		for i := range page.Items {
+			name := page.Items[i].Name
+			if match != nil && !match(name) {
+				continue
 			}
+			deleted = append(deleted, name)
+			names <- name
 		}
+		if page.Continue == "" {
+			break
+		}
+		listOpts.Continue = page.Continue
+	}
+	close(names)
+	wg.Wait()
+	if len(deleteErrs) > 0 {
+		return deleted, utilerrors.NewAggregate(deleteErrs)
+	}
+	return deleted, nil
+}
+
+// DeleteNamespacesByLabel deletes all namespaces matching the given label
+// selector in parallel and returns the names of the namespaces it deleted.
+// Unlike DeleteNamespaces it selects exactly, without substring matching on
+// names.
+func DeleteNamespacesByLabel(c clientset.Interface, labelSelector string) ([]string, error) {
+	return DeleteNamespacesBySelector(context.TODO(), c, NamespaceSelection{LabelSelector: labelSelector})
+}
+
+// NamespaceSelection selects namespaces for bulk deletion by exact label and
+// field selectors instead of name substrings, so suites that tag their
+// namespaces with a run-id label can clean up precisely.
+type NamespaceSelection struct {
+	LabelSelector string
+	FieldSelector string
+	// Protected namespaces are never deleted even when the selectors match
+	// them. The control plane namespaces (default, kube-system, kube-public,
+	// kube-node-lease) are always protected.
+	Protected []string
+}
+
+// DeleteNamespacesBySelector deletes every namespace matched by the selection
+// except protected ones, and returns the names of the namespaces it deleted.
+func DeleteNamespacesBySelector(ctx context.Context, c clientset.Interface, selection NamespaceSelection) ([]string, error) {
+	ginkgo.By(fmt.Sprintf("Deleting namespaces matching labels %q and fields %q", selection.LabelSelector, selection.FieldSelector))
+	return deleteNamespacesBySelector(ctx, c, selection)
+}
+
+func deleteNamespacesBySelector(ctx context.Context, c clientset.Interface, selection NamespaceSelection) ([]string, error) {
+	protected := sets.NewString(selection.Protected...)
+	protected.Insert(metav1.NamespaceDefault, metav1.NamespaceSystem, metav1.NamespacePublic, v1.NamespaceNodeLease)
+	listOpts := metav1.ListOptions{LabelSelector: selection.LabelSelector, FieldSelector: selection.FieldSelector}
+	return deleteNamespacesStreaming(ctx, c, metav1.DeleteOptions{}, listOpts, func(name string) bool {
+		return !protected.Has(name)
+	})
+}
+
+// NamespaceDeletionMetrics records when the delete call for one namespace was
+// issued and when its removal from the API was observed.
+type NamespaceDeletionMetrics struct {
+	Name            string
+	DeleteIssued    time.Time
+	RemovalObserved time.Time
+}
+
+// Removed reports whether the namespace's removal was observed before the
+// deadline.
+func (m NamespaceDeletionMetrics) Removed() bool {
+	return !m.RemovalObserved.IsZero()
+}
+
+// Duration returns the time between issuing the delete and observing the
+// removal, or zero when the removal was never observed.
+func (m NamespaceDeletionMetrics) Duration() time.Duration {
+	if !m.Removed() {
+		return 0
+	}
+	return m.RemovalObserved.Sub(m.DeleteIssued)
+}
+
+// DeleteNamespacesWithMetrics deletes all namespaces matching the given label
+// selector in parallel, then watches until every one is removed or the
+// timeout expires. It returns per-namespace deletion metrics so callers can
+// assert on latency distributions rather than a single pass/fail deadline;
+// namespaces that survive the timeout are returned with a zero
+// RemovalObserved time.
+func DeleteNamespacesWithMetrics(c clientset.Interface, labelSelector string, timeout time.Duration) ([]NamespaceDeletionMetrics, error) {
+	ginkgo.By(fmt.Sprintf("Deleting namespaces matching %q and timing their removal", labelSelector))
+	nsList, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces matching %q: %v", labelSelector, err)
+	}
+
+	var lock sync.Mutex
+	metrics := make(map[string]*NamespaceDeletionMetrics, len(nsList.Items))
+	var wg sync.WaitGroup
+	for _, item := range nsList.Items {
 		wg.Add(1)
-		deleted = append(deleted, item.Name)
 		go func(nsName string) {
 			defer wg.Done()
 			defer ginkgo.GinkgoRecover()
+			issued := time.Now()
 			gomega.Expect(c.CoreV1().Namespaces().Delete(context.TODO(), nsName, metav1.DeleteOptions{})).To(gomega.Succeed())
-			Logf("namespace : %v api call to delete is complete ", nsName)
+			lock.Lock()
+			metrics[nsName] = &NamespaceDeletionMetrics{Name: nsName, DeleteIssued: issued}
+			lock.Unlock()
 		}(item.Name)
 	}
 	wg.Wait()
-	return deleted, nil
+
+	deadline := time.Now().Add(timeout)
+	pending := make(map[string]bool, len(metrics))
+	for name := range metrics {
+		pending[name] = true
+	}
+	markRemoved := func(name string, at time.Time) {
+		if !pending[name] {
+			return
+		}
+		metrics[name].RemovalObserved = at
+		delete(pending, name)
+	}
+	for len(pending) > 0 && time.Now().Before(deadline) {
+		// Re-list both to pick up removals missed across watch restarts and
+		// to obtain a fresh resourceVersion to watch from.
+		nsList, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			time.Sleep(2 * time.Second)
+			continue
+		}
+		present := make(map[string]bool, len(nsList.Items))
+		for _, item := range nsList.Items {
+			present[item.Name] = true
+		}
+		now := time.Now()
+		for name := range pending {
+			if !present[name] {
+				markRemoved(name, now)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+		w, err := c.CoreV1().Namespaces().Watch(context.TODO(), metav1.ListOptions{
+			LabelSelector:   labelSelector,
+			ResourceVersion: nsList.ResourceVersion,
+		})
+		if err != nil {
+			time.Sleep(2 * time.Second)
+			continue
+		}
+	observe:
+		for len(pending) > 0 {
+			select {
+			case event, ok := <-w.ResultChan():
+				if !ok {
+					break observe
+				}
+				if event.Type != watch.Deleted {
+					continue
+				}
+				if ns, isNamespace := event.Object.(*v1.Namespace); isNamespace {
+					markRemoved(ns.Name, time.Now())
+				}
+			case <-time.After(time.Until(deadline)):
+				break observe
+			}
+		}
+		w.Stop()
+	}
+
+	results := make([]NamespaceDeletionMetrics, 0, len(metrics))
+	for _, m := range metrics {
+		results = append(results, *m)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results, nil
 }
 
 // WaitForNamespacesDeleted waits for the namespaces to be deleted.
 func WaitForNamespacesDeleted(c clientset.Interface, namespaces []string, timeout time.Duration) error {
+	return WaitForNamespacesDeletedWithContext(context.TODO(), c, namespaces, timeout)
+}
+
+// WaitForNamespacesDeletedWithContext is WaitForNamespacesDeleted with an
+// explicit context; a cancelled context ends the poll early.
+func WaitForNamespacesDeletedWithContext(ctx context.Context, c clientset.Interface, namespaces []string, timeout time.Duration) error {
 	ginkgo.By(fmt.Sprintf("Waiting for namespaces %+v to vanish", namespaces))
+	defer func(start time.Time) { RecordFrameworkWait("namespace-deletion", time.Since(start)) }(time.Now())
 	nsMap := map[string]bool{}
 	for _, ns := range namespaces {
 		nsMap[ns] = true
 	}
 	//Now POLL until all namespaces have been eradicated.
-	return wait.Poll(2*time.Second, timeout,
-		func() (bool, error) {
-			nsList, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	return wait.PollWithContext(ctx, 2*time.Second, timeout,
+		func(ctx context.Context) (bool, error) {
+			nsList, err := c.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 			if err != nil {
 				return false, err
 			}
@@ -281,19 +510,138 @@ func WaitForNamespacesDeleted(c clientset.Interface, namespaces []string, timeou
 		})
 }
 
-func waitForConfigMapInNamespace(c clientset.Interface, ns, name string, timeout time.Duration) error {
+// WaitForNamespaceTerminated waits until the named namespace is fully
+// terminated, using a field-selected watch (with automatic re-list when the
+// resourceVersion expires) instead of polling Get. It returns the last
+// observed state of the namespace, which is nil on success and useful for
+// diagnostics when the wait fails.
+func WaitForNamespaceTerminated(ctx context.Context, c clientset.Interface, name string, timeout time.Duration) (*v1.Namespace, error) {
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return c.CoreV1().Namespaces().List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return c.CoreV1().Namespaces().Watch(ctx, options)
+		},
+	}
+	waitCtx, cancel := watchtools.ContextWithOptionalTimeout(ctx, timeout)
+	defer cancel()
+	var lastNamespace *v1.Namespace
+	precondition := func(store cache.Store) (bool, error) {
+		obj, exists, err := store.GetByKey(name)
+		if err != nil {
+			return true, err
+		}
+		if !exists {
+			return true, nil
+		}
+		if ns, ok := obj.(*v1.Namespace); ok {
+			lastNamespace = ns
+		}
+		return false, nil
+	}
+	_, err := watchtools.UntilWithSync(waitCtx, lw, &v1.Namespace{}, precondition, func(event watch.Event) (bool, error) {
+		if ns, ok := event.Object.(*v1.Namespace); ok {
+			lastNamespace = ns
+		}
+		return event.Type == watch.Deleted, nil
+	})
+	if err != nil {
+		return lastNamespace, err
+	}
+	return nil, nil
+}
+
+// WaitForNamespaceCondition waits until the named namespace reports the
+// condition type with the given status, returning the namespace as last
+// observed. The condition controllers only publish while deletion is in
+// progress, so callers typically race this against a blocked termination.
+func WaitForNamespaceCondition(ctx context.Context, c clientset.Interface, name string, conditionType v1.NamespaceConditionType, status v1.ConditionStatus, timeout time.Duration) (*v1.Namespace, error) {
+	var lastNamespace *v1.Namespace
+	err := wait.PollImmediateWithContext(ctx, Poll, timeout, func(ctx context.Context) (bool, error) {
+		ns, err := c.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		lastNamespace = ns
+		for _, condition := range ns.Status.Conditions {
+			if condition.Type == conditionType && condition.Status == status {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return lastNamespace, fmt.Errorf("namespace %s never reported condition %s=%s: %v", name, conditionType, status, err)
+	}
+	return lastNamespace, nil
+}
+
+// WaitAllNamespacesActive waits until every named namespace reports phase
+// Active, using one list plus a single shared watch instead of polling (or a
+// fixed sleep), so callers creating many namespaces proceed as soon as the
+// cluster is ready and still get a correct barrier on slow clusters.
+func WaitAllNamespacesActive(c clientset.Interface, names []string, timeout time.Duration) error {
+	pending := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		pending[name] = struct{}{}
+	}
+	nsList, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, ns := range nsList.Items {
+		if _, waiting := pending[ns.Name]; waiting && ns.Status.Phase == v1.NamespaceActive {
+			delete(pending, ns.Name)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	w, err := c.CoreV1().Namespaces().Watch(context.TODO(), metav1.ListOptions{ResourceVersion: nsList.ResourceVersion})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed while waiting for %d namespaces to become Active", len(pending))
+			}
+			ns, ok := event.Object.(*v1.Namespace)
+			if !ok {
+				continue
+			}
+			if _, waiting := pending[ns.Name]; waiting && ns.Status.Phase == v1.NamespaceActive {
+				delete(pending, ns.Name)
+				if len(pending) == 0 {
+					return nil
+				}
+			}
+		case <-deadline:
+			return fmt.Errorf("timed out waiting for %d of %d namespaces to become Active", len(pending), len(names))
+		}
+	}
+}
+
+func waitForConfigMapInNamespace(parentCtx context.Context, c clientset.Interface, ns, name string, timeout time.Duration) error {
 	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
 	lw := &cache.ListWatch{
 		ListFunc: func(options metav1.ListOptions) (object runtime.Object, e error) {
 			options.FieldSelector = fieldSelector
-			return c.CoreV1().ConfigMaps(ns).List(context.TODO(), options)
+			return c.CoreV1().ConfigMaps(ns).List(parentCtx, options)
 		},
 		WatchFunc: func(options metav1.ListOptions) (i watch.Interface, e error) {
 			options.FieldSelector = fieldSelector
-			return c.CoreV1().ConfigMaps(ns).Watch(context.TODO(), options)
+			return c.CoreV1().ConfigMaps(ns).Watch(parentCtx, options)
 		},
 	}
-	ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), timeout)
+	ctx, cancel := watchtools.ContextWithOptionalTimeout(parentCtx, timeout)
 	defer cancel()
 	_, err := watchtools.UntilWithSync(ctx, lw, &v1.ConfigMap{}, nil, func(event watch.Event) (bool, error) {
 		switch event.Type {
@@ -307,19 +655,19 @@ func waitForConfigMapInNamespace(c clientset.Interface, ns, name string, timeout
 	return err
 }
 
-func waitForServiceAccountInNamespace(c clientset.Interface, ns, serviceAccountName string, timeout time.Duration) error {
+func waitForServiceAccountInNamespace(parentCtx context.Context, c clientset.Interface, ns, serviceAccountName string, timeout time.Duration) error {
 	fieldSelector := fields.OneTermEqualSelector("metadata.name", serviceAccountName).String()
 	lw := &cache.ListWatch{
 		ListFunc: func(options metav1.ListOptions) (object runtime.Object, e error) {
 			options.FieldSelector = fieldSelector
-			return c.CoreV1().ServiceAccounts(ns).List(context.TODO(), options)
+			return c.CoreV1().ServiceAccounts(ns).List(parentCtx, options)
 		},
 		WatchFunc: func(options metav1.ListOptions) (i watch.Interface, e error) {
 			options.FieldSelector = fieldSelector
-			return c.CoreV1().ServiceAccounts(ns).Watch(context.TODO(), options)
+			return c.CoreV1().ServiceAccounts(ns).Watch(parentCtx, options)
 		},
 	}
-	ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), timeout)
+	ctx, cancel := watchtools.ContextWithOptionalTimeout(parentCtx, timeout)
 	defer cancel()
 	_, err := watchtools.UntilWithSync(ctx, lw, &v1.ServiceAccount{}, nil, func(event watch.Event) (bool, error) {
 		switch event.Type {
@@ -337,62 +685,244 @@ func waitForServiceAccountInNamespace(c clientset.Interface, ns, serviceAccountN
 // the default service account is what is associated with pods when they do not specify a service account
 // as a result, pods are not able to be provisioned in a namespace until the service account is provisioned
 func WaitForDefaultServiceAccountInNamespace(c clientset.Interface, namespace string) error {
-	return waitForServiceAccountInNamespace(c, namespace, "default", ServiceAccountProvisionTimeout)
+	return WaitForDefaultServiceAccountInNamespaceWithContext(context.TODO(), c, namespace)
+}
+
+// WaitForDefaultServiceAccountInNamespaceWithContext is
+// WaitForDefaultServiceAccountInNamespace with an explicit context, so the
+// wait ends when a per-spec context is cancelled.
+func WaitForDefaultServiceAccountInNamespaceWithContext(ctx context.Context, c clientset.Interface, namespace string) error {
+	start := time.Now()
+	err := waitForServiceAccountInNamespace(ctx, c, namespace, "default", ScaleTimeout(ServiceAccountProvisionTimeout))
+	if err == nil {
+		recordServiceAccountProvisioningLatency(namespace, time.Since(start))
+	}
+	return err
+}
+
+// AddNamespaceSpecFinalizer adds the given finalizer to the namespace's
+// spec.finalizers through the /finalize subresource and returns the updated
+// namespace. The namespace controller will not complete deletion of the
+// namespace until the finalizer is removed again.
+func AddNamespaceSpecFinalizer(c clientset.Interface, name string, finalizer v1.FinalizerName) (*v1.Namespace, error) {
+	return mutateNamespaceSpecFinalizers(c, name, func(finalizers []v1.FinalizerName) []v1.FinalizerName {
+		for _, existing := range finalizers {
+			if existing == finalizer {
+				return finalizers
+			}
+		}
+		return append(finalizers, finalizer)
+	})
+}
+
+// RemoveNamespaceSpecFinalizer removes the given finalizer from the
+// namespace's spec.finalizers through the /finalize subresource, unblocking
+// deletion, and returns the updated namespace.
+func RemoveNamespaceSpecFinalizer(c clientset.Interface, name string, finalizer v1.FinalizerName) (*v1.Namespace, error) {
+	return mutateNamespaceSpecFinalizers(c, name, func(finalizers []v1.FinalizerName) []v1.FinalizerName {
+		kept := make([]v1.FinalizerName, 0, len(finalizers))
+		for _, existing := range finalizers {
+			if existing != finalizer {
+				kept = append(kept, existing)
+			}
+		}
+		return kept
+	})
+}
+
+func mutateNamespaceSpecFinalizers(c clientset.Interface, name string, mutate func([]v1.FinalizerName) []v1.FinalizerName) (*v1.Namespace, error) {
+	var updated *v1.Namespace
+	err := wait.PollImmediate(Poll, 30*time.Second, func() (bool, error) {
+		ns, err := c.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		ns.Spec.Finalizers = mutate(ns.Spec.Finalizers)
+		updated, err = c.CoreV1().Namespaces().Finalize(context.TODO(), ns, metav1.UpdateOptions{})
+		if err != nil {
+			if apierrors.IsConflict(err) {
+				RecordRetry("conflict", "finalize of namespace %q conflicted", name)
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	})
+	return updated, err
+}
+
+// GetNamespaceWithResourceVersion issues a GET for the namespace with explicit
+// resourceVersion semantics: "" forces a quorum read that must observe the
+// caller's own preceding writes, while "0" allows the apiserver to serve a
+// possibly stale object from the watch cache.
+func GetNamespaceWithResourceVersion(c clientset.Interface, name, resourceVersion string) (*v1.Namespace, error) {
+	return c.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{ResourceVersion: resourceVersion})
 }
 
 // WaitForKubeRootCAInNamespace waits for the configmap kube-root-ca.crt containing the service account
 // CA trust bundle to be provisioned in the specified namespace so that pods do not have to retry mounting
 // the config map (which creates noise that hides other issues in the Kubelet).
 func WaitForKubeRootCAInNamespace(c clientset.Interface, namespace string) error {
-	return waitForConfigMapInNamespace(c, namespace, "kube-root-ca.crt", ServiceAccountProvisionTimeout)
+	return waitForConfigMapInNamespace(context.TODO(), c, namespace, "kube-root-ca.crt", ScaleTimeout(ServiceAccountProvisionTimeout))
+}
+
+// WaitForNamespaceInitialized waits until a freshly created namespace has both
+// the default service account and the kube-root-ca.crt configmap provisioned,
+// which is what a pod created immediately after namespace creation needs for
+// its service account token and projected CA bundle volumes. A single watch
+// cannot span both resource types, so the two watch-based waits run
+// concurrently under a shared timeout instead of polling one after the other.
+func WaitForNamespaceInitialized(ctx context.Context, c clientset.Interface, namespace string) error {
+	defer func(start time.Time) { RecordFrameworkWait("namespace-initialization", time.Since(start)) }(time.Now())
+	errs := make(chan error, 2)
+	go func() {
+		errs <- WaitForDefaultServiceAccountInNamespaceWithContext(ctx, c, namespace)
+	}()
+	go func() {
+		errs <- waitForConfigMapInNamespace(ctx, c, namespace, "kube-root-ca.crt", ScaleTimeout(ServiceAccountProvisionTimeout))
+	}()
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			return fmt.Errorf("waiting for namespace %s to be initialized: %v", namespace, err)
+		}
+	}
+	return nil
 }
 
+// NamespaceCreationError reports a namespace creation that did not succeed
+// within its retry budget. Callers can unwrap it to inspect the API error of
+// the last attempt, e.g. with apierrors.IsAlreadyExists.
+type NamespaceCreationError struct {
+	// BaseName is the requested name prefix.
+	BaseName string
+	// LastName is the last concrete name tried; empty when the server was
+	// generating the names.
+	LastName string
+	// Attempts is how many create calls were made.
+	Attempts int
+	// Err is the error of the last attempt.
+	Err error
+}
+
+func (e *NamespaceCreationError) Error() string {
+	name := e.LastName
+	if name == "" {
+		name = e.BaseName + "-<generated>"
+	}
+	return fmt.Sprintf("failed to create namespace %s after %d attempts: %v", name, e.Attempts, e.Err)
+}
+
+func (e *NamespaceCreationError) Unwrap() error { return e.Err }
+
 // CreateTestingNS should be used by every test, note that we append a common prefix to the provided test name.
 // Please see NewFramework instead of using this directly.
 func CreateTestingNS(baseName string, c clientset.Interface, labels map[string]string) (*v1.Namespace, error) {
+	return CreateTestingNSWithContext(context.TODO(), baseName, c, labels)
+}
+
+// CreateTestingNSWithGenerateName is CreateTestingNSWithContext with the name
+// chosen by the server through metadata.generateName, so concurrent creators
+// cannot collide on names at all. The trade-off is that a create call whose
+// response is lost may leak a namespace this client never learns the name of;
+// the e2e-run label keeps such leaks reachable for the suite janitor.
+func CreateTestingNSWithGenerateName(ctx context.Context, baseName string, c clientset.Interface, labels map[string]string) (*v1.Namespace, error) {
+	return createTestingNS(ctx, baseName, c, labels, true, nil)
+}
+
+// CreateTestingNSWithContext is CreateTestingNS with an explicit context, so
+// callers driven by a per-spec context abort the creation retries when the
+// spec is over.
+func CreateTestingNSWithContext(ctx context.Context, baseName string, c clientset.Interface, labels map[string]string) (*v1.Namespace, error) {
+	return createTestingNS(ctx, baseName, c, labels, false, nil)
+}
+
+// namespaceInitializationAttempts is how many times the initialization wait
+// is retried before the creation path gives up. A watch dropped by an
+// apiserver restart fails the wait even though the namespace itself is fine,
+// so a single failure is not yet a verdict on the service account controller.
+const namespaceInitializationAttempts = 3
+
+func waitForNamespaceInitializedWithRetries(ctx context.Context, c clientset.Interface, namespace string) error {
+	var lastErr error
+	for attempt := 1; attempt <= namespaceInitializationAttempts; attempt++ {
+		lastErr = WaitForNamespaceInitialized(ctx, c, namespace)
+		if lastErr == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return lastErr
+		}
+		if attempt < namespaceInitializationAttempts {
+			RecordRetry("transient", "initialization wait for namespace %s failed (attempt %d): %v", namespace, attempt, lastErr)
+		}
+	}
+	return lastErr
+}
+
+// createTestingNS is the shared creation path behind the exported variants.
+// onCreated, when non-nil, runs as soon as the namespace object exists and
+// before any initialization wait; the framework uses it to register the
+// namespace for cleanup first, so a failed or aborted wait can no longer
+// leave a created namespace untracked.
+func createTestingNS(ctx context.Context, baseName string, c clientset.Interface, labels map[string]string, useGenerateName bool, onCreated func(*v1.Namespace)) (*v1.Namespace, error) {
 	if labels == nil {
 		labels = map[string]string{}
 	}
 	labels["e2e-run"] = string(RunID)
 
-	// We don't use ObjectMeta.GenerateName feature, as in case of API call
-	// failure we don't know whether the namespace was created and what is its
-	// name.
-	name := fmt.Sprintf("%v-%v", baseName, RandomSuffix())
-
 	namespaceObj := &v1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: "",
-			Labels:    labels,
-		},
-		Status: v1.NamespaceStatus{},
+		ObjectMeta: metav1.ObjectMeta{Labels: labels},
+	}
+	if useGenerateName {
+		namespaceObj.GenerateName = baseName + "-"
+	} else {
+		// In this mode we avoid ObjectMeta.GenerateName: in case of API call
+		// failure we would not know whether the namespace was created and
+		// what its name is.
+		namespaceObj.Name = fmt.Sprintf("%v-%v", baseName, RandomSuffix())
 	}
+
 	// Be robust about making the namespace creation call.
 	var got *v1.Namespace
-	if err := wait.PollImmediate(Poll, 30*time.Second, func() (bool, error) {
+	var lastErr error
+	attempts := 0
+	if err := wait.PollImmediateWithContext(ctx, Poll, ScaleTimeout(namespaceCreateTimeout), func(ctx context.Context) (bool, error) {
+		attempts++
 		var err error
-		got, err = c.CoreV1().Namespaces().Create(context.TODO(), namespaceObj, metav1.CreateOptions{})
+		got, err = c.CoreV1().Namespaces().Create(ctx, namespaceObj, metav1.CreateOptions{})
 		if err != nil {
-			if apierrors.IsAlreadyExists(err) {
+			lastErr = err
+			switch {
+			case apierrors.IsAlreadyExists(err) && useGenerateName:
+				// The server exhausted its own suffix attempts; rare, and a
+				// plain retry draws fresh suffixes.
+				RecordRetry("conflict", "generated namespace name for %q was already taken", baseName)
+			case apierrors.IsAlreadyExists(err):
 				// regenerate on conflict
 				Logf("Namespace name %q was already taken, generate a new name and retry", namespaceObj.Name)
+				RecordRetry("conflict", "namespace name %q was already taken", namespaceObj.Name)
 				namespaceObj.Name = fmt.Sprintf("%v-%v", baseName, RandomSuffix())
-			} else {
+			default:
 				Logf("Unexpected error while creating namespace: %v", err)
 			}
 			return false, nil
 		}
 		return true, nil
 	}); err != nil {
-		return nil, err
+		if lastErr == nil {
+			lastErr = err
+		}
+		return nil, &NamespaceCreationError{BaseName: baseName, LastName: namespaceObj.Name, Attempts: attempts, Err: lastErr}
+	}
+
+	if onCreated != nil {
+		onCreated(got)
 	}
 
 	if TestContext.VerifyServiceAccount {
-		if err := WaitForDefaultServiceAccountInNamespace(c, got.Name); err != nil {
-			// Even if we fail to create serviceAccount in the namespace,
-			// we have successfully create a namespace.
-			// So, return the created namespace.
+		if err := waitForNamespaceInitializedWithRetries(ctx, c, got.Name); err != nil {
+			// The namespace exists even if it never finishes initializing, so
+			// return it alongside the error.
 			return got, err
 		}
 	}
@@ -537,9 +1067,23 @@ func LoadClientset() (*clientset.Clientset, error) {
 	return clientset.NewForConfig(config)
 }
 
+// ProxiedClientSet returns a clientset whose requests reach the apiserver
+// through its own service proxy path, modelling clients that access the API
+// via the aggregation layer proxy rather than directly. Whether the proxied
+// requests are accepted depends on how the cluster wires proxy
+// authentication, so callers should probe with a cheap read and skip when it
+// is rejected.
+func ProxiedClientSet(config *restclient.Config) (clientset.Interface, error) {
+	proxyConfig := restclient.CopyConfig(config)
+	proxyConfig.Host = strings.TrimRight(proxyConfig.Host, "/") + "/api/v1/namespaces/default/services/https:kubernetes:443/proxy"
+	return clientset.NewForConfig(proxyConfig)
+}
+
 // RandomSuffix provides a random sequence to append to pods,services,rcs.
+// It draws from the framework's seeded randomness source so that runs can be
+// replayed with --test-seed.
 func RandomSuffix() string {
-	return strconv.Itoa(rand.Intn(10000))
+	return strconv.Itoa(RandIntn(10000))
 }
 
 // LookForStringInPodExec looks for the given string in the output of a command
@@ -947,6 +1491,110 @@ func DumpAllNamespaceInfo(c clientset.Interface, namespace string) {
 	}
 }
 
+// CleanupLeakedTestNamespaces deletes namespaces that earlier, aborted e2e
+// runs left behind: those carrying the "e2e-run" label with a value other
+// than the current RunID and a creation timestamp older than maxAge. The age
+// guard keeps the janitor from reaping namespaces owned by another run that
+// is still in flight against the same cluster. It returns the names of the
+// namespaces whose deletion was issued; termination is not waited for.
+func CleanupLeakedTestNamespaces(c clientset.Interface, maxAge time.Duration) ([]string, error) {
+	nsList, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: "e2e-run"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list e2e-run labeled namespaces: %v", err)
+	}
+	cutoff := time.Now().Add(-maxAge)
+	var deleted []string
+	var errs []error
+	for i := range nsList.Items {
+		ns := &nsList.Items[i]
+		if ns.Labels["e2e-run"] == string(RunID) {
+			continue
+		}
+		if ns.Status.Phase == v1.NamespaceTerminating {
+			continue
+		}
+		if ns.CreationTimestamp.Time.After(cutoff) {
+			continue
+		}
+		if err := c.CoreV1().Namespaces().Delete(context.TODO(), ns.Name, metav1.DeleteOptions{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("failed to delete leaked namespace %s: %v", ns.Name, err))
+			continue
+		}
+		deleted = append(deleted, ns.Name)
+	}
+	return deleted, utilerrors.NewAggregate(errs)
+}
+
+// DumpTerminatingNamespaces finds all namespaces created by this e2e run (via
+// the "e2e-run" label) that are still in the Terminating phase and dumps their
+// YAML, conditions and a sweep of remaining objects. The output goes to files
+// in TestContext.ReportDir when set, or to the log otherwise. It is meant to be
+// called at suite end so stuck namespaces are captured regardless of which spec
+// created them.
+func DumpTerminatingNamespaces(c clientset.Interface) {
+	nsList, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"e2e-run": string(RunID)}).String(),
+	})
+	if err != nil {
+		Logf("Unable to list namespaces while dumping Terminating namespaces: %v", err)
+		return
+	}
+	encoder := GetObjectDumpEncoder(TestContext.DumpEncoding)
+	for i := range nsList.Items {
+		ns := &nsList.Items[i]
+		if ns.Status.Phase != v1.NamespaceTerminating {
+			continue
+		}
+		dump := bytes.Buffer{}
+		data, err := encoder.Encode(ns)
+		if err != nil {
+			Logf("Unable to marshal namespace %s: %v", ns.Name, err)
+			continue
+		}
+		dump.Write(data)
+		for _, condition := range ns.Status.Conditions {
+			fmt.Fprintf(&dump, "# condition %s=%s (%s): %s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+		dumpRemainingObjectsInNamespace(c, ns.Name, &dump)
+		if TestContext.ReportDir != "" {
+			filePath := path.Join(TestContext.ReportDir, fmt.Sprintf("terminating_namespace_%s.%s", ns.Name, encoder.FileExtension()))
+			if err := os.WriteFile(filePath, dump.Bytes(), 0644); err != nil {
+				Logf("Failed to write dump of Terminating namespace %s to %v: %v", ns.Name, filePath, err)
+			}
+		} else {
+			Logf("Terminating namespace %s at suite end:\n%s", ns.Name, dump.String())
+		}
+	}
+}
+
+// dumpRemainingObjectsInNamespace appends a summary of objects still present in
+// the namespace for the common core resources to the given buffer.
+func dumpRemainingObjectsInNamespace(c clientset.Interface, namespace string, dump *bytes.Buffer) {
+	if pods, err := c.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{}); err == nil {
+		for _, pod := range pods.Items {
+			fmt.Fprintf(dump, "# remaining pod: %s (phase %s)\n", pod.Name, pod.Status.Phase)
+		}
+	}
+	if services, err := c.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{}); err == nil {
+		for _, service := range services.Items {
+			fmt.Fprintf(dump, "# remaining service: %s\n", service.Name)
+		}
+	}
+	if configMaps, err := c.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{}); err == nil {
+		for _, configMap := range configMaps.Items {
+			fmt.Fprintf(dump, "# remaining configmap: %s\n", configMap.Name)
+		}
+	}
+	if secrets, err := c.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{}); err == nil {
+		for _, secret := range secrets.Items {
+			fmt.Fprintf(dump, "# remaining secret: %s\n", secret.Name)
+		}
+	}
+}
+
 // byFirstTimestamp sorts a slice of events by first timestamp, using their involvedObject's name as a tie breaker.
 type byFirstTimestamp []v1.Event
 
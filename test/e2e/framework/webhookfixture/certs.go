@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhookfixture
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	"k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/kubernetes/test/utils"
+)
+
+// servingCerts holds PEM-encoded serving material for the webhook server: a
+// fresh self-signed CA and a server certificate for the in-cluster service
+// DNS name signed by it.
+type servingCerts struct {
+	cert        []byte
+	key         []byte
+	signingCert []byte
+}
+
+// generateServingCerts mints a CA and a serving cert for
+// "<serviceName>.<namespaceName>.svc", so the apiserver can verify the
+// webhook backend against the CA bundle published in the webhook
+// configuration.
+func generateServingCerts(namespaceName, serviceName string) (*servingCerts, error) {
+	signingKey, err := utils.NewPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA private key: %v", err)
+	}
+	signingCert, err := cert.NewSelfSignedCACert(cert.Config{CommonName: "e2e-webhook-fixture-ca"}, signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA cert: %v", err)
+	}
+	key, err := utils.NewPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server private key: %v", err)
+	}
+	serviceDNSName := serviceName + "." + namespaceName + ".svc"
+	signedCert, err := utils.NewSignedCert(
+		&cert.Config{
+			CommonName: serviceDNSName,
+			AltNames:   cert.AltNames{DNSNames: []string{serviceDNSName}},
+			Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		},
+		key, signingCert, signingKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create serving cert for %s: %v", serviceDNSName, err)
+	}
+	privateKeyPEM, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal server private key: %v", err)
+	}
+	return &servingCerts{
+		cert:        utils.EncodeCertPEM(signedCert),
+		key:         privateKeyPEM,
+		signingCert: utils.EncodeCertPEM(signingCert),
+	}, nil
+}
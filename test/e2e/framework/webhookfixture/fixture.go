@@ -0,0 +1,276 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhookfixture deploys a real admission webhook backend (secret,
+// deployment, service, serving certificate) into a test namespace and
+// registers webhook configurations pointing at it, so suites exercising
+// admission behavior do not have to copy the bootstrap code around.
+package webhookfixture
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"k8s.io/kubernetes/test/e2e/framework"
+	e2edeployment "k8s.io/kubernetes/test/e2e/framework/deployment"
+	imageutils "k8s.io/kubernetes/test/utils/image"
+)
+
+// Config tunes the deployed webhook backend. The zero value deploys the
+// agnhost webhook server on its usual ports.
+type Config struct {
+	// Image of the webhook server; defaults to the agnhost e2e image, whose
+	// `webhook` command serves the familiar echo/deny/delay endpoints
+	// (/pods, /configmaps, /add-label, /always-allow-delay-5s, ...).
+	Image string
+	// ServicePort the service exposes; defaults to 8443.
+	ServicePort int32
+	// ContainerPort the server listens on; defaults to 8444.
+	ContainerPort int32
+}
+
+// Fixture is a deployed webhook backend plus the webhook configurations
+// registered against it. The in-namespace objects disappear with the test
+// namespace; the cluster-scoped configurations are removed by Teardown.
+type Fixture struct {
+	client      clientset.Interface
+	namespace   string
+	serviceName string
+	servicePort int32
+	caBundle    []byte
+
+	validatingConfigs []string
+	mutatingConfigs   []string
+}
+
+// Deploy stands up the webhook backend in the given namespace and waits for
+// it to be ready to serve. The returned fixture can then register webhook
+// configurations against it.
+func Deploy(c clientset.Interface, namespace string, config Config) (*Fixture, error) {
+	if config.Image == "" {
+		config.Image = imageutils.GetE2EImage(imageutils.Agnhost)
+	}
+	if config.ServicePort == 0 {
+		config.ServicePort = 8443
+	}
+	if config.ContainerPort == 0 {
+		config.ContainerPort = 8444
+	}
+	suffix := framework.RandomSuffix()
+	fixture := &Fixture{
+		client:      c,
+		namespace:   namespace,
+		serviceName: "e2e-webhook-" + suffix,
+		servicePort: config.ServicePort,
+	}
+
+	certs, err := generateServingCerts(namespace, fixture.serviceName)
+	if err != nil {
+		return nil, err
+	}
+	fixture.caBundle = certs.signingCert
+
+	if err := createAuthReaderRoleBinding(c, namespace); err != nil {
+		return nil, err
+	}
+
+	secretName := "e2e-webhook-certs-" + suffix
+	_, err = c.CoreV1().Secrets(namespace).Create(context.TODO(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName},
+		Type:       v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"tls.crt": certs.cert,
+			"tls.key": certs.key,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("creating webhook cert secret in namespace %s: %v", namespace, err)
+	}
+
+	deploymentName := "e2e-webhook-" + suffix
+	podLabels := map[string]string{"app": deploymentName}
+	deployment := e2edeployment.NewDeployment(deploymentName, 1, podLabels, "", "", appsv1.RollingUpdateDeploymentStrategyType)
+	deployment.Spec.Template.Spec.Volumes = []v1.Volume{{
+		Name: "webhook-certs",
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{SecretName: secretName},
+		},
+	}}
+	deployment.Spec.Template.Spec.Containers = []v1.Container{{
+		Name:  "webhook",
+		Image: config.Image,
+		VolumeMounts: []v1.VolumeMount{{
+			Name:      "webhook-certs",
+			ReadOnly:  true,
+			MountPath: "/webhook.local.config/certificates",
+		}},
+		Args: []string{
+			"webhook",
+			"--tls-cert-file=/webhook.local.config/certificates/tls.crt",
+			"--tls-private-key-file=/webhook.local.config/certificates/tls.key",
+			"--alsologtostderr",
+			"-v=4",
+			fmt.Sprintf("--port=%d", config.ContainerPort),
+		},
+		ReadinessProbe: &v1.Probe{
+			ProbeHandler: v1.ProbeHandler{
+				HTTPGet: &v1.HTTPGetAction{
+					Scheme: v1.URISchemeHTTPS,
+					Port:   intstr.FromInt(int(config.ContainerPort)),
+					Path:   "/readyz",
+				},
+			},
+			PeriodSeconds:    1,
+			SuccessThreshold: 1,
+			FailureThreshold: 30,
+		},
+		Ports: []v1.ContainerPort{{ContainerPort: config.ContainerPort}},
+	}}
+	created, err := c.AppsV1().Deployments(namespace).Create(context.TODO(), deployment, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("creating webhook deployment in namespace %s: %v", namespace, err)
+	}
+	if err := e2edeployment.WaitForDeploymentComplete(c, created); err != nil {
+		return nil, fmt.Errorf("waiting for webhook deployment in namespace %s: %v", namespace, err)
+	}
+
+	_, err = c.CoreV1().Services(namespace).Create(context.TODO(), &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: fixture.serviceName},
+		Spec: v1.ServiceSpec{
+			Selector: podLabels,
+			Ports: []v1.ServicePort{{
+				Protocol:   v1.ProtocolTCP,
+				Port:       config.ServicePort,
+				TargetPort: intstr.FromInt(int(config.ContainerPort)),
+			}},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("creating webhook service in namespace %s: %v", namespace, err)
+	}
+	if err := framework.WaitForServiceEndpointsNum(c, namespace, fixture.serviceName, 1, time.Second, 30*time.Second); err != nil {
+		return nil, fmt.Errorf("waiting for webhook service endpoints in namespace %s: %v", namespace, err)
+	}
+	return fixture, nil
+}
+
+// ClientConfig returns a client config pointing at the given path of the
+// deployed server, with the CA bundle filled in, for use in webhook
+// definitions.
+func (fixture *Fixture) ClientConfig(path string) admissionregistrationv1.WebhookClientConfig {
+	return admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: fixture.namespace,
+			Name:      fixture.serviceName,
+			Path:      &path,
+			Port:      &fixture.servicePort,
+		},
+		CABundle: fixture.caBundle,
+	}
+}
+
+// RegisterValidating creates a ValidatingWebhookConfiguration with the given
+// webhooks (typically built around ClientConfig) and remembers it for
+// Teardown.
+func (fixture *Fixture) RegisterValidating(name string, webhooks ...admissionregistrationv1.ValidatingWebhook) error {
+	_, err := fixture.client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(context.TODO(),
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Webhooks:   webhooks,
+		}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("registering validating webhook configuration %s: %v", name, err)
+	}
+	fixture.validatingConfigs = append(fixture.validatingConfigs, name)
+	return nil
+}
+
+// RegisterMutating is RegisterValidating for mutating webhooks.
+func (fixture *Fixture) RegisterMutating(name string, webhooks ...admissionregistrationv1.MutatingWebhook) error {
+	_, err := fixture.client.AdmissionregistrationV1().MutatingWebhookConfigurations().Create(context.TODO(),
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Webhooks:   webhooks,
+		}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("registering mutating webhook configuration %s: %v", name, err)
+	}
+	fixture.mutatingConfigs = append(fixture.mutatingConfigs, name)
+	return nil
+}
+
+// Teardown removes the registered webhook configurations. They are
+// cluster-scoped and must go before the backing namespace does, or requests
+// matched by a fail-closed webhook start bouncing. The in-namespace backend
+// is left to namespace deletion.
+func (fixture *Fixture) Teardown() {
+	for _, name := range fixture.validatingConfigs {
+		if err := fixture.client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			framework.Logf("Failed to delete validating webhook configuration %s: %v", name, err)
+		}
+	}
+	fixture.validatingConfigs = nil
+	for _, name := range fixture.mutatingConfigs {
+		if err := fixture.client.AdmissionregistrationV1().MutatingWebhookConfigurations().Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			framework.Logf("Failed to delete mutating webhook configuration %s: %v", name, err)
+		}
+	}
+	fixture.mutatingConfigs = nil
+}
+
+// createAuthReaderRoleBinding lets the webhook server's service account read
+// the extension-apiserver-authentication configmap. The fixed name is shared
+// with other webhook fixtures on the cluster, so an existing binding is fine.
+func createAuthReaderRoleBinding(c clientset.Interface, namespace string) error {
+	_, err := c.RbacV1().RoleBindings(metav1.NamespaceSystem).Create(context.TODO(), &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "webhook-auth-reader",
+			Annotations: map[string]string{
+				rbacv1.AutoUpdateAnnotationKey: "true",
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "",
+			Kind:     "Role",
+			Name:     "extension-apiserver-authentication-reader",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      "default",
+				Namespace: namespace,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		framework.Logf("role binding webhook-auth-reader already exists")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("creating webhook auth reader role binding for namespace %s: %v", namespace, err)
+	}
+	return nil
+}
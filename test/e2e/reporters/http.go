@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporters
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// StartProgressServer serves the reporter's state over HTTP so CI infra can
+// observe a long serial run by polling instead of parsing logs. /progress
+// returns the same JSON document that is posted to the progress URL
+// (current spec, counters, namespace counts); /metrics exposes the counters
+// in Prometheus text format. The returned function shuts the server down.
+// In parallel runs every Ginkgo node shares the address, so only the first
+// node to bind it serves; the others log and continue without an endpoint.
+func StartProgressServer(reporter *ProgressReporter, address string) (func(), error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s for progress updates: %v", address, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/progress", func(w http.ResponseWriter, r *http.Request) {
+		reporter.refreshNamespaceCounts()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(reporter.serialize())
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		reporter.refreshNamespaceCounts()
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write([]byte(reporter.metricsText()))
+	})
+	server := &http.Server{Handler: mux}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			klog.Errorf("Progress endpoint on %s failed: %v", address, err)
+		}
+	}()
+	klog.Infof("Serving /progress and /metrics on %s", address)
+	return func() {
+		if err := server.Close(); err != nil {
+			klog.Errorf("Failed to shut down progress endpoint on %s: %v", address, err)
+		}
+	}, nil
+}
+
+// metricsText renders the reporter counters in Prometheus text format.
+func (reporter *ProgressReporter) metricsText() string {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	var b strings.Builder
+	gauge := func(name, help string, value int) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", name, help, name, name, value)
+	}
+	gauge("e2e_tests_started", "Number of specs started so far.", reporter.TestsStarted)
+	gauge("e2e_tests_completed", "Number of specs that passed.", reporter.TestsCompleted)
+	gauge("e2e_tests_failed", "Number of specs that failed.", reporter.TestsFailed)
+	gauge("e2e_tests_skipped", "Number of specs that were skipped.", reporter.TestsSkipped)
+	gauge("e2e_namespaces_active", "Namespaces currently Active in the cluster under test.", reporter.NamespacesActive)
+	gauge("e2e_namespaces_terminating", "Namespaces currently Terminating in the cluster under test.", reporter.NamespacesTerminating)
+	running := 0
+	if reporter.CurrentSpec != "" {
+		running = 1
+	}
+	fmt.Fprintf(&b, "# HELP e2e_spec_running Whether a spec is currently executing; the spec name is in the label.\n# TYPE e2e_spec_running gauge\ne2e_spec_running{spec=%q} %d\n", reporter.CurrentSpec, running)
+	return b.String()
+}
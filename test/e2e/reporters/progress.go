@@ -23,6 +23,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/onsi/ginkgo/v2"
@@ -38,14 +39,26 @@ import (
 type ProgressReporter struct {
 	LastMsg string `json:"msg"`
 
+	// CurrentSpec is the full name of the spec in flight, empty between specs.
+	CurrentSpec string `json:"currentSpec,omitempty"`
+
+	TestsStarted   int `json:"started"`
 	TestsCompleted int `json:"completed"`
 	TestsSkipped   int `json:"skipped"`
 	TestsFailed    int `json:"failed"`
 
+	NamespacesActive      int `json:"namespacesActive"`
+	NamespacesTerminating int `json:"namespacesTerminating"`
+
 	Failures []string `json:"failures,omitempty"`
 
-	progressURL string
-	client      *http.Client
+	// mu guards the exported fields above: ginkgo updates them between specs
+	// while the optional HTTP endpoint reads them at arbitrary times.
+	mu sync.Mutex
+
+	progressURL     string
+	client          *http.Client
+	namespaceCounts func() (active, terminating int, err error)
 }
 
 // NewProgressReporter returns a progress reporter which posts updates to the given URL.
@@ -62,8 +75,31 @@ func NewProgressReporter(progressReportURL string) *ProgressReporter {
 	return rep
 }
 
+// SetNamespaceCountFunc installs a callback used to refresh the namespace
+// counts included in every update. The callback is expected to be cheap; it is
+// invoked synchronously before each update is sent.
+func (reporter *ProgressReporter) SetNamespaceCountFunc(fn func() (active, terminating int, err error)) {
+	reporter.namespaceCounts = fn
+}
+
+func (reporter *ProgressReporter) refreshNamespaceCounts() {
+	if reporter.namespaceCounts == nil {
+		return
+	}
+	active, terminating, err := reporter.namespaceCounts()
+	if err != nil {
+		klog.Errorf("Failed to refresh namespace counts for progress update: %v", err)
+		return
+	}
+	reporter.mu.Lock()
+	reporter.NamespacesActive = active
+	reporter.NamespacesTerminating = terminating
+	reporter.mu.Unlock()
+}
+
 // SendUpdates serializes the current progress and prints it to stdout and also posts it to the configured endpoint if set.
 func (reporter *ProgressReporter) SendUpdates() {
+	reporter.refreshNamespaceCounts()
 	b := reporter.serialize()
 	fmt.Println(string(b))
 	go reporter.postProgressToURL(b)
@@ -97,6 +133,8 @@ func (reporter *ProgressReporter) postProgressToURL(b []byte) {
 }
 
 func (reporter *ProgressReporter) serialize() []byte {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
 	b, err := json.Marshal(reporter)
 	if err != nil {
 		return []byte(fmt.Sprintf(`{"msg":"%v", "error":"%v"}`, reporter.LastMsg, err))
@@ -105,7 +143,25 @@ func (reporter *ProgressReporter) serialize() []byte {
 }
 
 func (reporter *ProgressReporter) SetStartMsg() {
+	reporter.mu.Lock()
 	reporter.LastMsg = "Test Suite starting"
+	reporter.mu.Unlock()
+	reporter.SendUpdates()
+}
+
+// ProcessSpecStart records that a spec has begun executing and sends the state
+// to the configured endpoint if set, so that watchers of a long serial run can
+// see which test is currently in flight.
+func (reporter *ProgressReporter) ProcessSpecStart(report ginkgo.SpecReport) {
+	testName := strings.Join(report.ContainerHierarchyTexts, " ")
+	if len(report.LeafNodeText) > 0 {
+		testName = testName + " " + report.LeafNodeText
+	}
+	reporter.mu.Lock()
+	reporter.TestsStarted++
+	reporter.CurrentSpec = testName
+	reporter.LastMsg = fmt.Sprintf("STARTED %v", testName)
+	reporter.mu.Unlock()
 	reporter.SendUpdates()
 }
 
@@ -115,6 +171,8 @@ func (reporter *ProgressReporter) ProcessSpecReport(report ginkgo.SpecReport) {
 	if len(report.LeafNodeText) > 0 {
 		testName = testName + " " + report.LeafNodeText
 	}
+	reporter.mu.Lock()
+	reporter.CurrentSpec = ""
 	switch report.State {
 	case types.SpecStateFailed:
 		if len(testName) > 0 {
@@ -129,15 +187,20 @@ func (reporter *ProgressReporter) ProcessSpecReport(report ginkgo.SpecReport) {
 		reporter.LastMsg = fmt.Sprintf("PASSED %v", testName)
 	case types.SpecStateSkipped:
 		reporter.TestsSkipped++
+		reporter.mu.Unlock()
 		return
 	default:
+		reporter.mu.Unlock()
 		return
 	}
+	reporter.mu.Unlock()
 
 	reporter.SendUpdates()
 }
 
 func (reporter *ProgressReporter) SetEndMsg() {
+	reporter.mu.Lock()
 	reporter.LastMsg = "Test Suite completed"
+	reporter.mu.Unlock()
 	reporter.SendUpdates()
 }
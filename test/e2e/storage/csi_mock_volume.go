@@ -2365,7 +2365,7 @@ func compareCSICalls(trackedCalls []string, expectedCallSequence []csiCall, getC
 	for i, c := range calls {
 		if i >= len(expectedCallSequence) {
 			// Log all unexpected calls first, return error below outside the loop.
-			framework.Logf("Unexpected CSI driver call: %s (%d)", c.Method, c.FullError)
+			framework.Logf("Unexpected CSI driver call: %s (%d)", c.Method, c.FullError.Code)
 			continue
 		}
 
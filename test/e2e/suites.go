@@ -35,12 +35,17 @@ func CleanupSuite() {
 	// Run on all Ginkgo nodes
 	framework.Logf("Running AfterSuite actions on all nodes")
 	framework.RunCleanupActions()
+	// Each process writes its own share of the run's namespace lifecycle
+	// latencies; conformance tooling aggregates the per-process files from
+	// the results tarball.
+	framework.WriteNamespaceLifecycleReport()
 }
 
 // AfterSuiteActions are actions that are run on ginkgo's SynchronizedAfterSuite
 func AfterSuiteActions() {
 	// Run only Ginkgo on node 1
 	framework.Logf("Running AfterSuite actions on node 1")
+	framework.StopSuiteNamespaceCanary()
 	if framework.TestContext.ReportDir != "" {
 		framework.CoreDump(framework.TestContext.ReportDir)
 	}
@@ -52,6 +57,12 @@ func AfterSuiteActions() {
 	if framework.TestContext.NodeKiller.Enabled {
 		close(framework.TestContext.NodeKiller.NodeKillerStopCh)
 	}
+	if c, err := framework.LoadClientset(); err != nil {
+		framework.Logf("Error loading client for Terminating namespace dump: %v", err)
+	} else {
+		framework.DumpTerminatingNamespaces(c)
+	}
+	framework.LogServiceAccountProvisioningStats()
 }
 
 func gatherTestSuiteMetrics() error {